@@ -0,0 +1,73 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// CordonBudgetSpec defines the desired state of CordonBudget
+type CordonBudgetSpec struct {
+	// Selector selects the nodes of the node group of the budget. An empty selector matches all nodes.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// MaxUnavailable is the maximum number of the selected nodes which can be cordoned simultaneously,
+	// either an absolute number or a percentage of the selected nodes (e.g. "20%"), which is rounded up.
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable intstr.IntOrString `json:"maxUnavailable"`
+}
+
+// CordonBudgetStatus defines the observed state of CordonBudget
+type CordonBudgetStatus struct {
+	// CurrentCordoned is the number of the selected nodes which are cordoned.
+	CurrentCordoned int32 `json:"currentCordoned"`
+
+	// SelectedNodes is the number of the selected nodes.
+	SelectedNodes int32 `json:"selectedNodes"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Max Unavailable",type=string,JSONPath=`.spec.maxUnavailable`
+// +kubebuilder:printcolumn:name="Cordoned",type=integer,JSONPath=`.status.currentCordoned`
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.selectedNodes`
+
+// CordonBudget is the Schema for the cordonbudgets API. Like a PodDisruptionBudget for pods, it limits
+// the number of the nodes of a node group which can be cordoned simultaneously.
+type CordonBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CordonBudgetSpec   `json:"spec,omitempty"`
+	Status CordonBudgetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CordonBudgetList contains a list of CordonBudget
+type CordonBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CordonBudget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CordonBudget{}, &CordonBudgetList{})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Migrating from the ConfigMap policies:
+//
+// A NodeOperationPolicy replaces a policy ConfigMap along with its entry in the "selectors" key of the
+// node-operation-validator-policies ConfigMap. The keys of the ConfigMap map to the fields of the spec as follows:
+//
+//	allowedReasons: "a,b"       -> spec.allowedReasons: [a, b]
+//	reasonRegexPattern: "..."   -> spec.reasonRegexPattern: "..."
+//	forbiddenUsers: "u1,u2"     -> spec.forbiddenUsers: [u1, u2]
+//	warnOnly: "true"            -> spec.warnOnly: true
+//	labelSelector of a selector -> spec.nodeSelector
+//
+// The global node-operation-validator-config ConfigMap maps to a NodeOperationPolicy with an empty nodeSelector,
+// which matches all nodes. The ConfigMaps are only used as long as there are no NodeOperationPolicy objects
+// matching the node, so they can be deleted once the NodeOperationPolicy objects have been created.
+
+// NodeOperationPolicySpec defines the desired state of NodeOperationPolicy
+type NodeOperationPolicySpec struct {
+	// AllowedReasons is the list of reasons allowed in the node.dana.io/reason annotation.
+	// +optional
+	AllowedReasons []string `json:"allowedReasons,omitempty"`
+
+	// ReasonRegexPattern is a regular expression which allowed reasons match.
+	// +optional
+	ReasonRegexPattern string `json:"reasonRegexPattern,omitempty"`
+
+	// ForbiddenUsers are the users which aren't allowed to perform any validated operation.
+	// +optional
+	ForbiddenUsers []string `json:"forbiddenUsers,omitempty"`
+
+	// ForbiddenGroups are the groups whose users aren't allowed to perform any validated operation.
+	// +optional
+	ForbiddenGroups []string `json:"forbiddenGroups,omitempty"`
+
+	// WarnOnly allows denied operations, returning the denial message as an admission warning.
+	// +optional
+	WarnOnly bool `json:"warnOnly,omitempty"`
+
+	// NodeSelector selects the nodes the policy applies to. An empty selector matches all nodes.
+	// +optional
+	NodeSelector metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Scope is the namespace of the team owning the nodes selected by the policy. A scoped policy only applies
+	// to the service accounts of its namespace, and takes precedence over the unscoped policies for them.
+	// An empty scope applies the policy to all users.
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// NodeOperationPolicy is the Schema for the nodeoperationpolicies API
+type NodeOperationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeOperationPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeOperationPolicyList contains a list of NodeOperationPolicy
+type NodeOperationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeOperationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeOperationPolicy{}, &NodeOperationPolicyList{})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduledMaintenanceSpec defines the desired state of ScheduledMaintenance
+type ScheduledMaintenanceSpec struct {
+	// StartTime is the time from which the operations of the maintenance are allowed.
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime is the time until which the operations of the maintenance are allowed.
+	EndTime metav1.Time `json:"endTime"`
+
+	// AllowedOperations are the operations allowed on the nodes during the maintenance.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=delete;cordon;drain;taint
+	AllowedOperations []string `json:"allowedOperations"`
+
+	// AllowedNodes are the names of the nodes, or glob patterns of the names of the nodes (e.g. "gpu-*"),
+	// on which the operations of the maintenance are allowed.
+	// +kubebuilder:validation:MinItems=1
+	AllowedNodes []string `json:"allowedNodes"`
+
+	// Reason is the reason of the operations of the maintenance.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ScheduledMaintenance is the Schema for the scheduledmaintenances API. The operations on the nodes
+// referencing it by the node.dana.io/scheduled-maintenance-id annotation don't require a reason during its window.
+type ScheduledMaintenance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScheduledMaintenanceSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledMaintenanceList contains a list of ScheduledMaintenance
+type ScheduledMaintenanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledMaintenance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledMaintenance{}, &ScheduledMaintenanceList{})
+}
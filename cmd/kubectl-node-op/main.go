@@ -0,0 +1,98 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-node-op is a kubectl plugin annotating nodes with a valid reason before cordoning or deleting them.
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/dana-team/node-operation-validator/internal/nodeop"
+	"github.com/dana-team/node-operation-validator/internal/webhook"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand returns the command of the plugin, with its cordon, uncordon and delete subcommands.
+func newRootCommand() *cobra.Command {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	operator := &nodeop.Operator{}
+
+	root := &cobra.Command{
+		Use:          "kubectl node-op",
+		Short:        "Perform node operations with the reason required by node-operation-validator",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+			if err != nil {
+				return err
+			}
+			if operator.Client, err = kubernetes.NewForConfig(config); err != nil {
+				return err
+			}
+			operator.In, operator.Out = cmd.InOrStdin(), cmd.OutOrStdout()
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file to use")
+	root.PersistentFlags().StringVar(&overrides.CurrentContext, "context", "", "The name of the kubeconfig context to use")
+	root.PersistentFlags().StringVar(&operator.AnnotationKey, "annotation-key", webhook.ReasonAnnotationKey(),
+		"The key of the reason annotation. Defaults to the value of the "+webhook.ReasonAnnotationKeyEnv+" environment variable, if set")
+
+	var cordonOptions nodeop.Options
+	cordon := &cobra.Command{
+		Use:   "cordon NODE",
+		Short: "Annotate the node with a reason and cordon it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operator.Cordon(cmd.Context(), args[0], cordonOptions)
+		},
+	}
+	cordon.Flags().StringVar(&cordonOptions.Reason, "reason", "", "The reason of the cordon. Prompted for if empty")
+	cordon.Flags().BoolVar(&cordonOptions.RemoveAnnotation, "remove-annotation", false, "Remove the reason annotation once the node is cordoned")
+
+	uncordon := &cobra.Command{
+		Use:   "uncordon NODE",
+		Short: "Remove the reason annotation of the node and uncordon it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operator.Uncordon(cmd.Context(), args[0])
+		},
+	}
+
+	var deleteOptions nodeop.Options
+	deleteCommand := &cobra.Command{
+		Use:   "delete NODE",
+		Short: "Annotate the node with a reason and delete it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return operator.Delete(cmd.Context(), args[0], deleteOptions)
+		},
+	}
+	deleteCommand.Flags().StringVar(&deleteOptions.Reason, "reason", "", "The reason of the deletion. Prompted for if empty")
+
+	root.AddCommand(cordon, uncordon, deleteCommand)
+	return root
+}
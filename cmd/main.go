@@ -17,22 +17,34 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
 	nodewebhook "github.com/dana-team/node-operation-validator/internal/webhook"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	// +kubebuilder:scaffold:imports
@@ -41,10 +53,14 @@ import (
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+	// version is the version of the webhook, shown on the status page. It is set at build time
+	// with -ldflags "-X main.version=<version>".
+	version = "dev"
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 
 	if _, doesEnvExist := os.LookupEnv(nodewebhook.ForbiddenUsersEnv); !doesEnvExist {
 		panic(nodewebhook.ForbiddenUsersEnv + " environment variable is not set")
@@ -59,6 +75,14 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var dryRun bool
+	var debugAddr string
+	var statusAddr string
+	var opaURL string
+	var webhookConfigurationName string
+	var useCertManager bool
+	var certManagerCertificate string
+	var certManagerNamespace string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -70,6 +94,26 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	dryRunDefault, _ := strconv.ParseBool(os.Getenv(nodewebhook.DryRunEnv))
+	flag.BoolVar(&dryRun, "dry-run", dryRunDefault,
+		"If set, operations which would be denied are allowed, and the denials are recorded as events. "+
+			"Defaults to the value of the "+nodewebhook.DryRunEnv+" environment variable.")
+	flag.StringVar(&debugAddr, "debug-addr", "", "The address the debug endpoints, such as /explain, bind to. "+
+		"The endpoints aren't protected, so the address must not be exposed. Leave empty to disable them.")
+	flag.StringVar(&statusAddr, "status-addr", ":8083", "The address the /status page binds to when the "+
+		nodewebhook.EnableStatusPageEnv+" environment variable is true.")
+	flag.StringVar(&opaURL, "opa-url", "", "The URL of an OPA decision, e.g. http://localhost:8181/v1/data/nodeoperation, "+
+		"to which the decisions on the operations are delegated instead of the built-in reason validation. Leave empty to disable it.")
+	flag.StringVar(&webhookConfigurationName, "validating-webhook-configuration",
+		"node-operation-validator-validating-webhook-configuration", "The name of the ValidatingWebhookConfiguration "+
+			"whose failure policy is overridden by the failurePolicy key of the ConfigMap. Leave empty to disable the override.")
+	flag.BoolVar(&useCertManager, "use-cert-manager", false, "If set, the webhook server serves the certificate of the Secret "+
+		"of the cert-manager Certificate given by --cert-manager-certificate, and reloads it as soon as cert-manager renews it. "+
+		"The certificate of the webhook certificate directory is served if the Certificate doesn't exist.")
+	flag.StringVar(&certManagerCertificate, "cert-manager-certificate", "node-operation-validator-serving-cert",
+		"The name of the cert-manager Certificate of the webhook server.")
+	flag.StringVar(&certManagerNamespace, "cert-manager-namespace", "",
+		"The namespace of the cert-manager Certificate of the webhook server. Defaults to the namespace of the pod.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -93,8 +137,32 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	minTLSVersion, err := nodewebhook.MinTLSVersion()
+	if err != nil {
+		setupLog.Error(err, "invalid minimum TLS version")
+		os.Exit(1)
+	}
+	setupLog.Info("setting the minimum TLS version of the webhook server", "version", tls.VersionName(minTLSVersion))
+	if minTLSVersion == tls.VersionTLS12 {
+		setupLog.Info("WARNING: the webhook server accepts TLS 1.2, TLS 1.3 is recommended for new deployments, " +
+			"set the " + nodewebhook.MinTLSVersionEnv + " environment variable to TLS13 to require it")
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	webhookTLSOpts := append(slices.Clone(tlsOpts), func(c *tls.Config) {
+		c.MinVersion = minTLSVersion
+	})
+	var certWatcher *nodewebhook.SecretCertWatcher
+	if useCertManager {
+		if certWatcher = setupCertWatcher(restConfig, certManagerNamespace, certManagerCertificate); certWatcher != nil {
+			webhookTLSOpts = append(webhookTLSOpts, func(c *tls.Config) {
+				c.GetCertificate = certWatcher.GetCertificate
+			})
+		}
+	}
+
 	webhookServer := webhook.NewServer(webhook.Options{
-		TLSOpts: tlsOpts,
+		TLSOpts: webhookTLSOpts,
 	})
 
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
@@ -119,13 +187,17 @@ func main() {
 		// this setup is not recommended for production.
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "b6227a88.dana.io",
+		// Secrets and pods are read directly from the API server, so the manager doesn't need to list and watch them.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}, &corev1.Pod{}}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -145,21 +217,156 @@ func main() {
 
 	// +kubebuilder:scaffold:builder
 
+	if certWatcher != nil {
+		if err := mgr.Add(certWatcher); err != nil {
+			setupLog.Error(err, "unable to add the certificate watcher")
+			os.Exit(1)
+		}
+	}
+
+	// The policy read from the environment variables doesn't depend on the API server.
+	var envPolicyResolver nodewebhook.PolicyResolver
+	if nodewebhook.IsEnvConfigSource() {
+		setupLog.Info("reading the policy from the environment variables", "configSource", os.Getenv(nodewebhook.ConfigSourceEnv))
+		if envPolicyResolver, err = nodewebhook.NewEnvPolicyResolver(); err != nil {
+			setupLog.Error(err, "invalid policy")
+			os.Exit(1)
+		}
+	}
+
+	setupLog.Info("setting up webhook server")
+	hookServer := mgr.GetWebhookServer()
+	readinessChecker := &nodewebhook.ConfigMapReadinessChecker{Client: mgr.GetAPIReader()}
+	hookServer.Register("/healthz", nodewebhook.LivenessHandler)
+	if envPolicyResolver != nil {
+		hookServer.Register("/readyz", nodewebhook.LivenessHandler)
+	} else {
+		hookServer.Register("/readyz", readinessChecker)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err := mgr.AddHealthzCheck("webhook", hookServer.StartedChecker()); err != nil {
+		setupLog.Error(err, "unable to set up webhook health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if envPolicyResolver == nil {
+		if err := mgr.AddReadyzCheck("configmap", readinessChecker.Check); err != nil {
+			setupLog.Error(err, "unable to set up ConfigMap ready check")
+			os.Exit(1)
+		}
+	}
+
+	// The policy is read from the API server through a circuit breaker, since the reads of the informer cache
+	// of the manager don't fail when the API server is unavailable.
+	apiReader, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create the client of the policy")
+		os.Exit(1)
+	}
+	policyClient := &nodewebhook.CircuitBreakerClient{Client: apiReader}
+	policyResolver := envPolicyResolver
+	if policyResolver == nil {
+		policyResolver = nodewebhook.NewPolicyResolver(policyClient)
+	}
 
-	setupLog.Info("setting up webhook server")
-	hookServer := mgr.GetWebhookServer()
 	decoder := admission.NewDecoder(scheme)
+	validator := &nodewebhook.NodeValidator{
+		Decoder:        decoder,
+		Client:         mgr.GetClient(),
+		APIReader:      mgr.GetAPIReader(),
+		PolicyResolver: policyResolver,
+		Recorder:       mgr.GetEventRecorderFor("node-operation-validator"),
+		DryRun:         dryRun,
+	}
+	if disableEvents, _ := strconv.ParseBool(os.Getenv(nodewebhook.DisableEventEmissionEnv)); disableEvents {
+		setupLog.Info("event emission is disabled, the events are logged at debug level instead")
+		validator.WithEventEmissionDisabled()
+	}
+	if opaURL != "" {
+		setupLog.Info("delegating the decisions to OPA", "url", opaURL)
+		validator.WithPolicyEvaluator(&nodewebhook.OPARESTEvaluator{URL: opaURL})
+	}
+	if webhookConfigurationName != "" {
+		setupLog.Info("setting up the failure policy controller", "validatingWebhookConfiguration", webhookConfigurationName)
+		if err := (&nodewebhook.FailurePolicyController{
+			Client:                   mgr.GetClient(),
+			Recorder:                 validator.Recorder,
+			WebhookConfigurationName: webhookConfigurationName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up the failure policy controller")
+			os.Exit(1)
+		}
+	}
+	if _, err := mgr.GetRESTMapper().RESTMapping(v1alpha1.GroupVersion.WithKind("CordonBudget").GroupKind()); err != nil {
+		setupLog.Info("the CordonBudget CRD isn't installed, the status of the cordon budgets isn't updated", "error", err.Error())
+	} else {
+		setupLog.Info("setting up the cordon budget controller")
+		if err := (&nodewebhook.CordonBudgetController{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up the cordon budget controller")
+			os.Exit(1)
+		}
+	}
+	if envPolicyResolver == nil {
+		setupLog.Info("validating the ConfigMap of node-operation-validator")
+		if err := validator.ValidateConfig(context.Background()); err != nil {
+			setupLog.Error(err, "invalid ConfigMap")
+			os.Exit(1)
+		}
+	}
+	setupLog.Info("running the self-test of node-operation-validator")
+	if err := validator.SelfTest(context.Background()); err != nil {
+		setupLog.Error(err, "self-test failed")
+		os.Exit(1)
+	}
 	setupLog.Info("registering node-operation-validator to the webhook server")
-	hookServer.Register("/validate-v1-node",
-		&webhook.Admission{Handler: &nodewebhook.NodeValidator{Decoder: decoder, Client: mgr.GetClient()}})
+	hookServer.Register("/validate-v1-node", &webhook.Admission{Handler: validator})
+	setupLog.Info("registering node-operation-mutator to the webhook server")
+	hookServer.Register("/mutate-v1-node",
+		&webhook.Admission{Handler: &nodewebhook.NodeMutator{
+			Decoder:        decoder,
+			Client:         mgr.GetClient(),
+			PolicyResolver: policyResolver,
+			Validator:      validator,
+		}})
+
+	if debugAddr != "" {
+		setupLog.Info("adding the debug server", "address", debugAddr)
+		debugMux := http.NewServeMux()
+		debugMux.Handle("/explain", &nodewebhook.ExplainHandler{Client: mgr.GetClient(), PolicyResolver: policyResolver})
+		if err := mgr.Add(&manager.Server{
+			Name:   "debug",
+			Server: &http.Server{Addr: debugAddr, Handler: debugMux, ReadHeaderTimeout: 10 * time.Second},
+		}); err != nil {
+			setupLog.Error(err, "unable to add the debug server")
+			os.Exit(1)
+		}
+	}
+
+	if nodewebhook.StatusPageEnabled() {
+		setupLog.Info("adding the status page server", "address", statusAddr)
+		statusMux := http.NewServeMux()
+		statusMux.Handle("/status", &nodewebhook.StatusHandler{
+			Validator:       validator,
+			Client:          policyClient,
+			Version:         version,
+			StartTime:       time.Now(),
+			CircuitBreakers: map[string]*nodewebhook.CircuitBreakerClient{"kubernetes-api": policyClient},
+		})
+		if err := mgr.Add(&manager.Server{
+			Name:   "status",
+			Server: &http.Server{Addr: statusAddr, Handler: statusMux, ReadHeaderTimeout: 10 * time.Second},
+		}); err != nil {
+			setupLog.Error(err, "unable to add the status page server")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -167,3 +374,50 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// setupCertWatcher returns a watcher serving the certificate of the Secret of the cert-manager Certificate, loaded
+// from the Secret, or nil if the Certificate doesn't exist, in which case the certificate directory is served.
+func setupCertWatcher(restConfig *rest.Config, namespace string, certificateName string) *nodewebhook.SecretCertWatcher {
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+	reader, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create the client reading the cert-manager Certificate")
+		os.Exit(1)
+	}
+	secretName, ok, err := nodewebhook.CertificateSecretName(context.Background(), reader, namespace, certificateName)
+	if err != nil {
+		setupLog.Error(err, "unable to read the cert-manager Certificate")
+		os.Exit(1)
+	}
+	if !ok {
+		setupLog.Info("the cert-manager Certificate doesn't exist, serving the certificate of the certificate directory",
+			"certificate", namespace+"/"+certificateName)
+		return nil
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create the client watching the certificate Secret")
+		os.Exit(1)
+	}
+	certWatcher := &nodewebhook.SecretCertWatcher{Client: clientset, Namespace: namespace, Name: secretName}
+	if err := certWatcher.Load(context.Background()); err != nil {
+		setupLog.Error(err, "unable to load the certificate of the cert-manager Certificate")
+		os.Exit(1)
+	}
+	setupLog.Info("serving the certificate of the cert-manager Certificate", "certificate", namespace+"/"+certificateName,
+		"secret", namespace+"/"+secretName)
+	return certWatcher
+}
+
+// podNamespace returns the namespace of the pod, read from its service account token mount.
+func podNamespace() string {
+	namespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		setupLog.Error(err, "unable to read the namespace of the pod, set --cert-manager-namespace")
+		os.Exit(1)
+	}
+	return strings.TrimSpace(string(namespace))
+}
@@ -0,0 +1,163 @@
+// Package nodeop performs node operations along with the reason annotation which the webhook requires,
+// for the kubectl node-op plugin.
+package nodeop
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/dana-team/node-operation-validator/internal/webhook"
+)
+
+// Operator annotates nodes with a reason and performs the operations on them.
+type Operator struct {
+	Client kubernetes.Interface
+	// In is read for the reason when none is given, and Out receives the prompts and the progress.
+	In  io.Reader
+	Out io.Writer
+	// AnnotationKey is the key of the reason annotation.
+	AnnotationKey string
+}
+
+// Options are the options of an operation.
+type Options struct {
+	// Reason is the reason of the operation. It is prompted for if it is empty.
+	Reason string
+	// RemoveAnnotation removes the reason annotation once the operation succeeded.
+	RemoveAnnotation bool
+}
+
+// Cordon annotates the node with the reason and cordons it.
+func (o *Operator) Cordon(ctx context.Context, nodeName string, options Options) error {
+	reason, err := o.resolveReason(ctx, webhook.Cordon, nodeName, options.Reason)
+	if err != nil {
+		return err
+	}
+	if err := o.patch(ctx, nodeName, map[string]any{"metadata": map[string]any{"annotations": map[string]any{o.AnnotationKey: reason}}}); err != nil {
+		return fmt.Errorf("failed to annotate node %q: %w", nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s annotated\n", nodeName)
+	if err := o.patch(ctx, nodeName, map[string]any{"spec": map[string]any{"unschedulable": true}}); err != nil {
+		return fmt.Errorf("failed to cordon node %q: %w", nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s cordoned\n", nodeName)
+	if !options.RemoveAnnotation {
+		return nil
+	}
+	if err := o.patch(ctx, nodeName, removeAnnotationPatch(o.AnnotationKey)); err != nil {
+		return fmt.Errorf("failed to remove the %q annotation of node %q: %w", o.AnnotationKey, nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s annotation %q removed\n", nodeName, o.AnnotationKey)
+	return nil
+}
+
+// Uncordon uncordons the node. The webhook denies the uncordons of nodes keeping the reason annotation,
+// so the annotation is removed along with the uncordon and no reason is needed.
+func (o *Operator) Uncordon(ctx context.Context, nodeName string) error {
+	patch := removeAnnotationPatch(o.AnnotationKey)
+	patch["spec"] = map[string]any{"unschedulable": nil}
+	if err := o.patch(ctx, nodeName, patch); err != nil {
+		return fmt.Errorf("failed to uncordon node %q: %w", nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s uncordoned\n", nodeName)
+	return nil
+}
+
+// Delete annotates the node with the reason and deletes it.
+func (o *Operator) Delete(ctx context.Context, nodeName string, options Options) error {
+	reason, err := o.resolveReason(ctx, webhook.Delete, nodeName, options.Reason)
+	if err != nil {
+		return err
+	}
+	if err := o.patch(ctx, nodeName, map[string]any{"metadata": map[string]any{"annotations": map[string]any{o.AnnotationKey: reason}}}); err != nil {
+		return fmt.Errorf("failed to annotate node %q: %w", nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s annotated\n", nodeName)
+	if err := o.Client.CoreV1().Nodes().Delete(ctx, nodeName, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete node %q: %w", nodeName, err)
+	}
+	fmt.Fprintf(o.Out, "node/%s deleted\n", nodeName)
+	return nil
+}
+
+// resolveReason returns the given reason, or prompts for one if it is empty, and validates it against the policy
+// of the global ConfigMap. The validation is skipped if the ConfigMap can't be read, e.g. because the user isn't
+// allowed to, since the webhook validates the reason anyway.
+func (o *Operator) resolveReason(ctx context.Context, operation webhook.Operation, nodeName string, reason string) (string, error) {
+	policy, err := o.policy(ctx)
+	if err != nil {
+		fmt.Fprintf(o.Out, "Warning: the reason isn't validated locally: %s\n", err)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		if reason, err = o.promptReason(operation, nodeName, policy); err != nil {
+			return "", err
+		}
+	}
+	if policy == nil {
+		return reason, nil
+	}
+	if err := policy.CheckReason(operation, reason); err != nil {
+		return "", fmt.Errorf("invalid reason: %w", err)
+	}
+	return reason, nil
+}
+
+// policy fetches the global ConfigMap and parses its policy.
+func (o *Operator) policy(ctx context.Context) (*webhook.Policy, error) {
+	configMap, err := o.Client.CoreV1().ConfigMaps(webhook.ConfigMapNamespace).Get(ctx, webhook.ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ConfigMap %s/%s doesn't exist", webhook.ConfigMapNamespace, webhook.ConfigMapName)
+		}
+		return nil, fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", webhook.ConfigMapNamespace, webhook.ConfigMapName, err)
+	}
+	policy, err := webhook.ParsePolicy(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// promptReason reads the reason of the operation from the input, listing the allowed reasons of the policy if any.
+func (o *Operator) promptReason(operation webhook.Operation, nodeName string, policy *webhook.Policy) (string, error) {
+	if policy != nil && len(policy.AllowedReasons) > 0 {
+		fmt.Fprintf(o.Out, "Allowed reasons: %s\n", strings.Join(policy.AllowedReasons, ", "))
+	}
+	fmt.Fprintf(o.Out, "Reason for the %s of node %q: ", operation, nodeName)
+	line, err := bufio.NewReader(o.In).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the reason: %w", err)
+	}
+	reason := strings.TrimSpace(line)
+	if reason == "" {
+		return "", errors.New("a reason is required")
+	}
+	return reason, nil
+}
+
+// patch applies the JSON merge patch to the node.
+func (o *Operator) patch(ctx context.Context, nodeName string, patch map[string]any) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = o.Client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+// removeAnnotationPatch returns a JSON merge patch removing the annotation.
+func removeAnnotationPatch(key string) map[string]any {
+	return map[string]any{"metadata": map[string]any{"annotations": map[string]any{key: nil}}}
+}
@@ -0,0 +1,115 @@
+package nodeop
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/dana-team/node-operation-validator/internal/webhook"
+)
+
+const (
+	nodeName         = "worker-1"
+	reasonAnnotation = "node.dana.io/reason"
+)
+
+// newOperator returns an operator of a fake cluster holding the node and the objects, reading the input.
+func newOperator(input string, objects ...runtime.Object) (*Operator, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	objects = append(objects, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})
+	return &Operator{Client: fake.NewSimpleClientset(objects...), In: strings.NewReader(input), Out: out, AnnotationKey: reasonAnnotation}, out
+}
+
+// newConfigMap returns the global ConfigMap allowing the reasons.
+func newConfigMap(allowedReasons string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: webhook.ConfigMapName, Namespace: webhook.ConfigMapNamespace},
+		Data:       map[string]string{"allowedReasons": allowedReasons},
+	}
+}
+
+func TestCordon(t *testing.T) {
+	tests := []struct {
+		name             string
+		configMap        *corev1.ConfigMap
+		options          Options
+		input            string
+		valid            bool
+		annotation       string
+		output           string
+		removeAnnotation bool
+	}{
+		{name: "ReasonFlag", configMap: newConfigMap("Testing"), options: Options{Reason: "Testing"}, valid: true, annotation: "Testing"},
+		{name: "PromptedReason", configMap: newConfigMap("Testing,Upgrade"), input: "Upgrade\n", valid: true, annotation: "Upgrade",
+			output: "Allowed reasons: Testing, Upgrade"},
+		{name: "EmptyPromptedReason", configMap: newConfigMap("Testing"), input: "\n", valid: false},
+		{name: "InvalidReason", configMap: newConfigMap("Testing"), options: Options{Reason: "for fun"}, valid: false},
+		{name: "WithoutConfigMap", options: Options{Reason: "for fun"}, valid: true, annotation: "for fun", output: "isn't validated locally"},
+		{name: "RemoveAnnotation", configMap: newConfigMap("Testing"), options: Options{Reason: "Testing", RemoveAnnotation: true}, valid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			var objects []runtime.Object
+			if test.configMap != nil {
+				objects = append(objects, test.configMap)
+			}
+			operator, out := newOperator(test.input, objects...)
+
+			err := operator.Cordon(ctx, nodeName, test.options)
+			node, getErr := operator.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			g.Expect(getErr).ShouldNot(HaveOccurred())
+			g.Expect(out.String()).Should(ContainSubstring(test.output))
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				g.Expect(node.Spec.Unschedulable).Should(BeFalse())
+				g.Expect(node.Annotations).ShouldNot(HaveKey(reasonAnnotation))
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(node.Spec.Unschedulable).Should(BeTrue())
+			if test.annotation == "" {
+				g.Expect(node.Annotations).ShouldNot(HaveKey(reasonAnnotation))
+			} else {
+				g.Expect(node.Annotations).Should(HaveKeyWithValue(reasonAnnotation, test.annotation))
+			}
+		})
+	}
+}
+
+func TestUncordon(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	operator, _ := newOperator("", newConfigMap("Testing"))
+	g.Expect(operator.Cordon(ctx, nodeName, Options{Reason: "Testing"})).Should(Succeed())
+
+	g.Expect(operator.Uncordon(ctx, nodeName)).Should(Succeed())
+	node, err := operator.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(node.Spec.Unschedulable).Should(BeFalse())
+	g.Expect(node.Annotations).ShouldNot(HaveKey(reasonAnnotation))
+}
+
+func TestDelete(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	operator, _ := newOperator("", newConfigMap("Testing"))
+
+	g.Expect(operator.Delete(ctx, nodeName, Options{Reason: "for fun"})).ShouldNot(Succeed())
+	_, err := operator.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	g.Expect(operator.Delete(ctx, nodeName, Options{Reason: "Testing"})).Should(Succeed())
+	_, err = operator.Client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+}
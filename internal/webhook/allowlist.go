@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// groupPrefix marks an operation allowlist entry of a group rather than a user.
+const groupPrefix = "group:"
+
+// parseOperationAllowlist parses semicolon separated user=operations entries, where the operations are comma
+// separated, e.g. "alice=cordon,uncordon;group:sre=delete,cordon". Groups are prefixed with "group:".
+func parseOperationAllowlist(value string) (map[string][]Operation, error) {
+	allowlist := make(map[string][]Operation)
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		subject, operations, found := strings.Cut(entry, "=")
+		subject = strings.TrimSpace(subject)
+		if !found || subject == "" {
+			return nil, fmt.Errorf("expected user=operations, got %q", entry)
+		}
+		for _, operation := range strings.Split(operations, ",") {
+			if operation = strings.TrimSpace(operation); operation != "" {
+				allowlist[subject] = append(allowlist[subject], Operation(operation))
+			}
+		}
+	}
+	return allowlist, nil
+}
+
+// getAllowedOperationsForUser returns the operations the user is allowed to perform, according to the entries
+// of the user and of its groups. It returns false if there is no entry for the user nor for any of its groups.
+func getAllowedOperationsForUser(allowlist map[string][]Operation, user string, groups []string) ([]Operation, bool) {
+	operations, hasEntry := allowlist[user]
+	operations = slices.Clone(operations)
+	for _, group := range groups {
+		if groupOperations, ok := allowlist[groupPrefix+group]; ok {
+			hasEntry = true
+			operations = append(operations, groupOperations...)
+		}
+	}
+	return operations, hasEntry
+}
+
+// isOperationAllowedForUser checks if the operation is in the allowlist of the user.
+// Users without an entry are allowed to perform any operation.
+func isOperationAllowedForUser(allowlist map[string][]Operation, user string, groups []string, operation Operation) bool {
+	operations, hasEntry := getAllowedOperationsForUser(allowlist, user, groups)
+	return !hasEntry || slices.Contains(operations, operation)
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestOperationAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		groups  []string
+		delete  bool
+		allowed bool
+	}{
+		{name: "UserCordonInAllowlist", user: "alice", delete: false, allowed: true},
+		{name: "UserDeleteNotInAllowlist", user: "alice", delete: true, allowed: false},
+		{name: "UserWithoutEntry", user: regularUserExample, delete: true, allowed: true},
+		{name: "GroupDeleteInAllowlist", user: "alice", groups: []string{"sre"}, delete: true, allowed: true},
+		{name: "ServiceAccountBypass", user: serviceAccountUser + "default:alice", delete: true, allowed: true},
+	}
+
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g := NewWithT(t)
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data: map[string]string{
+			allowedReasonsKey:     "Testing",
+			operationAllowlistKey: "alice=cordon,uncordon;group:sre=delete;" + serviceAccountUser + "default:alice=cordon",
+		},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+
+			request := newCordonRequest(g, test.name, test.user, annotations)
+			if test.delete {
+				request = newDeleteRequest(g, test.name, test.user, annotations)
+			}
+			request.UserInfo.Groups = test.groups
+
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
+
+func TestParseOperationAllowlist(t *testing.T) {
+	g := NewWithT(t)
+
+	allowlist, err := parseOperationAllowlist("alice=cordon, uncordon; bob=delete;")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(allowlist).Should(Equal(map[string][]Operation{"alice": {Cordon, Uncordon}, "bob": {Delete}}))
+
+	_, err = parseOperationAllowlist("alice")
+	g.Expect(err).Should(HaveOccurred())
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	attestedByAnnotation = "node.dana.io/reason-attested-by"
+	serviceAccountsGroup = "system:serviceaccounts"
+)
+
+// validateReasonAttestation denies an approved operation unless the reason is attested by a service account, other
+// than the requesting user, which is allowed to update nodes. In warn only mode, the denial message is added to the
+// warnings of the given response.
+func (n *NodeValidator) validateReasonAttestation(ctx context.Context, node *corev1.Node, user string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	attester, ok := node.Annotations[attestedByAnnotation]
+	if !ok {
+		log.Info("Reason attestation denied", "DenialReason", "attestation annotation doesn't exist", "User", user)
+		return denyApproved(policy, response, DenialDetail{
+			Code:    MissingAttestationCode,
+			User:    user,
+			Message: fmt.Sprintf("You must add %q annotation with the service account attesting the reason", attestedByAnnotation),
+		})
+	}
+
+	namespace, ok := serviceAccountNamespace(attester, policy.serviceAccountPrefix())
+	if !ok {
+		log.Info("Reason attestation denied", "DenialReason", "attester is not a service account", "User", user, "Attester", attester)
+		return denyApproved(policy, response, DenialDetail{
+			Code:    InvalidAttestationCode,
+			User:    user,
+			Message: fmt.Sprintf("The %q annotation must be a service account of the form %s<namespace>:<name>", attestedByAnnotation, policy.serviceAccountPrefix()),
+		})
+	}
+	if attester == user {
+		log.Info("Reason attestation denied", "DenialReason", "self attestation", "User", user)
+		return denyApproved(policy, response, DenialDetail{
+			Code:    InvalidAttestationCode,
+			User:    user,
+			Message: "The reason must be attested by a service account other than the requesting user",
+		})
+	}
+
+	allowed, err := n.canUpdateNodes(ctx, attester, namespace)
+	if err != nil {
+		log.Error(err, "Failed to review the access of the attester", "Attester", attester)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to review the access of %q: %w", attester, err))
+	}
+	if !allowed {
+		log.Info("Reason attestation denied", "DenialReason", "attester is not allowed to update nodes", "User", user, "Attester", attester)
+		return denyApproved(policy, response, DenialDetail{
+			Code:    InvalidAttestationCode,
+			User:    user,
+			Message: fmt.Sprintf("%q is not allowed to update nodes, so it cannot attest the reason", attester),
+		})
+	}
+	return response
+}
+
+// canUpdateNodes checks if the service account is allowed to update nodes, using a SubjectAccessReview.
+func (n *NodeValidator) canUpdateNodes(ctx context.Context, serviceAccount string, namespace string) (bool, error) {
+	review := authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   serviceAccount,
+			Groups: []string{serviceAccountsGroup, serviceAccountsGroup + ":" + namespace},
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "update",
+				Resource: "nodes",
+			},
+		},
+	}
+	if err := n.Client.Create(ctx, &review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// serviceAccountNamespace returns the namespace of a service account user of the form
+// <prefix><namespace>:<name>, and false if the user isn't of that form.
+func serviceAccountNamespace(user string, prefix string) (string, bool) {
+	key, ok := serviceAccountKey(user, prefix)
+	return key.Namespace, ok
+}
+
+// serviceAccountKey returns the key of the ServiceAccount of a service account user of the form
+// <prefix><namespace>:<name>, and false if the user isn't of that form.
+func serviceAccountKey(user string, prefix string) (client.ObjectKey, bool) {
+	if !isServiceAccount(user, prefix) {
+		return client.ObjectKey{}, false
+	}
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(user, prefix), ":")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, ":") {
+		return client.ObjectKey{}, false
+	}
+	return client.ObjectKey{Namespace: namespace, Name: name}, true
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const trustedServiceAccount = serviceAccountUser + "automation:node-reasoner"
+
+// newAccessReviewClient returns a fake client which allows only the trusted service account to update nodes.
+func newAccessReviewClient(objects ...client.Object) client.Client {
+	return testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if review, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+				review.Status.Allowed = review.Spec.User == trustedServiceAccount && review.Spec.ResourceAttributes.Verb == "update"
+				return nil
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+}
+
+func TestReasonAttestation(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       string
+		attestedBy string
+		allowed    bool
+	}{
+		{name: "TrustedAttester", user: regularUserExample, attestedBy: trustedServiceAccount, allowed: true},
+		{name: "MissingAttestation", user: regularUserExample, attestedBy: "", allowed: false},
+		{name: "AttesterNotServiceAccount", user: regularUserExample, attestedBy: "bob", allowed: false},
+		{name: "UntrustedAttester", user: regularUserExample, attestedBy: serviceAccountUser + "default:default", allowed: false},
+		{name: "ServiceAccountBypass", user: trustedServiceAccount, attestedBy: trustedServiceAccount, allowed: true},
+	}
+
+	fakeClient := newAccessReviewClient(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", reasonAttestationKey: "true"},
+	})
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.attestedBy != "" {
+				annotations[attestedByAnnotation] = test.attestedBy
+			}
+
+			response := nv.Handle(context.Background(), newDeleteRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
+
+func TestServiceAccountNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	namespace, ok := serviceAccountNamespace(trustedServiceAccount, serviceAccountUser)
+	g.Expect(ok).Should(BeTrue())
+	g.Expect(namespace).Should(Equal("automation"))
+
+	for _, user := range []string{regularUserExample, serviceAccountUser + "automation", serviceAccountUser + ":name", serviceAccountUser + "a:b:c"} {
+		_, ok = serviceAccountNamespace(user, serviceAccountUser)
+		g.Expect(ok).Should(BeFalse(), user)
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AuditViolation is a node whose current state would not have been admitted under the current policy.
+type AuditViolation struct {
+	NodeName  string
+	Operation Operation
+	Message   string
+}
+
+// Audit lists all the nodes in the cluster and returns the violations of the ones whose current state would not
+// have been admitted under the current policy, e.g. nodes which bypassed the webhook or which were cordoned
+// before the policy was enacted.
+func (n *NodeValidator) Audit(ctx context.Context) ([]AuditViolation, error) {
+	logger := log.FromContext(ctx).WithName("Node Audit")
+
+	nodes := corev1.NodeList{}
+	if err := n.Client.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var violations []AuditViolation
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		policy, err := n.resolvePolicy(ctx, node, logger.WithValues("node", node.Name), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve policy of node %q: %w", node.Name, err)
+		}
+		if violation, ok := auditNode(node, policy); ok {
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+// auditNode checks the current state of the node against the policy. A cordoned node, or a node with a monitored
+// taint, must have a valid reason annotation unless the policy removes it after a delay, and any other node
+// must not have a reason annotation.
+func auditNode(node *corev1.Node, policy Policy) (AuditViolation, bool) {
+	reasonMessage, doesReasonExist := node.Annotations[ReasonAnnotationKey()]
+
+	operation := Uncordon
+	if node.Spec.Unschedulable {
+		operation = Cordon
+	} else if added, _ := monitoredTaintChanges(nil, node.Spec.Taints, policy.MonitoredTaints); added {
+		operation = TaintAdd
+	}
+
+	switch {
+	case operation == Uncordon && doesReasonExist:
+		return AuditViolation{NodeName: node.Name, Operation: operation, Message: fmt.Sprintf("Node is schedulable but has the %q annotation", ReasonAnnotationKey())}, true
+
+	case operation == Uncordon:
+		return AuditViolation{}, false
+
+	case !doesReasonExist && policy.ReasonAnnotationAutoDeleteAfter > 0:
+		// The reason annotation of the approved operation may have been removed after the delay of the policy.
+		return AuditViolation{}, false
+
+	case !doesReasonExist:
+		return AuditViolation{NodeName: node.Name, Operation: operation, Message: fmt.Sprintf("Node was %sed without the %q annotation", operation, ReasonAnnotationKey())}, true
+
+	default:
+		if code, message := validateReason(policy, reasonMessage); code != "" {
+			return AuditViolation{NodeName: node.Name, Operation: operation, Message: message}, true
+		}
+		return AuditViolation{}, false
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestAudit(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", monitoredTaintsKey: monitoredTaintExample},
+	})).Should(Succeed())
+
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "schedulable"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "schedulable-with-reason", Annotations: map[string]string{reasonAnnotation: "Testing"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned-with-reason", Annotations: map[string]string{reasonAnnotation: "Testing"}}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned-without-reason"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned-with-invalid-reason", Annotations: map[string]string{reasonAnnotation: "for fun"}}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "tainted-without-reason"}, Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: monitoredTaintExample, Effect: corev1.TaintEffectNoSchedule}}}},
+	}
+	for i := range nodes {
+		g.Expect(fakeClient.Create(ctx, &nodes[i])).Should(Succeed())
+	}
+
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+	violations, err := nv.Audit(ctx)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	violatingNodes := make(map[string]Operation)
+	for _, violation := range violations {
+		violatingNodes[violation.NodeName] = violation.Operation
+	}
+	g.Expect(violatingNodes).Should(Equal(map[string]Operation{
+		"schedulable-with-reason":      Uncordon,
+		"cordoned-without-reason":      Cordon,
+		"cordoned-with-invalid-reason": Cordon,
+		"tainted-without-reason":       TaintAdd,
+	}))
+}
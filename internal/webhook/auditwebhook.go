@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// auditWebhookSecretKey is the key of the HMAC key in the Secret referenced by the policy.
+	auditWebhookSecretKey = "hmacKey"
+	// auditWebhookSignatureHeader holds the hex-encoded HMAC-SHA256 of the payload, prefixed with "sha256=".
+	auditWebhookSignatureHeader = "X-Signature-256"
+	// auditEventComponent is the source component of the events sent to the audit webhook.
+	auditEventComponent = "node-operation-validator"
+	// defaultAuditWebhookFlushInterval is the default maximum time a decision waits in a batch before it is sent.
+	defaultAuditWebhookFlushInterval = 10 * time.Second
+)
+
+// forwardDecision adds the decision event on the node to the batch of the audit webhook of the policy. The batch
+// is sent once it holds AuditWebhookBatchSize events, or once its oldest event is AuditWebhookFlushInterval old.
+func (n *NodeValidator) forwardDecision(node *corev1.Node, eventType, reason, message string, policy Policy) {
+	now := metav1.NewTime(n.now())
+	event := corev1.Event{
+		TypeMeta: metav1.TypeMeta{Kind: "Event", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%v.%x", node.Name, now.UnixNano()),
+			Namespace:         metav1.NamespaceDefault,
+			CreationTimestamp: now,
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: node.Name, UID: node.UID},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: auditEventComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	n.auditBatches.add(event, policy, func(events []corev1.Event) {
+		go n.sendAuditEvents(policy, events)
+	})
+}
+
+// sendAuditEvents posts the events as an EventList to the audit webhook of the policy. The events are dropped
+// if they can't be sent.
+func (n *NodeValidator) sendAuditEvents(policy Policy, events []corev1.Event) {
+	logger := log.Log.WithName("Node Webhook")
+	if err := n.postAuditEvents(context.Background(), policy, events); err != nil {
+		logger.Error(err, "Failed to send the decisions to the audit webhook, they are dropped", "URL", policy.AuditWebhookURL, "Decisions", len(events))
+	}
+}
+
+// postAuditEvents posts the events as an EventList to the audit webhook of the policy, signed with the HMAC key of
+// the Secret referenced by the policy if any.
+func (n *NodeValidator) postAuditEvents(ctx context.Context, policy Policy, events []corev1.Event) error {
+	payload, err := json.Marshal(corev1.EventList{TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "v1"}, Items: events})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, policy.AuditWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	headers, err := n.externalAPIHeaders(ctx, policy)
+	if err != nil {
+		return err
+	}
+	setHeaders(request, headers)
+	request.Header.Set("Content-Type", "application/json")
+	if policy.AuditWebhookSecretRef != "" {
+		key, err := getSecretValue(ctx, n.Client, policy.AuditWebhookSecretRef, auditWebhookSecretKey)
+		if err != nil {
+			return err
+		}
+		request.Header.Set(auditWebhookSignatureHeader, "sha256="+signAuditPayload(key, payload))
+	}
+
+	httpClient, err := n.externalHTTPClient(ctx, policy)
+	if err != nil {
+		return err
+	}
+	httpResponse, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %q from %s", httpResponse.Status, policy.AuditWebhookURL)
+	}
+	return nil
+}
+
+// signAuditPayload returns the hex-encoded HMAC-SHA256 of the payload with the key.
+func signAuditPayload(key, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditBatcher keeps the events waiting to be sent to the audit webhooks in memory by URL, so the events of
+// a batch are lost if the webhook stops before the batch is sent. Its zero value is ready to use.
+type auditBatcher struct {
+	mu      sync.Mutex
+	batches map[string]*auditBatch
+}
+
+// auditBatch holds the events waiting to be sent to an audit webhook, and the timer sending them.
+type auditBatch struct {
+	events []corev1.Event
+	timer  *time.Timer
+}
+
+// add adds the event to the batch of the audit webhook of the policy, and calls send with the events of the batch
+// once it is full or once the flush interval of the policy elapsed since its first event.
+func (b *auditBatcher) add(event corev1.Event, policy Policy, send func([]corev1.Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.batches == nil {
+		b.batches = make(map[string]*auditBatch)
+	}
+
+	url := policy.AuditWebhookURL
+	batch, ok := b.batches[url]
+	if !ok {
+		batch = &auditBatch{}
+		b.batches[url] = batch
+	}
+	batch.events = append(batch.events, event)
+	if len(batch.events) >= policy.AuditWebhookBatchSize {
+		if batch.timer != nil {
+			batch.timer.Stop()
+		}
+		delete(b.batches, url)
+		send(batch.events)
+		return
+	}
+	if batch.timer == nil {
+		flushInterval := policy.AuditWebhookFlushInterval
+		if flushInterval <= 0 {
+			flushInterval = defaultAuditWebhookFlushInterval
+		}
+		batch.timer = time.AfterFunc(flushInterval, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.batches[url] != batch {
+				return
+			}
+			delete(b.batches, url)
+			send(batch.events)
+		})
+	}
+}
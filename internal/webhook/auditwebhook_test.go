@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// auditWebhookServer is an audit webhook recording the payloads it receives along with their signatures.
+type auditWebhookServer struct {
+	mu         sync.Mutex
+	payloads   [][]byte
+	signatures []string
+}
+
+func (s *auditWebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payloads = append(s.payloads, payload)
+	s.signatures = append(s.signatures, r.Header.Get(auditWebhookSignatureHeader))
+}
+
+func (s *auditWebhookServer) received() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.payloads)
+}
+
+func TestAuditWebhook(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	hmacKey := []byte("audit-key")
+	receiver := &auditWebhookServer{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit-webhook", Namespace: cmNamespace},
+		Data:       map[string][]byte{auditWebhookSecretKey: hmacKey},
+	})).Should(Succeed())
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data: map[string]string{allowedReasonsKey: "Testing", auditWebhookURLKey: server.URL, auditBatchSizeKey: "2",
+			auditFlushIntervalKey: "3600", auditSecretKey: "audit-webhook"},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, HTTPClient: server.Client()}
+
+	// The decisions are sent once the batch is full.
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"})).Allowed).Should(BeTrue())
+	g.Consistently(receiver.received, 100*time.Millisecond).Should(BeZero())
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-2", regularUserExample, map[string]string{reasonAnnotation: "Invalid"})).Allowed).Should(BeFalse())
+	g.Eventually(receiver.received).Should(Equal(1))
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	g.Expect(receiver.signatures[0]).Should(Equal("sha256=" + signAuditPayload(hmacKey, receiver.payloads[0])))
+	events := corev1.EventList{}
+	g.Expect(json.Unmarshal(receiver.payloads[0], &events)).Should(Succeed())
+	g.Expect(events.Kind).Should(Equal("EventList"))
+	g.Expect(events.Items).Should(HaveLen(2))
+	g.Expect(events.Items[0].InvolvedObject.Name).Should(Equal("node-1"))
+	g.Expect(events.Items[0].Reason).Should(Equal(operationApprovedEvent))
+	g.Expect(events.Items[0].Type).Should(Equal(corev1.EventTypeNormal))
+	g.Expect(events.Items[1].InvolvedObject.Name).Should(Equal("node-2"))
+	g.Expect(events.Items[1].Reason).Should(Equal(operationDeniedEvent))
+	g.Expect(events.Items[1].Type).Should(Equal(corev1.EventTypeWarning))
+}
+
+func TestAuditWebhookFlushInterval(t *testing.T) {
+	g := NewWithT(t)
+	receiver := &auditWebhookServer{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	nv := NodeValidator{Client: newFakeClient(), HTTPClient: server.Client()}
+	policy := Policy{AuditWebhookURL: server.URL, AuditWebhookBatchSize: 10, AuditWebhookFlushInterval: 50 * time.Millisecond}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	nv.forwardDecision(node, corev1.EventTypeNormal, operationApprovedEvent, "approved", policy)
+	nv.forwardDecision(node, corev1.EventTypeNormal, operationApprovedEvent, "approved", policy)
+	g.Eventually(receiver.received).Should(Equal(1))
+	g.Consistently(receiver.received, 100*time.Millisecond).Should(Equal(1))
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	g.Expect(receiver.signatures[0]).Should(BeEmpty())
+	events := corev1.EventList{}
+	g.Expect(json.Unmarshal(receiver.payloads[0], &events)).Should(Succeed())
+	g.Expect(events.Items).Should(HaveLen(2))
+}
+
+func TestAuditWebhookUnbatched(t *testing.T) {
+	g := NewWithT(t)
+	receiver := &auditWebhookServer{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	nv := NodeValidator{Client: newFakeClient(), HTTPClient: server.Client()}
+	policy := Policy{AuditWebhookURL: server.URL}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	nv.forwardDecision(node, corev1.EventTypeNormal, operationApprovedEvent, "approved", policy)
+	nv.forwardDecision(node, corev1.EventTypeNormal, operationApprovedEvent, "approved", policy)
+	g.Eventually(receiver.received).Should(Equal(2))
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	changeRequestAnnotation = "node.dana.io/change-request"
+	bulkCordonEvent         = "NodeOperationBulkCordon"
+)
+
+// validateBulkCordon detects the bulk cordons: when a user cordons more nodes than the threshold of the policy within
+// its window, such as with "kubectl cordon -l", the cordons beyond the threshold require the change request annotation.
+// The first cordon of the bulk is recorded as a single event, in place of the approval events of the cordons of
+// the bulk, which are still forwarded to the audit webhook. It returns true if the cordon is part of a bulk.
+// The service accounts of the trusted namespaces are exempt.
+func (n *NodeValidator) validateBulkCordon(node *corev1.Node, user string, policy Policy, log logr.Logger, dryRun bool, response admission.Response) (admission.Response, bool) {
+	if !response.Allowed || policy.BulkCordonThreshold <= 0 || policy.BulkCordonWindow <= 0 ||
+		isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		return response, false
+	}
+
+	count, firstOfBulk := n.bulkCordons.record(user, policy.BulkCordonThreshold, policy.BulkCordonWindow, n.now(), !dryRun)
+	if count <= policy.BulkCordonThreshold {
+		return response, false
+	}
+	if firstOfBulk && !dryRun && n.Recorder != nil {
+		n.Recorder.Eventf(node, corev1.EventTypeWarning, bulkCordonEvent, "%q cordoned %d nodes within %s: the cordons beyond %d are a bulk cordon "+
+			"requiring the %q annotation, whose approvals aren't recorded as individual events", user, count, policy.BulkCordonWindow,
+			policy.BulkCordonThreshold, changeRequestAnnotation)
+	}
+	if node.Annotations[changeRequestAnnotation] != "" {
+		log.Info("Bulk cordon", "Node", node.Name, "User", user, "Cordons", count, "ChangeRequest", node.Annotations[changeRequestAnnotation])
+		return response, true
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: Cordon, Decision: decisionDenied, DenialCode: MissingChangeRequestCode,
+		Grounds: "bulk cordon without a change request"})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      MissingChangeRequestCode,
+		Operation: Cordon,
+		User:      user,
+		Message: fmt.Sprintf("%q cordoned %d nodes within %s, which is a bulk cordon. Please add the %q annotation referencing the change request of the bulk cordon",
+			user, count, policy.BulkCordonWindow, changeRequestAnnotation),
+	}), true
+}
+
+// recordBulkCordonApproval records the approval of a cordon of a bulk like recordDecision, except for the event
+// on the node, since the bulk is recorded as a single event.
+func (n *NodeValidator) recordBulkCordonApproval(node *corev1.Node, user string, reason string, policy Policy, response admission.Response) {
+	n.decisions.record(user, false, n.now())
+	if policy.AuditWebhookURL != "" {
+		eventType, eventReason, message := n.decisionEvent(node, Cordon, user, reason, policy, response)
+		n.forwardDecision(node, eventType, eventReason, message, policy)
+	}
+}
+
+// bulkCordonTracker keeps the times of the recent cordons of each user in memory, so it is only accurate when
+// the webhook runs with a single replica. Its zero value is ready to use.
+type bulkCordonTracker struct {
+	mu    sync.Mutex
+	users map[string]*userCordons
+}
+
+// userCordons are the times of the cordons of a user within the window, from the oldest to the newest.
+type userCordons struct {
+	times []time.Time
+	// reported is set once the bulk of the current cordons is recorded as an event.
+	reported bool
+}
+
+// record returns the number of cordons of the user within the window ending at now, including a cordon at now,
+// and whether it is the first cordon beyond the threshold, which reports the bulk. The cordon is recorded when
+// record is true. The users without cordons within the window are dropped.
+func (t *bulkCordonTracker) record(user string, threshold int, window time.Duration, now time.Time, record bool) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.users == nil {
+		t.users = make(map[string]*userCordons)
+	}
+
+	for name, cordons := range t.users {
+		cordons.prune(now.Add(-window))
+		if len(cordons.times) == 0 {
+			delete(t.users, name)
+		}
+	}
+	cordons, ok := t.users[user]
+	if !ok {
+		cordons = &userCordons{}
+	}
+	count := len(cordons.times) + 1
+	if !record {
+		return count, false
+	}
+	cordons.times = append(cordons.times, now)
+	t.users[user] = cordons
+	firstOfBulk := count > threshold && !cordons.reported
+	if firstOfBulk {
+		cordons.reported = true
+	}
+	return count, firstOfBulk
+}
+
+// prune drops the cordons before the start of the window, and resets the report once there are none.
+func (c *userCordons) prune(start time.Time) {
+	i := 0
+	for i < len(c.times) && !c.times[i].After(start) {
+		i++
+	}
+	c.times = c.times[i:]
+	if len(c.times) == 0 {
+		c.reported = false
+	}
+}
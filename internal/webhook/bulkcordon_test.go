@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestBulkCordon(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", bulkCordonThresholdKey: "2", bulkCordonWindowKey: "60"},
+	})).Should(Succeed())
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := NewChannelEventRecorder(10)
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: clock, Recorder: recorder}
+	withReason := map[string]string{reasonAnnotation: "Testing"}
+	withChangeRequest := map[string]string{reasonAnnotation: "Testing", changeRequestAnnotation: "CHG-1"}
+	var event RecordedEvent
+
+	// The cordons up to the threshold are validated and recorded as usual.
+	for _, name := range []string{"node-1", "node-2"} {
+		g.Expect(nv.Handle(ctx, newCordonRequest(g, name, regularUserExample, withReason)).Allowed).Should(BeTrue())
+		g.Expect(recorder.Events()).Should(Receive(&event))
+		g.Expect(event.Reason).Should(Equal(operationApprovedEvent))
+	}
+
+	// The cordons beyond the threshold are a bulk cordon requiring a change request.
+	response := nv.Handle(ctx, newCordonRequest(g, "node-3", regularUserExample, withReason))
+	g.Expect(response.Allowed).Should(BeFalse())
+	g.Expect(denialDetail(g, response).Code).Should(Equal(MissingChangeRequestCode))
+	g.Expect(recorder.Events()).Should(Receive(&event))
+	g.Expect(event.Reason).Should(Equal(bulkCordonEvent))
+	g.Expect(event.Message).Should(ContainSubstring("cordoned 3 nodes within 1m0s"))
+	g.Expect(recorder.Events()).Should(Receive(&event))
+	g.Expect(event.Reason).Should(Equal(operationDeniedEvent))
+
+	// The approvals of the bulk cordon aren't recorded as individual events.
+	for _, name := range []string{"node-3", "node-4"} {
+		g.Expect(nv.Handle(ctx, newCordonRequest(g, name, regularUserExample, withChangeRequest)).Allowed).Should(BeTrue())
+	}
+	g.Expect(recorder.Events()).ShouldNot(Receive())
+
+	// Other users aren't affected.
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-5", "other-user", withReason)).Allowed).Should(BeTrue())
+
+	// Once the window passed, the cordons are validated as usual again.
+	clock.now = clock.now.Add(time.Minute)
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-6", regularUserExample, withReason)).Allowed).Should(BeTrue())
+}
+
+func TestBulkCordonTracker(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := bulkCordonTracker{}
+
+	for i, expected := range []struct {
+		count       int
+		firstOfBulk bool
+	}{{1, false}, {2, false}, {3, true}, {4, false}} {
+		count, firstOfBulk := tracker.record(regularUserExample, 2, time.Minute, now.Add(time.Duration(i)*time.Second), true)
+		g.Expect(count).Should(Equal(expected.count))
+		g.Expect(firstOfBulk).Should(Equal(expected.firstOfBulk))
+	}
+
+	// Dry runs aren't recorded.
+	count, firstOfBulk := tracker.record(regularUserExample, 2, time.Minute, now.Add(5*time.Second), false)
+	g.Expect(count).Should(Equal(5))
+	g.Expect(firstOfBulk).Should(BeFalse())
+
+	// The bulk ends once its cordons are out of the window, and the users without cordons are dropped.
+	count, firstOfBulk = tracker.record("other-user", 2, time.Minute, now.Add(time.Hour), true)
+	g.Expect(count).Should(Equal(1))
+	g.Expect(firstOfBulk).Should(BeFalse())
+	g.Expect(tracker.users).ShouldNot(HaveKey(regularUserExample))
+}
+
+func TestBulkCordonAuditWebhook(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	receiver := &auditWebhookServer{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data: map[string]string{allowedReasonsKey: "Testing", bulkCordonThresholdKey: "1", bulkCordonWindowKey: "60",
+			auditWebhookURLKey: server.URL, auditBatchSizeKey: "2", auditFlushIntervalKey: "3600"},
+	})).Should(Succeed())
+	recorder := NewChannelEventRecorder(10)
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, HTTPClient: server.Client(),
+		Clock: &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}, Recorder: recorder}
+	withChangeRequest := map[string]string{reasonAnnotation: "Testing", changeRequestAnnotation: "CHG-1"}
+
+	// The approval of a bulk cordon isn't recorded as an event on the node, but is forwarded to the audit webhook.
+	for _, name := range []string{"node-1", "node-2"} {
+		g.Expect(nv.Handle(ctx, newCordonRequest(g, name, regularUserExample, withChangeRequest)).Allowed).Should(BeTrue())
+	}
+	var event RecordedEvent
+	g.Expect(recorder.Events()).Should(Receive(&event))
+	g.Expect(event.Reason).Should(Equal(operationApprovedEvent))
+	g.Expect(recorder.Events()).Should(Receive(&event))
+	g.Expect(event.Reason).Should(Equal(bulkCordonEvent))
+	g.Expect(recorder.Events()).ShouldNot(Receive())
+
+	g.Eventually(receiver.received).Should(Equal(1))
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	events := corev1.EventList{}
+	g.Expect(json.Unmarshal(receiver.payloads[0], &events)).Should(Succeed())
+	g.Expect(events.Items).Should(HaveLen(2))
+	g.Expect(events.Items[1].InvolvedObject.Name).Should(Equal("node-2"))
+	g.Expect(events.Items[1].Reason).Should(Equal(operationApprovedEvent))
+	g.Expect(events.Items[1].Message).Should(ContainSubstring(`approved with reason "Testing"`))
+}
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	emergencyBypassAnnotation = "node.dana.io/emergency-bypass"
+	emergencyBypassSecretKey  = "hmacKey"
+)
+
+// NewEmergencyBypassToken returns an emergency bypass token for the node, valid until the expiry.
+// The token is of the form <expiry unix time>.<hex HMAC-SHA256 of "<node name>.<expiry unix time>">.
+func NewEmergencyBypassToken(key []byte, nodeName string, expiry time.Time) string {
+	expiryUnix := strconv.FormatInt(expiry.Unix(), 10)
+	return expiryUnix + "." + hex.EncodeToString(emergencyBypassMAC(key, nodeName, expiryUnix))
+}
+
+// emergencyBypassMAC returns the HMAC-SHA256 of the node name and the expiry.
+func emergencyBypassMAC(key []byte, nodeName string, expiryUnix string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(nodeName + "." + expiryUnix))
+	return mac.Sum(nil)
+}
+
+// isEmergencyBypassValid checks if the emergency bypass annotation of the node holds a token signed
+// with the secret for this node, which hasn't expired yet. The signature must be in lowercase hex, so that
+// each token has a single form and can't be used again by changing the case of its signature.
+func isEmergencyBypassValid(node *corev1.Node, secret []byte, now time.Time) bool {
+	token, ok := node.Annotations[emergencyBypassAnnotation]
+	if !ok || len(secret) == 0 {
+		return false
+	}
+
+	expiryUnix, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(expiryUnix, 10, 64)
+	if err != nil || !now.Before(time.Unix(expiry, 0)) {
+		return false
+	}
+	mac, err := hex.DecodeString(signature)
+	if err != nil || hex.EncodeToString(mac) != signature {
+		return false
+	}
+	return hmac.Equal(mac, emergencyBypassMAC(secret, node.Name, expiryUnix))
+}
+
+// emergencyBypass approves the operation if the node has a valid emergency bypass token which wasn't used before,
+// and records a Warning event on the node. It returns false if the operation isn't bypassed.
+// Forbidden users and groups can't bypass the validation. In dry run mode, the token isn't marked as used
+// and no event is recorded.
+func (n *NodeValidator) emergencyBypass(ctx context.Context, operation Operation, node *corev1.Node, user string, groups []string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool) {
+	token, ok := node.Annotations[emergencyBypassAnnotation]
+	if !ok || policy.EmergencyBypassSecret == "" || isForbidden(user, groups, policy) {
+		return admission.Response{}, false
+	}
+
+	secret, err := getSecretValue(ctx, n.Client, policy.EmergencyBypassSecret, emergencyBypassSecretKey)
+	if err != nil {
+		log.Error(err, "Failed to fetch the emergency bypass secret")
+		return admission.Response{}, false
+	}
+
+	now := n.now()
+	if !isEmergencyBypassValid(node, secret, now) {
+		log.Info("Emergency bypass rejected", "User", user, "DenialReason", "invalid or expired token")
+		return admission.Response{}, false
+	}
+	if (dryRun && n.usedBypassTokens.isUsed(token)) || (!dryRun && !n.usedBypassTokens.use(token, now)) {
+		log.Info("Emergency bypass rejected", "User", user, "DenialReason", "token was already used")
+		return admission.Response{}, false
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionAllowed, Grounds: "emergency bypass"})
+	if !dryRun {
+		n.decisions.record(user, false, now)
+	}
+	if n.Recorder != nil && !dryRun {
+		n.Recorder.Eventf(node, corev1.EventTypeWarning, emergencyBypassEvent, "%s operation by %q has been approved using the %q annotation", operation, user, emergencyBypassAnnotation)
+	}
+	return admission.Allowed(fmt.Sprintf("%s operation has been approved using an emergency bypass", operation)), true
+}
+
+// getSecretValue fetches the value of the key from the secret referenced as <namespace>/<name>,
+// or as <name> for a secret in the namespace of the webhook.
+func getSecretValue(ctx context.Context, c client.Client, ref string, key string) ([]byte, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = cmNamespace, ref
+	}
+
+	secret := corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to fetch Secret %s/%s: %w", namespace, name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("Secret %s/%s does not contain '%s' key", namespace, name, key)
+	}
+	return value, nil
+}
+
+// bypassTokenTracker keeps the used emergency bypass tokens in memory until they expire,
+// so that each token can only be used once. Its zero value is ready to use.
+type bypassTokenTracker struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+}
+
+// isUsed returns true if the token was already used.
+func (b *bypassTokenTracker) isUsed(token string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.tokens[token]
+	return ok
+}
+
+// use marks the token as used, and returns false if it was already used.
+func (b *bypassTokenTracker) use(token string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens == nil {
+		b.tokens = make(map[string]time.Time)
+	}
+	for usedToken, expiry := range b.tokens {
+		if now.After(expiry) {
+			delete(b.tokens, usedToken)
+		}
+	}
+	if _, ok := b.tokens[token]; ok {
+		return false
+	}
+
+	expiryUnix, _, _ := strings.Cut(token, ".")
+	expiry, _ := strconv.ParseInt(expiryUnix, 10, 64)
+	b.tokens[token] = time.Unix(expiry, 0)
+	return true
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const bypassSecretName = "emergency-bypass"
+
+var bypassKey = []byte("emergency-bypass-key")
+
+func TestEmergencyBypass(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		token     func(nodeName string) string
+		reason    string
+		allowed   bool
+		eventType string
+	}{
+		{name: "ValidToken", token: func(nodeName string) string {
+			return NewEmergencyBypassToken(bypassKey, nodeName, now.Add(time.Minute))
+		}, allowed: true, eventType: corev1.EventTypeWarning},
+		{name: "ExpiredToken", token: func(nodeName string) string {
+			return NewEmergencyBypassToken(bypassKey, nodeName, now.Add(-time.Minute))
+		}, allowed: false, eventType: corev1.EventTypeWarning},
+		{name: "WrongSignature", token: func(nodeName string) string {
+			return NewEmergencyBypassToken([]byte("wrong"), nodeName, now.Add(time.Minute))
+		}, allowed: false, eventType: corev1.EventTypeWarning},
+		{name: "OtherNodeToken", token: func(string) string { return NewEmergencyBypassToken(bypassKey, "other", now.Add(time.Minute)) }, allowed: false, eventType: corev1.EventTypeWarning},
+		{name: "AbsentAnnotation", reason: "Testing", allowed: true, eventType: corev1.EventTypeNormal},
+	}
+
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g := NewWithT(t)
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", emergencyBypassKey: bypassSecretName},
+	})).Should(Succeed())
+	g.Expect(fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: bypassSecretName, Namespace: cmNamespace},
+		Data:       map[string][]byte{emergencyBypassSecretKey: bypassKey},
+	})).Should(Succeed())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(10)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: now}, Recorder: recorder}
+
+			annotations := map[string]string{}
+			if test.token != nil {
+				annotations[emergencyBypassAnnotation] = test.token(test.name)
+			}
+			if test.reason != "" {
+				annotations[reasonAnnotation] = test.reason
+			}
+
+			response := nv.Handle(ctx, newDeleteRequest(g, test.name, regularUserExample, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			g.Expect(recorder.Events).Should(Receive(HavePrefix(test.eventType)))
+
+			// A token can only be used once.
+			if test.token != nil && test.allowed {
+				response = nv.Handle(ctx, newDeleteRequest(g, test.name, regularUserExample, annotations))
+				g.Expect(response.Allowed).Should(BeFalse())
+
+				// Nor replayed by changing the case of its signature.
+				annotations[emergencyBypassAnnotation] = strings.ToUpper(annotations[emergencyBypassAnnotation])
+				response = nv.Handle(ctx, newDeleteRequest(g, test.name, regularUserExample, annotations))
+				g.Expect(response.Allowed).Should(BeFalse())
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const defaultMinRemainingCapacityPercent = 20
+
+// clusterCapacity is the CPU and memory allocatable on the nodes of the cluster and requested by its pods.
+type clusterCapacity struct {
+	allocatableCPU    resource.Quantity
+	allocatableMemory resource.Quantity
+	requestedCPU      resource.Quantity
+	requestedMemory   resource.Quantity
+}
+
+// validateRemainingCapacity denies the deletion of a node if the CPU or memory left unrequested on the remaining nodes,
+// once the pods of the node are rescheduled, would be below the minimum remaining capacity of the policy, since
+// deleting a node of a cluster at capacity could cause cascading failures. A check which fails to run only adds
+// a warning, like the drain simulation.
+func (n *NodeValidator) validateRemainingCapacity(ctx context.Context, node *corev1.Node, user string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	if !response.Allowed {
+		return response
+	}
+
+	capacity, err := n.remainingCapacity(ctx, node)
+	if err != nil {
+		log.Error(err, "Failed to compute the remaining capacity of the cluster")
+		response.Warnings = append(response.Warnings, fmt.Sprintf("The remaining capacity of the cluster without node %q could not be checked: %s", node.Name, err))
+		return response
+	}
+	cpuPercent := freePercent(capacity.allocatableCPU.MilliValue(), capacity.requestedCPU.MilliValue())
+	memoryPercent := freePercent(capacity.allocatableMemory.Value(), capacity.requestedMemory.Value())
+	minPercent := float64(policy.MinRemainingCapacityPercent)
+	if cpuPercent >= minPercent && memoryPercent >= minPercent {
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: Delete, Decision: decisionDenied, DenialCode: InsufficientCapacityCode,
+		Grounds: "insufficient remaining capacity", Details: []any{"RemainingCPUPercent", int(cpuPercent), "RemainingMemoryPercent", int(memoryPercent)}})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      InsufficientCapacityCode,
+		Operation: Delete,
+		User:      user,
+		Message: fmt.Sprintf("Deleting node %q would leave %.0f%% of the CPU (%s requested of %s allocatable) and %.0f%% of the memory "+
+			"(%s requested of %s allocatable) of the remaining nodes free, below the minimum of %d%%",
+			node.Name, cpuPercent, capacity.requestedCPU.String(), capacity.allocatableCPU.String(),
+			memoryPercent, capacity.requestedMemory.String(), capacity.allocatableMemory.String(), policy.MinRemainingCapacityPercent),
+	})
+}
+
+// remainingCapacity returns the CPU and memory allocatable on the nodes other than the given one, and requested by
+// all the running pods, including those of the node which would be rescheduled.
+func (n *NodeValidator) remainingCapacity(ctx context.Context, node *corev1.Node) (clusterCapacity, error) {
+	nodes := corev1.NodeList{}
+	if err := n.Client.List(ctx, &nodes); err != nil {
+		return clusterCapacity{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods := corev1.PodList{}
+	if err := n.Client.List(ctx, &pods); err != nil {
+		return clusterCapacity{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	capacity := clusterCapacity{}
+	for _, remaining := range nodes.Items {
+		if remaining.Name == node.Name {
+			continue
+		}
+		capacity.allocatableCPU.Add(*remaining.Status.Allocatable.Cpu())
+		capacity.allocatableMemory.Add(*remaining.Status.Allocatable.Memory())
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		// The DaemonSet and mirror pods of the node go away with it.
+		if pod.Spec.NodeName == node.Name && !isEvictable(pod) {
+			continue
+		}
+		cpu, memory := podRequests(pod)
+		capacity.requestedCPU.Add(cpu)
+		capacity.requestedMemory.Add(memory)
+	}
+	return capacity, nil
+}
+
+// freePercent returns the percentage of the allocatable amount which isn't requested. It is zero without allocatable amount.
+func freePercent(allocatable int64, requested int64) float64 {
+	if allocatable <= 0 {
+		return 0
+	}
+	return max(0, float64(allocatable-requested)*100/float64(allocatable))
+}
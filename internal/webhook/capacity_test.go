@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestRemainingCapacityOnDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		allowed bool
+		message string
+	}{
+		{name: "Disabled", data: map[string]string{minCapacityPercentKey: "90"}, allowed: true},
+		{name: "DefaultMinimum", data: map[string]string{checkCapacityKey: "true"}, allowed: true},
+		{name: "BelowMinimum", data: map[string]string{checkCapacityKey: "true", minCapacityPercentKey: "40"}, allowed: false,
+			message: `Deleting node "worker-1" would leave 30% of the CPU (14 requested of 20 allocatable) and 50% of the memory ` +
+				`(10Gi requested of 20Gi allocatable) of the remaining nodes free, below the minimum of 40%`},
+		{name: "InvalidMinimum", data: map[string]string{checkCapacityKey: "true", minCapacityPercentKey: "120"}, allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			test.data[allowedReasonsKey] = "Testing"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			daemonSetPod := newSimulatedPod("agent", "worker-1", "8", "8Gi", nil)
+			daemonSetPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent", UID: "uid", Controller: ptr(true)}}
+			completedPod := newSimulatedPod("job", "worker-2", "8", "8Gi", nil)
+			completedPod.Status.Phase = corev1.PodSucceeded
+			for _, object := range []corev1.Node{
+				*newSimulatedNode("worker-1", "4", "8Gi", nil),
+				*newSimulatedNode("worker-2", "10", "10Gi", nil),
+				*newSimulatedNode("worker-3", "10", "10Gi", nil),
+			} {
+				g.Expect(fakeClient.Create(ctx, &object)).Should(Succeed())
+			}
+			for _, pod := range []*corev1.Pod{newSimulatedPod("web", "worker-1", "2", "2Gi", nil), newSimulatedPod("db", "worker-2", "12", "8Gi", nil), daemonSetPod, completedPod} {
+				g.Expect(fakeClient.Create(ctx, pod)).Should(Succeed())
+			}
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newDeleteRequest(g, "worker-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.message != "" {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(InsufficientCapacityCode))
+				g.Expect(detail.Message).Should(Equal(test.message))
+			}
+		})
+	}
+}
+
+func TestFreePercent(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(freePercent(100, 25)).Should(Equal(75.0))
+	g.Expect(freePercent(100, 150)).Should(Equal(0.0))
+	g.Expect(freePercent(0, 0)).Should(Equal(0.0))
+}
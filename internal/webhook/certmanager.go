@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// certificateGVK is the kind of the cert-manager Certificates.
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get
+// +kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=list;watch
+
+// CertificateSecretName returns the name of the Secret in which cert-manager stores the certificate of the given
+// Certificate. It returns false if the Certificate doesn't exist, or if cert-manager isn't installed.
+func CertificateSecretName(ctx context.Context, reader client.Reader, namespace string, name string) (string, bool, error) {
+	certificate := unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &certificate); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to fetch Certificate %s/%s: %w", namespace, name, err)
+	}
+	secretName, _, err := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	if err != nil || secretName == "" {
+		return "", false, fmt.Errorf("certificate %s/%s has no secretName", namespace, name)
+	}
+	return secretName, true, nil
+}
+
+// SecretCertWatcher serves the certificate of a TLS Secret, reloading it whenever the Secret changes, e.g. when
+// cert-manager renews it, without waiting for the kubelet to update a mounted volume.
+// It is a manager.Runnable, watching the Secret once the manager starts.
+type SecretCertWatcher struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// Load fetches the Secret and loads its certificate, so that the certificate is served before the watch starts.
+func (w *SecretCertWatcher) Load(ctx context.Context) error {
+	secret, err := w.Client.CoreV1().Secrets(w.Namespace).Get(ctx, w.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch Secret %s/%s: %w", w.Namespace, w.Name, err)
+	}
+	return w.load(secret)
+}
+
+// Start watches the Secret until the context is done, reloading the certificate on each change.
+func (w *SecretCertWatcher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("Certificate Watcher").WithValues("Secret", w.Namespace+"/"+w.Name)
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", w.Name).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return w.Client.CoreV1().Secrets(w.Namespace).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return w.Client.CoreV1().Secrets(w.Namespace).Watch(ctx, options)
+		},
+	}
+	reload := func(obj any) {
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		if err := w.load(secret); err != nil {
+			logger.Error(err, "Failed to reload the certificate, the previous one is still served")
+			return
+		}
+		logger.Info("Reloaded the certificate")
+	}
+	informer := cache.NewSharedIndexInformer(listWatch, &corev1.Secret{}, 0, cache.Indexers{})
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    reload,
+		UpdateFunc: func(_, obj any) { reload(obj) },
+	}); err != nil {
+		return err
+	}
+	informer.Run(ctx.Done())
+	return nil
+}
+
+// NeedLeaderElection returns false, since every replica serves the webhooks.
+func (w *SecretCertWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// GetCertificate returns the current certificate. It is meant to be set as the GetCertificate of a tls.Config.
+func (w *SecretCertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.certificate == nil {
+		return nil, errors.New("the certificate isn't loaded yet")
+	}
+	return w.certificate, nil
+}
+
+// load parses the certificate and the key of the Secret and makes them the served certificate.
+func (w *SecretCertWatcher) load(secret *corev1.Secret) error {
+	certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid certificate in Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.certificate = &certificate
+	return nil
+}
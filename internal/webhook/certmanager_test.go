@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTLSSecret returns a TLS Secret holding a self-signed certificate of the given common name.
+func newTLSSecret(g *WithT, commonName string) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-server-cert", Namespace: cmNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+		},
+	}
+}
+
+// servedCommonName returns the common name of the certificate served by the watcher.
+func servedCommonName(g Gomega, w *SecretCertWatcher) string {
+	certificate, err := w.GetCertificate(nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return leaf.Subject.CommonName
+}
+
+func TestCertificateSecretName(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetNamespace(cmNamespace)
+	certificate.SetName("serving-cert")
+	g.Expect(unstructured.SetNestedField(certificate.Object, "webhook-server-cert", "spec", "secretName")).Should(Succeed())
+	fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(certificate).Build()
+
+	secretName, ok, err := CertificateSecretName(ctx, fakeClient, cmNamespace, "serving-cert")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ok).Should(BeTrue())
+	g.Expect(secretName).Should(Equal("webhook-server-cert"))
+
+	_, ok, err = CertificateSecretName(ctx, fakeClient, cmNamespace, "missing")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ok).Should(BeFalse())
+}
+
+func TestSecretCertWatcher(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	secret := newTLSSecret(g, "original")
+	clientset := fake.NewSimpleClientset(secret)
+	watcher := &SecretCertWatcher{Client: clientset, Namespace: secret.Namespace, Name: secret.Name}
+
+	_, err := watcher.GetCertificate(nil)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(watcher.Load(ctx)).Should(Succeed())
+	g.Expect(servedCommonName(g, watcher)).Should(Equal("original"))
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Start(ctx) }()
+
+	// cert-manager renews the certificate.
+	renewed := newTLSSecret(g, "renewed")
+	g.Eventually(func(g Gomega) {
+		_, err := clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, renewed, metav1.UpdateOptions{})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(servedCommonName(g, watcher)).Should(Equal("renewed"))
+	}, 5*time.Second, 50*time.Millisecond).Should(Succeed())
+
+	// An invalid Secret keeps the previous certificate.
+	renewed.Data[corev1.TLSCertKey] = []byte("invalid")
+	_, err = clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, renewed, metav1.UpdateOptions{})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Consistently(func(g Gomega) string { return servedCommonName(g, watcher) }, 200*time.Millisecond).Should(Equal("renewed"))
+
+	cancel()
+	g.Eventually(done).Should(Receive(BeNil()))
+}
@@ -0,0 +1,41 @@
+//go:build chaos
+
+package webhook
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	enableChaosEnv  = "ENABLE_CHAOS"
+	chaosLatencyEnv = "CHAOS_LATENCY_MS"
+)
+
+// injectChaosLatency delays the admission response by CHAOS_LATENCY_MS milliseconds when ENABLE_CHAOS is true.
+// It is only compiled with the chaos build tag.
+func injectChaosLatency(ctx context.Context) {
+	latency := chaosLatency()
+	if latency <= 0 {
+		return
+	}
+
+	select {
+	case <-time.After(latency):
+	case <-ctx.Done():
+	}
+}
+
+// chaosLatency returns the latency to inject, or zero if chaos isn't enabled or the latency isn't a positive integer.
+func chaosLatency() time.Duration {
+	if enabled, err := strconv.ParseBool(os.Getenv(enableChaosEnv)); err != nil || !enabled {
+		return 0
+	}
+	latency, err := strconv.Atoi(os.Getenv(chaosLatencyEnv))
+	if err != nil || latency <= 0 {
+		return 0
+	}
+	return time.Duration(latency) * time.Millisecond
+}
@@ -0,0 +1,8 @@
+//go:build !chaos
+
+package webhook
+
+import "context"
+
+// injectChaosLatency is a no-op unless built with the chaos build tag.
+func injectChaosLatency(context.Context) {}
@@ -0,0 +1,33 @@
+//go:build chaos
+
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestChaosLatency(t *testing.T) {
+	tests := []struct {
+		name        string
+		enableChaos string
+		latency     string
+		expected    time.Duration
+	}{
+		{name: "Enabled", enableChaos: "true", latency: "250", expected: 250 * time.Millisecond},
+		{name: "Disabled", enableChaos: "false", latency: "250", expected: 0},
+		{name: "EnabledWithoutLatency", enableChaos: "true", latency: "", expected: 0},
+		{name: "NegativeLatency", enableChaos: "true", latency: "-1", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv(enableChaosEnv, test.enableChaos)
+			t.Setenv(chaosLatencyEnv, test.latency)
+			g.Expect(chaosLatency()).Should(Equal(test.expected))
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenDuration     = 30 * time.Second
+	// maxLastGoodObjects bounds the number of the last good policy objects kept by a CircuitBreakerClient.
+	maxLastGoodObjects = 64
+)
+
+// circuitState is the state of a circuit breaker. Its value is exported as the circuit state gauge.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// ErrCircuitOpen is returned by the CircuitBreakerClient when the circuit is open and there is no cached object.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+var circuitStateGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "node_operation_validator_api_circuit_state",
+	Help: "State of the circuit breaker of the Kubernetes API client: 0 closed, 1 half-open, 2 open.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(circuitStateGauge)
+}
+
+// CircuitBreakerClient wraps a client with a circuit breaker on its reads. The circuit opens after FailureThreshold
+// consecutive failures, during which the reads of the policy, the ConfigMaps and the NodeOperationPolicy list, return
+// the last copy fetched successfully instead of calling the API server. The other reads fail with ErrCircuitOpen, and
+// only the latest 64 policy objects are kept. After OpenDuration, a single probe is let through, and the circuit closes
+// if it succeeds. It is meant to wrap a client reading from the API server rather than from an informer cache, whose
+// reads don't fail when the API server is unavailable. Its zero value, along with a Client, is ready to use.
+type CircuitBreakerClient struct {
+	client.Client
+	// FailureThreshold is the number of consecutive failures opening the circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenDuration is the time the circuit stays open before a probe is let through. Defaults to 30 seconds.
+	OpenDuration time.Duration
+	// Clock provides the current time. Defaults to the system clock.
+	Clock Clock
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	lastGood map[circuitCacheKey]runtime.Object
+	// lastGoodOrder holds the keys of the last good objects from the oldest stored to the latest.
+	lastGoodOrder []circuitCacheKey
+}
+
+// circuitCacheKey identifies an object fetched by the CircuitBreakerClient. The key of a list is empty.
+type circuitCacheKey struct {
+	gvk schema.GroupVersionKind
+	key client.ObjectKey
+}
+
+// Get fetches the object, or returns the last good copy of it while the circuit is open.
+func (c *CircuitBreakerClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if !c.allow() {
+		return c.getLastGood(key, obj)
+	}
+
+	err := c.Client.Get(ctx, key, obj, opts...)
+	c.done(err)
+	if err == nil {
+		c.storeLastGood(key, obj)
+	}
+	return err
+}
+
+// List lists the objects, or returns the last good copy of the list while the circuit is open.
+// Only the lists without options are kept.
+func (c *CircuitBreakerClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if !c.allow() {
+		if len(opts) > 0 {
+			return ErrCircuitOpen
+		}
+		return c.getLastGood(client.ObjectKey{}, list)
+	}
+
+	err := c.Client.List(ctx, list, opts...)
+	c.done(err)
+	if err == nil && len(opts) == 0 {
+		c.storeLastGood(client.ObjectKey{}, list)
+	}
+	return err
+}
+
+// isPolicyObject returns true if the object holds a policy, whose last good copy is kept.
+func isPolicyObject(obj runtime.Object) bool {
+	switch obj.(type) {
+	case *corev1.ConfigMap, *v1alpha1.NodeOperationPolicyList:
+		return true
+	default:
+		return false
+	}
+}
+
+// allow checks if a request can be sent to the API server. Once the open duration has passed,
+// the circuit becomes half-open and a single probe is allowed.
+func (c *CircuitBreakerClient) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitClosed:
+		return true
+	case circuitOpen:
+		if c.now().Sub(c.openedAt) >= c.openDuration() {
+			c.transition(circuitHalfOpen)
+			return true
+		}
+	}
+	return false
+}
+
+// done records the result of a request sent to the API server. Not found errors are valid responses, so
+// they aren't failures.
+func (c *CircuitBreakerClient) done(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil || apierrors.IsNotFound(err) {
+		c.failures = 0
+		if c.state != circuitClosed {
+			c.transition(circuitClosed)
+		}
+		return
+	}
+
+	c.failures++
+	if c.state == circuitHalfOpen || c.failures >= c.failureThreshold() {
+		c.openedAt = c.now()
+		c.transition(circuitOpen)
+	}
+}
+
+// currentState returns the state of the circuit.
+func (c *CircuitBreakerClient) currentState() circuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// transition changes the state of the circuit, logging it and updating the gauge.
+func (c *CircuitBreakerClient) transition(state circuitState) {
+	if c.state != state {
+		log.Log.WithName("circuit-breaker").Info("Circuit state changed", "From", c.state, "To", state, "ConsecutiveFailures", c.failures)
+	}
+	c.state = state
+	circuitStateGauge.Set(float64(state))
+}
+
+// storeLastGood keeps a copy of a successfully fetched policy object, dropping the oldest stored one beyond
+// the maximum number of objects.
+func (c *CircuitBreakerClient) storeLastGood(key client.ObjectKey, obj runtime.Object) {
+	if !isPolicyObject(obj) {
+		return
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastGood == nil {
+		c.lastGood = make(map[circuitCacheKey]runtime.Object)
+	}
+	cacheKey := circuitCacheKey{gvk: gvk, key: key}
+	c.lastGoodOrder = slices.DeleteFunc(c.lastGoodOrder, func(k circuitCacheKey) bool { return k == cacheKey })
+	c.lastGoodOrder = append(c.lastGoodOrder, cacheKey)
+	c.lastGood[cacheKey] = obj.DeepCopyObject()
+	if len(c.lastGoodOrder) > maxLastGoodObjects {
+		delete(c.lastGood, c.lastGoodOrder[0])
+		c.lastGoodOrder = c.lastGoodOrder[1:]
+	}
+}
+
+// getLastGood copies the last good copy of an object into obj, or returns ErrCircuitOpen if there is none.
+func (c *CircuitBreakerClient) getLastGood(key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCircuitOpen, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lastGood, ok := c.lastGood[circuitCacheKey{gvk: gvk, key: key}]
+	if !ok {
+		return fmt.Errorf("%w: no cached %s %s", ErrCircuitOpen, gvk.Kind, key)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(lastGood.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *CircuitBreakerClient) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+func (c *CircuitBreakerClient) failureThreshold() int {
+	if c.FailureThreshold <= 0 {
+		return defaultFailureThreshold
+	}
+	return c.FailureThreshold
+}
+
+func (c *CircuitBreakerClient) openDuration() time.Duration {
+	if c.OpenDuration <= 0 {
+		return defaultOpenDuration
+	}
+	return c.OpenDuration
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+func TestCircuitBreakerClient(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	apiServerDown := false
+	calls := 0
+	fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	}).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			calls++
+			if apiServerDown {
+				return errors.New("connection refused")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	clock := &fakeClock{now: time.Now()}
+	breaker := &CircuitBreakerClient{Client: fakeClient, FailureThreshold: 2, OpenDuration: time.Minute, Clock: clock}
+	key := client.ObjectKey{Name: cmName, Namespace: cmNamespace}
+
+	configMap := corev1.ConfigMap{}
+	g.Expect(breaker.Get(ctx, key, &configMap)).Should(Succeed())
+
+	apiServerDown = true
+	for range 2 {
+		g.Expect(breaker.Get(ctx, key, &corev1.ConfigMap{})).ShouldNot(Succeed())
+	}
+	g.Expect(breaker.state).Should(Equal(circuitOpen))
+
+	// The open circuit serves the last good config without calling the API server.
+	calls = 0
+	configMap = corev1.ConfigMap{}
+	g.Expect(breaker.Get(ctx, key, &configMap)).Should(Succeed())
+	g.Expect(configMap.Data[allowedReasonsKey]).Should(Equal("Testing"))
+	g.Expect(calls).Should(BeZero())
+	g.Expect(breaker.Get(ctx, client.ObjectKey{Name: policiesCMName, Namespace: cmNamespace}, &corev1.ConfigMap{})).Should(MatchError(ErrCircuitOpen))
+
+	// A failed probe re-opens the circuit.
+	clock.now = clock.now.Add(time.Minute)
+	g.Expect(breaker.Get(ctx, key, &corev1.ConfigMap{})).ShouldNot(Succeed())
+	g.Expect(calls).Should(Equal(1))
+	g.Expect(breaker.state).Should(Equal(circuitOpen))
+	g.Expect(breaker.List(ctx, &corev1.NodeList{})).Should(MatchError(ErrCircuitOpen))
+
+	// A successful probe closes the circuit.
+	apiServerDown = false
+	clock.now = clock.now.Add(time.Minute)
+	g.Expect(breaker.Get(ctx, key, &corev1.ConfigMap{})).Should(Succeed())
+	g.Expect(calls).Should(Equal(2))
+	g.Expect(breaker.state).Should(Equal(circuitClosed))
+}
+
+func TestCircuitBreakerClientLastGood(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	apiServerDown := false
+	objects := []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "reason", Namespace: cmNamespace}},
+		&v1alpha1.NodeOperationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "all-nodes"}},
+	}
+	for i := range maxLastGoodObjects {
+		objects = append(objects, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i), Namespace: cmNamespace}})
+	}
+	fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if apiServerDown {
+				return errors.New("connection refused")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if apiServerDown {
+				return errors.New("connection refused")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	}).Build()
+	breaker := &CircuitBreakerClient{Client: fakeClient, FailureThreshold: 1, OpenDuration: time.Minute, Clock: &fakeClock{now: time.Now()}}
+
+	g.Expect(breaker.Get(ctx, client.ObjectKey{Name: "reason", Namespace: cmNamespace}, &corev1.Secret{})).Should(Succeed())
+	g.Expect(breaker.List(ctx, &v1alpha1.NodeOperationPolicyList{})).Should(Succeed())
+	for i := range maxLastGoodObjects {
+		g.Expect(breaker.Get(ctx, client.ObjectKey{Name: fmt.Sprintf("policy-%d", i), Namespace: cmNamespace}, &corev1.ConfigMap{})).Should(Succeed())
+	}
+	g.Expect(breaker.lastGood).Should(HaveLen(maxLastGoodObjects))
+
+	apiServerDown = true
+	g.Expect(breaker.Get(ctx, client.ObjectKey{Name: "policy-0", Namespace: cmNamespace}, &corev1.ConfigMap{})).ShouldNot(Succeed())
+	g.Expect(breaker.state).Should(Equal(circuitOpen))
+
+	// The Secrets aren't kept, and the NodeOperationPolicies fetched first are dropped beyond the bound.
+	g.Expect(breaker.Get(ctx, client.ObjectKey{Name: "reason", Namespace: cmNamespace}, &corev1.Secret{})).Should(MatchError(ErrCircuitOpen))
+	g.Expect(breaker.List(ctx, &v1alpha1.NodeOperationPolicyList{})).Should(MatchError(ErrCircuitOpen))
+	configMap := corev1.ConfigMap{}
+	g.Expect(breaker.Get(ctx, client.ObjectKey{Name: "policy-1", Namespace: cmNamespace}, &configMap)).Should(Succeed())
+	g.Expect(configMap.Name).Should(Equal("policy-1"))
+}
+
+func TestCircuitBreakerClientPolicyList(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	apiServerDown := false
+	fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(
+		&v1alpha1.NodeOperationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "all-nodes"}},
+	).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if apiServerDown {
+				return errors.New("connection refused")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	}).Build()
+	breaker := &CircuitBreakerClient{Client: fakeClient, FailureThreshold: 1, OpenDuration: time.Minute, Clock: &fakeClock{now: time.Now()}}
+
+	g.Expect(breaker.List(ctx, &v1alpha1.NodeOperationPolicyList{})).Should(Succeed())
+	apiServerDown = true
+	g.Expect(breaker.List(ctx, &v1alpha1.NodeOperationPolicyList{})).ShouldNot(Succeed())
+	g.Expect(breaker.state).Should(Equal(circuitOpen))
+
+	// The open circuit serves the last good NodeOperationPolicies, but not the lists with options.
+	policies := v1alpha1.NodeOperationPolicyList{}
+	g.Expect(breaker.List(ctx, &policies)).Should(Succeed())
+	g.Expect(policies.Items).Should(HaveLen(1))
+	g.Expect(breaker.List(ctx, &v1alpha1.NodeOperationPolicyList{}, client.InNamespace(cmNamespace))).Should(MatchError(ErrCircuitOpen))
+}
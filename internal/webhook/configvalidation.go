@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ValidateConfig fetches the global ConfigMap and returns an error describing each of its violations, so that
+// a misconfigured webhook fails at startup rather than on every admission request. The reason patterns must compile,
+// the minimum reason length must not exceed the maximum one, and the maintenance windows must parse. The rest of
+// the keys are only checked if none of those are violated. A missing ConfigMap isn't an error, since the default
+// policy is used then. The allowed reasons which look misspelled are logged as warnings.
+func (n *NodeValidator) ValidateConfig(ctx context.Context) error {
+	reader := n.APIReader
+	if reader == nil {
+		reader = n.Client
+	}
+
+	configMap := corev1.ConfigMap{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: cmNamespace, Name: cmName}, &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", cmNamespace, cmName, err)
+	}
+
+	var errs []error
+	for _, key := range []string{reasonRegexPatternKey, drainPatternKey} {
+		if _, err := compilePattern(configMap.Data[key]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", key, cmNamespace, cmName, err))
+		}
+	}
+	minLength, minErr := parseNonNegativeInt(&configMap, reasonMinLengthKey)
+	maxLength, maxErr := parseNonNegativeInt(&configMap, reasonMaxLengthKey)
+	errs = append(errs, minErr, maxErr)
+	if minErr == nil && maxErr == nil && maxLength > 0 && minLength > maxLength {
+		errs = append(errs, fmt.Errorf("%q (%d) is greater than %q (%d) in ConfigMap %s/%s",
+			reasonMinLengthKey, minLength, reasonMaxLengthKey, maxLength, cmNamespace, cmName))
+	}
+	if _, err := parseMaintenanceWindows(configMap.Data[maintenanceWindowsKey]); err != nil {
+		errs = append(errs, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", maintenanceWindowsKey, cmNamespace, cmName, err))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	policy, err := policyFromConfigMap(&configMap)
+	if err != nil {
+		return err
+	}
+	warnMisspelledReasons(policy.AllowedReasons, log.FromContext(ctx))
+	return nil
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		noConfig bool
+		getError bool
+		errors   []string
+	}{
+		{name: "ValidConfig", data: map[string]string{allowedReasonsKey: "Testing", reasonRegexPatternKey: "^OPS-[0-9]+", maintenanceWindowsKey: "Sat 00:00-06:00 UTC"}},
+		{name: "MissingConfigMap", noConfig: true},
+		{name: "InvalidRegex", data: map[string]string{allowedReasonsKey: "Testing", reasonRegexPatternKey: "^OPS-[0-9+"}, errors: []string{reasonRegexPatternKey}},
+		{name: "InvalidDrainRegex", data: map[string]string{allowedReasonsKey: "Testing", drainPatternKey: "(maintenance"}, errors: []string{drainPatternKey}},
+		{name: "MinLengthGreaterThanMaxLength", data: map[string]string{allowedReasonsKey: "Testing", reasonMinLengthKey: "20", reasonMaxLengthKey: "10"}, errors: []string{reasonMinLengthKey + `" (20) is greater than "` + reasonMaxLengthKey}},
+		{name: "InvalidLength", data: map[string]string{allowedReasonsKey: "Testing", reasonMaxLengthKey: "ten"}, errors: []string{reasonMaxLengthKey}},
+		{name: "InvalidMaintenanceWindow", data: map[string]string{allowedReasonsKey: "Testing", maintenanceWindowsKey: "Funday 00:00-06:00 UTC"}, errors: []string{maintenanceWindowsKey}},
+		{name: "MissingAllowedReasons", data: map[string]string{}, errors: []string{allowedReasonsKey}},
+		{name: "MultipleViolations", data: map[string]string{allowedReasonsKey: "Testing", reasonRegexPatternKey: "[", reasonMinLengthKey: "20", reasonMaxLengthKey: "10", maintenanceWindowsKey: "Sat"},
+			errors: []string{reasonRegexPatternKey, reasonMinLengthKey, maintenanceWindowsKey}},
+		{name: "FetchError", getError: true, errors: []string{"connection refused"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			funcs := interceptor.Funcs{}
+			if test.getError {
+				funcs.Get = func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return errors.New("connection refused")
+				}
+			}
+			builder := testclient.NewClientBuilder().WithScheme(newScheme()).WithInterceptorFuncs(funcs)
+			if !test.noConfig {
+				builder = builder.WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+					Data:       test.data,
+				})
+			}
+			nv := NodeValidator{APIReader: builder.Build()}
+
+			err := nv.ValidateConfig(ctx)
+			if len(test.errors) == 0 {
+				g.Expect(err).ShouldNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).Should(HaveOccurred())
+			for _, expected := range test.errors {
+				g.Expect(err.Error()).Should(ContainSubstring(expected))
+			}
+		})
+	}
+}
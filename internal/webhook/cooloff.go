@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const lastUncordonedAnnotation = "node.dana.io/last-uncordoned"
+
+// checkCordonCooloff denies cordoning or draining a node within the minimum cordon cool-off period of the policy
+// since it was last uncordoned, as stamped by the NodeMutator in the last uncordoned annotation, since a node
+// repeatedly cordoned and uncordoned might indicate a control loop bug. An invalid annotation is ignored.
+// It returns false if the operation is denied.
+func (n *NodeValidator) checkCordonCooloff(operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger) (admission.Response, bool) {
+	if policy.MinCordonCooloff <= 0 || (operation != Cordon && operation != Drain) {
+		return admission.Response{}, true
+	}
+	value, ok := node.Annotations[lastUncordonedAnnotation]
+	if !ok {
+		return admission.Response{}, true
+	}
+	lastUncordoned, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Info("Ignoring invalid last uncordoned annotation", "Annotation", lastUncordonedAnnotation, "Value", value)
+		return admission.Response{}, true
+	}
+
+	remaining := lastUncordoned.Add(policy.MinCordonCooloff).Sub(n.now())
+	if remaining <= 0 {
+		return admission.Response{}, true
+	}
+	remainingSeconds := int(math.Ceil(remaining.Seconds()))
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: CordonCooloffCode,
+		Grounds: "node uncordoned too recently", Details: []any{"LastUncordoned", value, "RemainingSeconds", remainingSeconds}})
+	return denied(policy, DenialDetail{
+		Code:      CordonCooloffCode,
+		Operation: operation,
+		User:      user,
+		Message:   fmt.Sprintf("node was uncordoned too recently; wait %d seconds", remainingSeconds),
+	}), false
+}
+
+// lastUncordoned returns the time to stamp in the last uncordoned annotation of the node if the update uncordons it
+// and the policy sets a minimum cordon cool-off period, or false otherwise.
+func (m *NodeMutator) lastUncordoned(ctx context.Context, oldNode *corev1.Node, node *corev1.Node, logger logr.Logger) (string, bool) {
+	if !oldNode.Spec.Unschedulable || node.Spec.Unschedulable {
+		return "", false
+	}
+
+	policy, err := policyResolver(m.PolicyResolver, m.PolicyClient, m.Client).Resolve(ctx, node)
+	if err != nil {
+		logger.Error(err, "Failed to resolve policy, the last uncordoned time is not updated")
+		return "", false
+	}
+	if policy.MinCordonCooloff <= 0 {
+		return "", false
+	}
+	return m.now().UTC().Format(time.RFC3339), true
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestCordonCooloff(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		cooloffSeconds string
+		lastUncordoned string
+		allowed        bool
+		message        string
+	}{
+		{name: "WithinCooloff", cooloffSeconds: "300", lastUncordoned: now.Add(-100*time.Second - 500*time.Millisecond).Format(time.RFC3339Nano), allowed: false,
+			message: "node was uncordoned too recently; wait 200 seconds"},
+		{name: "AfterCooloff", cooloffSeconds: "300", lastUncordoned: now.Add(-10 * time.Minute).Format(time.RFC3339), allowed: true},
+		{name: "NeverUncordoned", cooloffSeconds: "300", allowed: true},
+		{name: "InvalidAnnotation", cooloffSeconds: "300", lastUncordoned: "yesterday", allowed: true},
+		{name: "NoCooloff", lastUncordoned: now.Format(time.RFC3339), allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			data := map[string]string{allowedReasonsKey: "Testing"}
+			if test.cooloffSeconds != "" {
+				data[cordonCooloffKey] = test.cooloffSeconds
+			}
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace}, Data: data})).Should(Succeed())
+			nv := &NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: now}}
+
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.lastUncordoned != "" {
+				annotations[lastUncordonedAnnotation] = test.lastUncordoned
+			}
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(CordonCooloffCode))
+				g.Expect(detail.Message).Should(Equal(test.message))
+			}
+		})
+	}
+}
+
+func TestNodeMutatorLastUncordoned(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		cooloffSeconds string
+		uncordon       bool
+		stamped        bool
+	}{
+		{name: "Uncordon", cooloffSeconds: "300", uncordon: true, stamped: true},
+		{name: "Cordon", cooloffSeconds: "300", uncordon: false, stamped: false},
+		{name: "NoCooloff", uncordon: true, stamped: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			data := map[string]string{allowedReasonsKey: "Testing"}
+			if test.cooloffSeconds != "" {
+				data[cordonCooloffKey] = test.cooloffSeconds
+			}
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace}, Data: data})).Should(Succeed())
+			nm := NodeMutator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: now}}
+
+			cordoned := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name}, Spec: corev1.NodeSpec{Unschedulable: true}}
+			uncordoned := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name}}
+			request := newUpdateRequest(g, regularUserExample, cordoned, uncordoned)
+			if !test.uncordon {
+				request = newUpdateRequest(g, regularUserExample, uncordoned, cordoned)
+			}
+
+			response := nm.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(BeTrue())
+			lastUncordoned, ok := patchedAnnotation(response, lastUncordonedAnnotation)
+			g.Expect(ok).Should(Equal(test.stamped))
+			if test.stamped {
+				g.Expect(lastUncordoned).Should(Equal(now.Format(time.RFC3339)))
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=dana.io,resources=cordonbudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dana.io,resources=cordonbudgets/status,verbs=get;update;patch
+
+// validateCordonBudgets denies a cordon which would bring the number of cordoned nodes selected by a CordonBudget
+// selecting the node over its maximum. The number of cordoned nodes is counted when validating rather than read
+// from the status of the budget, which may lag behind. The budgets with an invalid selector or maximum are ignored.
+// In warn only mode, the denial message is added to the warnings of the given response. A validator without a client,
+// such as the one of the self-test, has no budgets.
+func (n *NodeValidator) validateCordonBudgets(ctx context.Context, operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	if n.Client == nil {
+		return response
+	}
+
+	budgets := v1alpha1.CordonBudgetList{}
+	if err := n.Client.List(ctx, &budgets); err != nil {
+		if meta.IsNoMatchError(err) {
+			return response
+		}
+		log.Error(err, "Failed to list the cordon budgets")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to list CordonBudgets: %w", err))
+	}
+
+	var nodes *corev1.NodeList
+	for i := range budgets.Items {
+		budget := &budgets.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(&budget.Spec.Selector)
+		if err != nil {
+			log.Error(err, "Invalid selector, the cordon budget is ignored", "CordonBudget", budget.Name)
+			continue
+		}
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if nodes == nil {
+			nodes = &corev1.NodeList{}
+			if err := n.Client.List(ctx, nodes); err != nil {
+				log.Error(err, "Failed to list nodes")
+				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to list nodes: %w", err))
+			}
+		}
+
+		selected, cordoned := countBudgetNodes(nodes.Items, selector, node.Name)
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(&budget.Spec.MaxUnavailable, selected+1, true)
+		if err != nil {
+			log.Error(err, "Invalid maximum, the cordon budget is ignored", "CordonBudget", budget.Name)
+			continue
+		}
+		if cordoned < maxUnavailable {
+			continue
+		}
+
+		logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: CordonBudgetExceededCode,
+			Grounds: "cordon budget exceeded", Details: []any{"CordonBudget", budget.Name, "CordonedNodes", cordoned, "MaxUnavailable", maxUnavailable}})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      CordonBudgetExceededCode,
+			Operation: operation,
+			User:      user,
+			Message: fmt.Sprintf("%d of the %d nodes of CordonBudget %q are already cordoned, which is the maximum allowed",
+				cordoned, selected+1, budget.Name),
+		})
+	}
+	return response
+}
+
+// countBudgetNodes returns the number of the nodes matching the selector, and of the cordoned ones, excluding
+// the node with the given name.
+func countBudgetNodes(nodes []corev1.Node, selector labels.Selector, nodeName string) (int, int) {
+	selected, cordoned := 0, 0
+	for _, node := range nodes {
+		if node.Name == nodeName || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		selected++
+		if node.Spec.Unschedulable {
+			cordoned++
+		}
+	}
+	return selected, cordoned
+}
+
+// CordonBudgetController updates the status of the CordonBudgets with the number of the nodes they select and
+// of the cordoned ones, as the nodes are cordoned, uncordoned, relabeled, added and removed.
+type CordonBudgetController struct {
+	Client client.Client
+}
+
+// SetupWithManager registers the controller with the manager, watching the CordonBudgets and the nodes.
+func (c *CordonBudgetController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("cordon-budget").
+		For(&v1alpha1.CordonBudget{}).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(c.allBudgets), builder.WithPredicates(nodeBudgetChanged)).
+		Complete(c)
+}
+
+// nodeBudgetChanged filters out the updates of the nodes which change neither their labels nor whether they are
+// cordoned, such as their heartbeats.
+var nodeBudgetChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+		newNode, newOk := e.ObjectNew.(*corev1.Node)
+		if !oldOk || !newOk {
+			return true
+		}
+		return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable || !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels)
+	},
+}
+
+// allBudgets returns the requests of all the CordonBudgets, since a node may have been selected by any of them
+// before its labels changed.
+func (c *CordonBudgetController) allBudgets(ctx context.Context, _ client.Object) []reconcile.Request {
+	budgets := v1alpha1.CordonBudgetList{}
+	if err := c.Client.List(ctx, &budgets); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list the cordon budgets")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(budgets.Items))
+	for _, budget := range budgets.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&budget)})
+	}
+	return requests
+}
+
+// Reconcile updates the status of the CordonBudget with the number of the nodes it selects and of the cordoned ones.
+func (c *CordonBudgetController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("Cordon Budget")
+
+	budget := v1alpha1.CordonBudget{}
+	if err := c.Client.Get(ctx, req.NamespacedName, &budget); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&budget.Spec.Selector)
+	if err != nil {
+		logger.Info("Invalid selector, the status of the cordon budget is not updated", "CordonBudget", budget.Name, "Error", err.Error())
+		return ctrl.Result{}, nil
+	}
+	nodes := corev1.NodeList{}
+	if err := c.Client.List(ctx, &nodes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	selected, cordoned := countBudgetNodes(nodes.Items, selector, "")
+	status := v1alpha1.CordonBudgetStatus{CurrentCordoned: int32(cordoned), SelectedNodes: int32(selected)}
+	if budget.Status == status {
+		return ctrl.Result{}, nil
+	}
+	budget.Status = status
+	if err := c.Client.Status().Update(ctx, &budget); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the status of CordonBudget %q: %w", budget.Name, err)
+	}
+	logger.V(1).Info("Updated the status of the cordon budget", "CordonBudget", budget.Name, "CurrentCordoned", cordoned, "SelectedNodes", selected)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+// newCordonBudget returns a CordonBudget selecting the nodes of the pool.
+func newCordonBudget(name string, pool string, maxUnavailable intstr.IntOrString) *v1alpha1.CordonBudget {
+	return &v1alpha1.CordonBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.CordonBudgetSpec{
+			Selector:       metav1.LabelSelector{MatchLabels: map[string]string{"pool": pool}},
+			MaxUnavailable: maxUnavailable,
+		},
+	}
+}
+
+// createPoolNodes creates the nodes of the pool, the first cordoned ones of which are cordoned.
+func createPoolNodes(ctx context.Context, g *WithT, c client.Client, pool string, nodes int, cordoned int) {
+	for i := 0; i < nodes; i++ {
+		g.Expect(c.Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", pool, i), Labels: map[string]string{"pool": pool}},
+			Spec:       corev1.NodeSpec{Unschedulable: i < cordoned},
+		})).Should(Succeed())
+	}
+}
+
+func TestCordonBudget(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable intstr.IntOrString
+		cordoned       int
+		pool           string
+		allowed        bool
+	}{
+		{name: "BelowBudget", maxUnavailable: intstr.FromInt32(2), cordoned: 1, pool: "gpu", allowed: true},
+		{name: "BudgetReached", maxUnavailable: intstr.FromInt32(2), cordoned: 2, pool: "gpu", allowed: false},
+		// 25% of the 5 nodes, rounded up, is 2.
+		{name: "PercentageBelowBudget", maxUnavailable: intstr.FromString("25%"), cordoned: 1, pool: "gpu", allowed: true},
+		{name: "PercentageBudgetReached", maxUnavailable: intstr.FromString("25%"), cordoned: 2, pool: "gpu", allowed: false},
+		{name: "NodeNotSelected", maxUnavailable: intstr.FromInt32(0), cordoned: 2, pool: "cpu", allowed: true},
+		{name: "InvalidBudget", maxUnavailable: intstr.FromString("many"), cordoned: 2, pool: "gpu", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, newCordonBudget("gpu", "gpu", test.maxUnavailable))).Should(Succeed())
+			createPoolNodes(ctx, g, fakeClient, "gpu", 4, test.cordoned)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "new-node", Labels: map[string]string{"pool": test.pool},
+				Annotations: map[string]string{reasonAnnotation: "Testing"}}}
+			cordonedNode := *node.DeepCopy()
+			cordonedNode.Spec.Unschedulable = true
+			response := nv.Handle(ctx, newUpdateRequest(g, regularUserExample, node, cordonedNode))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(CordonBudgetExceededCode))
+				g.Expect(detail.Message).Should(ContainSubstring(`2 of the 5 nodes of CordonBudget "gpu" are already cordoned`))
+			}
+		})
+	}
+}
+
+func TestCordonBudgetController(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithStatusSubresource(&v1alpha1.CordonBudget{}).Build()
+	g.Expect(fakeClient.Create(ctx, newCordonBudget("gpu", "gpu", intstr.FromInt32(1)))).Should(Succeed())
+	createPoolNodes(ctx, g, fakeClient, "gpu", 3, 2)
+	createPoolNodes(ctx, g, fakeClient, "cpu", 2, 1)
+	controller := &CordonBudgetController{Client: fakeClient}
+	request := ctrl.Request{NamespacedName: client.ObjectKey{Name: "gpu"}}
+
+	_, err := controller.Reconcile(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	budget := v1alpha1.CordonBudget{}
+	g.Expect(fakeClient.Get(ctx, request.NamespacedName, &budget)).Should(Succeed())
+	g.Expect(budget.Status).Should(Equal(v1alpha1.CordonBudgetStatus{CurrentCordoned: 2, SelectedNodes: 3}))
+
+	// The status follows the uncordons.
+	node := corev1.Node{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "gpu-0"}, &node)).Should(Succeed())
+	node.Spec.Unschedulable = false
+	g.Expect(fakeClient.Update(ctx, &node)).Should(Succeed())
+	g.Expect(controller.allBudgets(ctx, &node)).Should(ConsistOf(request))
+	_, err = controller.Reconcile(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(fakeClient.Get(ctx, request.NamespacedName, &budget)).Should(Succeed())
+	g.Expect(budget.Status.CurrentCordoned).Should(Equal(int32(1)))
+
+	// A deleted budget is ignored.
+	_, err = controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Name: "missing"}})
+	g.Expect(err).ShouldNot(HaveOccurred())
+}
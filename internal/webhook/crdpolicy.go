@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=dana.io,resources=nodeoperationpolicies,verbs=get;list;watch
+
+// PolicyClient lists the NodeOperationPolicy objects.
+type PolicyClient interface {
+	ListPolicies(ctx context.Context) ([]v1alpha1.NodeOperationPolicy, error)
+}
+
+// KubernetesPolicyClient lists the NodeOperationPolicy objects using a Kubernetes client.
+// When the client reads from the cache of the manager, the objects are watched.
+type KubernetesPolicyClient struct {
+	Client client.Client
+}
+
+// ListPolicies returns all the NodeOperationPolicy objects. If the CRD isn't installed, there are no objects.
+func (c *KubernetesPolicyClient) ListPolicies(ctx context.Context) ([]v1alpha1.NodeOperationPolicy, error) {
+	policies := v1alpha1.NodeOperationPolicyList{}
+	if err := c.Client.List(ctx, &policies); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list NodeOperationPolicies: %w", err)
+	}
+	return policies.Items, nil
+}
+
+// CRDPolicyResolver resolves policies from NodeOperationPolicy objects. The objects are matched
+// in the order of their names, and the first one whose node selector matches the node's labels is used,
+// preferring the objects scoped to the namespace of the requesting service account. If none matches,
+// which is always the case when there are no objects, the Fallback resolver is used.
+type CRDPolicyResolver struct {
+	PolicyClient PolicyClient
+	Fallback     PolicyResolver
+}
+
+// Resolve returns the policy of the NodeOperationPolicy resolved for the requesting user of the context
+// and the node, or the fallback policy if none matches.
+func (r *CRDPolicyResolver) Resolve(ctx context.Context, node *corev1.Node) (Policy, error) {
+	policies, err := r.PolicyClient.ListPolicies(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	policy, err := resolvePolicyForUser(requestUserFrom(ctx), node, policies)
+	if err != nil {
+		return Policy{}, err
+	}
+	if policy != nil {
+		return policyFromNodeOperationPolicy(policy)
+	}
+	return r.Fallback.Resolve(ctx, node)
+}
+
+// resolvePolicyForUser returns the first NodeOperationPolicy, in the order of their names, whose node selector
+// matches the node's labels and whose scope is the namespace of the user, a service account.
+// If there is none, it returns the first matching NodeOperationPolicy without a scope, or nil if none matches.
+// The policies scoped to other namespaces never apply.
+func resolvePolicyForUser(user string, node *corev1.Node, policies []v1alpha1.NodeOperationPolicy) (*v1alpha1.NodeOperationPolicy, error) {
+	policies = slices.Clone(policies)
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+
+	namespace, isServiceAccount := serviceAccountNamespace(user, serviceAccountUser)
+	var clusterPolicy *v1alpha1.NodeOperationPolicy
+	for i, policy := range policies {
+		if policy.Spec.Scope != "" && (!isServiceAccount || policy.Spec.Scope != namespace) {
+			continue
+		}
+		if policy.Spec.Scope == "" && clusterPolicy != nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node selector for NodeOperationPolicy %q: %w", policy.Name, err)
+		}
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if policy.Spec.Scope != "" {
+			return &policies[i], nil
+		}
+		clusterPolicy = &policies[i]
+	}
+	return clusterPolicy, nil
+}
+
+// requestUserKey is the context key of the requesting user.
+type requestUserKey struct{}
+
+// withRequestUser returns a copy of the context holding the requesting user, according to which
+// the scoped NodeOperationPolicy objects are resolved.
+func withRequestUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, requestUserKey{}, user)
+}
+
+// requestUserFrom returns the requesting user held by the context, or an empty string if there is none.
+func requestUserFrom(ctx context.Context) string {
+	user, _ := ctx.Value(requestUserKey{}).(string)
+	return user
+}
+
+// policyFromNodeOperationPolicy converts the spec of a NodeOperationPolicy to a policy. The service accounts
+// of the scope of a scoped policy aren't trusted, so that their operations are validated by the policy.
+func policyFromNodeOperationPolicy(policy *v1alpha1.NodeOperationPolicy) (Policy, error) {
+	if len(policy.Spec.AllowedReasons) == 0 && policy.Spec.ReasonRegexPattern == "" {
+		return Policy{}, fmt.Errorf("NodeOperationPolicy %q must set either allowedReasons or reasonRegexPattern", policy.Name)
+	}
+	result := Policy{
+		AllowedReasons:     policy.Spec.AllowedReasons,
+		ReasonRegexPattern: policy.Spec.ReasonRegexPattern,
+		ForbiddenUsers:     policy.Spec.ForbiddenUsers,
+		ForbiddenGroups:    policy.Spec.ForbiddenGroups,
+		WarnOnly:           policy.Spec.WarnOnly,
+	}
+	if policy.Spec.Scope != "" {
+		result.TrustedServiceAccountNamespaces = []string{metav1.NamespaceSystem}
+	}
+	return result, nil
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+func TestCRDPolicyResolver(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	resolver := CRDPolicyResolver{
+		PolicyClient: &KubernetesPolicyClient{Client: fakeClient},
+		Fallback:     &ConfigMapPolicyResolver{Client: fakeClient, Namespace: cmNamespace},
+	}
+	master := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{masterRoleLabel: ""}}}
+	worker := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{workerRoleLabel: ""}}}
+
+	t.Run("NoPoliciesFallback", func(t *testing.T) {
+		policy, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Testing"}))
+	})
+
+	masterPolicy := v1alpha1.NodeOperationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "master"},
+		Spec: v1alpha1.NodeOperationPolicySpec{
+			AllowedReasons:  []string{"Maintenance"},
+			ForbiddenGroups: []string{"developers"},
+			NodeSelector:    metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: masterRoleLabel, Operator: metav1.LabelSelectorOpExists}}},
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		g.Expect(fakeClient.Create(ctx, &masterPolicy)).Should(Succeed())
+
+		policy, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Maintenance"}))
+		g.Expect(policy.ForbiddenGroups).Should(Equal([]string{"developers"}))
+	})
+
+	t.Run("NoMatchFallback", func(t *testing.T) {
+		policy, err := resolver.Resolve(ctx, &worker)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Testing"}))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		masterPolicy.Spec.WarnOnly = true
+		g.Expect(fakeClient.Update(ctx, &masterPolicy)).Should(Succeed())
+
+		policy, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.WarnOnly).Should(BeTrue())
+	})
+
+	t.Run("FirstMatchByName", func(t *testing.T) {
+		g.Expect(fakeClient.Create(ctx, &v1alpha1.NodeOperationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "all-nodes"},
+			Spec:       v1alpha1.NodeOperationPolicySpec{ReasonRegexPattern: "^JIRA-[0-9]+$"},
+		})).Should(Succeed())
+
+		policy, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.ReasonRegexPattern).Should(Equal("^JIRA-[0-9]+$"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		g.Expect(fakeClient.DeleteAllOf(ctx, &v1alpha1.NodeOperationPolicy{})).Should(Succeed())
+
+		policy, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Testing"}))
+	})
+
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		g.Expect(fakeClient.Create(ctx, &v1alpha1.NodeOperationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "invalid"}})).Should(Succeed())
+
+		_, err := resolver.Resolve(ctx, &master)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestForbiddenGroups(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &v1alpha1.NodeOperationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "all-nodes"},
+		Spec:       v1alpha1.NodeOperationPolicySpec{AllowedReasons: []string{"Testing"}, ForbiddenGroups: []string{"developers"}},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+	annotations := map[string]string{reasonAnnotation: "Testing"}
+
+	request := newCordonRequest(g, "forbidden-group", regularUserExample, annotations)
+	request.UserInfo.Groups = []string{"developers"}
+	response := nv.Handle(ctx, request)
+	g.Expect(response.Allowed).Should(BeFalse())
+	g.Expect(denialDetail(g, response).Code).Should(Equal(ForbiddenUserCode))
+
+	request = newCordonRequest(g, "allowed-group", regularUserExample, annotations)
+	request.UserInfo.Groups = []string{"sre"}
+	response = nv.Handle(ctx, request)
+	g.Expect(response.Allowed).Should(BeTrue())
+}
+
+func TestResolvePolicyForUser(t *testing.T) {
+	const (
+		teamAUser = serviceAccountUser + "team-a:deployer"
+		teamBUser = serviceAccountUser + "team-b:deployer"
+	)
+	newPolicy := func(name string, scope string, team string) v1alpha1.NodeOperationPolicy {
+		policy := v1alpha1.NodeOperationPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: v1alpha1.NodeOperationPolicySpec{Scope: scope}}
+		if team != "" {
+			policy.Spec.NodeSelector = metav1.LabelSelector{MatchLabels: map[string]string{"team": team}}
+		}
+		return policy
+	}
+	policies := []v1alpha1.NodeOperationPolicy{
+		newPolicy("team-b", "team-b", "b"),
+		newPolicy("cluster", "", ""),
+		newPolicy("team-a", "team-a", "a"),
+		newPolicy("team-a-shared", "team-a", "shared"),
+		newPolicy("team-b-shared", "team-b", "shared"),
+	}
+
+	tests := []struct {
+		name     string
+		user     string
+		team     string
+		expected string
+	}{
+		{name: "TeamAUserOnTeamANode", user: teamAUser, team: "a", expected: "team-a"},
+		{name: "TeamBUserOnTeamBNode", user: teamBUser, team: "b", expected: "team-b"},
+		{name: "TeamAUserOnTeamBNode", user: teamAUser, team: "b", expected: "cluster"},
+		{name: "TeamBUserOnSharedNode", user: teamBUser, team: "shared", expected: "team-b-shared"},
+		{name: "RegularUserOnTeamANode", user: regularUserExample, team: "a", expected: "cluster"},
+		{name: "OtherNamespaceUser", user: serviceAccountUser + "team-c:deployer", team: "a", expected: "cluster"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"team": test.team}}}
+
+			policy, err := resolvePolicyForUser(test.user, &node, policies)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(policy).ShouldNot(BeNil())
+			g.Expect(policy.Name).Should(Equal(test.expected))
+		})
+	}
+
+	t.Run("NoMatch", func(t *testing.T) {
+		g := NewWithT(t)
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"team": "a"}}}
+
+		policy, err := resolvePolicyForUser(teamBUser, &node, policies[:1])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy).Should(BeNil())
+	})
+}
+
+func TestScopedPolicies(t *testing.T) {
+	const (
+		teamAUser = serviceAccountUser + "team-a:deployer"
+		teamBUser = serviceAccountUser + "team-b:deployer"
+	)
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	shared := metav1.LabelSelector{MatchLabels: map[string]string{"pool": "shared"}}
+	for _, policy := range []*v1alpha1.NodeOperationPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: v1alpha1.NodeOperationPolicySpec{AllowedReasons: []string{"Maintenance"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}, Spec: v1alpha1.NodeOperationPolicySpec{AllowedReasons: []string{"Upgrade"}, NodeSelector: shared, Scope: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}, Spec: v1alpha1.NodeOperationPolicySpec{AllowedReasons: []string{"Benchmark"}, NodeSelector: shared, Scope: "team-b"}},
+	} {
+		g.Expect(fakeClient.Create(ctx, policy)).Should(Succeed())
+	}
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	tests := []struct {
+		name    string
+		user    string
+		labels  map[string]string
+		reason  string
+		allowed bool
+	}{
+		{name: "TeamAUserGetsTeamAPolicy", user: teamAUser, labels: map[string]string{"pool": "shared"}, reason: "Upgrade", allowed: true},
+		{name: "TeamBUserDeniedByTeamBPolicy", user: teamBUser, labels: map[string]string{"pool": "shared"}, reason: "Upgrade", allowed: false},
+		{name: "TeamBUserGetsTeamBPolicy", user: teamBUser, labels: map[string]string{"pool": "shared"}, reason: "Benchmark", allowed: true},
+		{name: "FallbackToClusterPolicy", user: teamAUser, labels: map[string]string{"pool": "dedicated"}, reason: "Maintenance", allowed: true},
+		{name: "RegularUserGetsClusterPolicy", user: regularUserExample, labels: map[string]string{"pool": "shared"}, reason: "Upgrade", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			request := newCordonRequest(g, "node", test.user, map[string]string{reasonAnnotation: test.reason})
+			node := corev1.Node{}
+			g.Expect(json.Unmarshal(request.Object.Raw, &node)).Should(Succeed())
+			node.Labels = test.labels
+			raw, err := json.Marshal(node)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			request.Object.Raw = raw
+
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(InvalidReasonCode))
+			}
+		})
+	}
+}
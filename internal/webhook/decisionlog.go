@@ -0,0 +1,41 @@
+package webhook
+
+import "github.com/go-logr/logr"
+
+// Decisions of the admission of an operation.
+const (
+	decisionAllowed = "allowed"
+	decisionDenied  = "denied"
+)
+
+// decisionLog describes the admission decision of an operation on a node, so that every decision
+// is logged with the same fields.
+type decisionLog struct {
+	Node      string
+	User      string
+	Operation Operation
+	// Decision is either decisionAllowed or decisionDenied.
+	Decision string
+	// DenialCode is the code of the denial. It is empty for allowed operations.
+	DenialCode string
+	// Reason is the reason of the operation.
+	Reason string
+	// Grounds briefly describes why the operation was allowed or denied.
+	Grounds string
+	// Details are additional key-value pairs specific to the decision.
+	Details []any
+}
+
+// logDecision logs the admission decision with its fields as key-value pairs.
+func logDecision(logger logr.Logger, d decisionLog) {
+	keysAndValues := []any{
+		"Node", d.Node,
+		"User", d.User,
+		"Operation", d.Operation,
+		"Decision", d.Decision,
+		"DenialCode", d.DenialCode,
+		"Reason", d.Reason,
+		"Grounds", d.Grounds,
+	}
+	logger.Info("Admission decision", append(keysAndValues, d.Details...)...)
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/testr"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// logCapture captures the log entries of a testr logger.
+type logCapture struct {
+	entries []string
+}
+
+func (l *logCapture) Helper() {}
+
+func (l *logCapture) Log(args ...any) {
+	l.entries = append(l.entries, fmt.Sprint(args...))
+}
+
+// decisionEntries returns the captured entries of the admission decisions.
+func (l *logCapture) decisionEntries() []string {
+	var entries []string
+	for _, entry := range l.entries {
+		if strings.Contains(entry, `"msg"="Admission decision"`) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestDecisionLog(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   Operation
+		annotations map[string]string
+		decision    string
+		denialCode  string
+		reason      string
+	}{
+		{name: "CreateAllowed", operation: Create, decision: decisionAllowed},
+		{name: "CreateDenied", operation: Create, annotations: map[string]string{reasonAnnotation: "Testing"}, decision: decisionDenied, denialCode: UnexpectedReasonCode},
+		{name: "DeleteAllowed", operation: Delete, annotations: map[string]string{reasonAnnotation: "Testing"}, decision: decisionAllowed, reason: "Testing"},
+		{name: "DeleteDenied", operation: Delete, decision: decisionDenied, denialCode: MissingReasonCode},
+		{name: "CordonAllowed", operation: Cordon, annotations: map[string]string{reasonAnnotation: "Testing"}, decision: decisionAllowed, reason: "Testing"},
+		{name: "CordonDenied", operation: Cordon, annotations: map[string]string{reasonAnnotation: "for fun"}, decision: decisionDenied, denialCode: InvalidReasonCode, reason: "for fun"},
+		{name: "UncordonAllowed", operation: Uncordon, decision: decisionAllowed},
+		{name: "UncordonDenied", operation: Uncordon, annotations: map[string]string{reasonAnnotation: "Testing"}, decision: decisionDenied, denialCode: UnexpectedReasonCode},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			capture := &logCapture{}
+			ctx := logr.NewContext(context.Background(), testr.NewWithInterface(capture, testr.Options{}))
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			var request admission.Request
+			switch test.operation {
+			case Create:
+				request = newCordonRequest(g, test.name, regularUserExample, test.annotations)
+				request.Operation, request.OldObject = admissionv1.Create, runtime.RawExtension{}
+			case Delete:
+				request = newDeleteRequest(g, test.name, regularUserExample, test.annotations)
+			case Cordon:
+				request = newCordonRequest(g, test.name, regularUserExample, test.annotations)
+			case Uncordon:
+				node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name, Annotations: test.annotations}, Spec: corev1.NodeSpec{Unschedulable: true}}
+				uncordonedNode := *node.DeepCopy()
+				uncordonedNode.Spec.Unschedulable = false
+				request = newUpdateRequest(g, regularUserExample, node, uncordonedNode)
+			}
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.decision == decisionAllowed))
+
+			entries := capture.decisionEntries()
+			g.Expect(entries).Should(HaveLen(1))
+			g.Expect(entries[0]).Should(And(
+				ContainSubstring(fmt.Sprintf(`"Node"=%q`, test.name)),
+				ContainSubstring(fmt.Sprintf(`"User"=%q`, regularUserExample)),
+				ContainSubstring(fmt.Sprintf(`"Operation"=%q`, test.operation)),
+				ContainSubstring(fmt.Sprintf(`"Decision"=%q`, test.decision)),
+				ContainSubstring(fmt.Sprintf(`"DenialCode"=%q`, test.denialCode)),
+				ContainSubstring(fmt.Sprintf(`"Reason"=%q`, test.reason)),
+				ContainSubstring(`"Grounds"=`),
+			))
+		})
+	}
+}
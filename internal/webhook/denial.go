@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Denial codes identifying why an operation was denied.
+const (
+	ForbiddenUserCode            = "ForbiddenUser"
+	OperationNotAllowedCode      = "OperationNotAllowed"
+	MissingReasonCode            = "MissingReason"
+	InvalidReasonCode            = "InvalidReason"
+	InvalidReasonLengthCode      = "InvalidReasonLength"
+	InvalidReasonFormatCode      = "InvalidReasonFormat"
+	PlaceholderReasonCode        = "PlaceholderReason"
+	InvalidReasonCategoryCode    = "InvalidReasonCategory"
+	InvalidPriorityCode          = "InvalidPriority"
+	UnexpectedReasonCode         = "UnexpectedReason"
+	OutsideMaintenanceWindowCode = "OutsideMaintenanceWindow"
+	OutsideOperationWindowCode   = "OutsideOperationWindow"
+	InvalidOperationWindowCode   = "InvalidOperationWindow"
+	ZoneCordonLimitCode          = "ZoneCordonLimit"
+	MissingAttestationCode       = "MissingAttestation"
+	InvalidAttestationCode       = "InvalidAttestation"
+	MissingTicketCode            = "MissingTicket"
+	InvalidTicketStatusCode      = "InvalidTicketStatus"
+	RateLimitedCode              = "RateLimited"
+	RiskScoreExceededCode        = "RiskScoreExceeded"
+	MissingReasonAuthorCode      = "MissingReasonAuthor"
+	ReasonAuthorMismatchCode     = "ReasonAuthorMismatch"
+	CordonCooloffCode            = "CordonCooloff"
+	NodeVelocityExceededCode     = "NodeVelocityExceeded"
+	ExternalPolicyDeniedCode     = "ExternalPolicyDenied"
+	DrainSimulationFailedCode    = "DrainSimulationFailed"
+	MissingChangeRequestCode     = "MissingChangeRequest"
+	InsufficientCapacityCode     = "InsufficientCapacity"
+	CordonBudgetExceededCode     = "CordonBudgetExceeded"
+)
+
+// denialCodes are all the denial codes.
+var denialCodes = []string{
+	ForbiddenUserCode, OperationNotAllowedCode, MissingReasonCode, InvalidReasonCode, InvalidReasonLengthCode,
+	InvalidReasonFormatCode, PlaceholderReasonCode, InvalidReasonCategoryCode, InvalidPriorityCode, UnexpectedReasonCode,
+	OutsideMaintenanceWindowCode, OutsideOperationWindowCode, InvalidOperationWindowCode, ZoneCordonLimitCode,
+	MissingAttestationCode, InvalidAttestationCode, MissingTicketCode, InvalidTicketStatusCode, RateLimitedCode,
+	RiskScoreExceededCode, MissingReasonAuthorCode, ReasonAuthorMismatchCode, CordonCooloffCode,
+	NodeVelocityExceededCode, ExternalPolicyDeniedCode, DrainSimulationFailedCode, MissingChangeRequestCode,
+	InsufficientCapacityCode, CordonBudgetExceededCode,
+}
+
+// Status codes of the denials, set in the code of the admission response result so that the consumers of the
+// responses can tell the denial reasons apart without parsing the message. The denials without a specific
+// status code use http.StatusForbidden. None of them is a status code which clients retry, such as
+// http.StatusConflict, since a denied operation doesn't succeed when retried as is.
+const (
+	CodeForbiddenUser       int32 = http.StatusForbidden
+	CodeOperationNotAllowed int32 = http.StatusMethodNotAllowed
+	CodeReasonNotAllowed    int32 = http.StatusNotAcceptable
+	CodeUnexpectedReason    int32 = http.StatusUnprocessableEntity
+	CodeInvalidReason       int32 = http.StatusUnprocessableEntity
+	CodeMissingReason       int32 = http.StatusPreconditionRequired
+)
+
+// denialStatusCodes maps the denial codes to the status codes of their responses.
+var denialStatusCodes = map[string]int32{
+	ForbiddenUserCode:       CodeForbiddenUser,
+	OperationNotAllowedCode: CodeOperationNotAllowed,
+	InvalidReasonCode:       CodeReasonNotAllowed,
+	UnexpectedReasonCode:    CodeUnexpectedReason,
+	InvalidReasonLengthCode: CodeInvalidReason,
+	InvalidReasonFormatCode: CodeInvalidReason,
+	PlaceholderReasonCode:   CodeInvalidReason,
+	MissingReasonCode:       CodeMissingReason,
+}
+
+// DenialDetail describes a denied operation. It is serialized to JSON as the message
+// of the admission response, so that it can be parsed by tools such as CI pipelines.
+type DenialDetail struct {
+	Code           string    `json:"code"`
+	Operation      Operation `json:"operation,omitempty"`
+	User           string    `json:"user,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	AllowedReasons []string  `json:"allowedReasons,omitempty"`
+	Pattern        string    `json:"pattern,omitempty"`
+	// Message is the human-readable description of the denial.
+	Message string `json:"message"`
+}
+
+// deniedWithDetail returns a denial response whose message is the JSON serialized detail, whose reason
+// is the human-readable message of the detail, and whose code is the status code of the denial code.
+func deniedWithDetail(detail DenialDetail) admission.Response {
+	message, err := json.Marshal(detail)
+	if err != nil {
+		message = []byte(detail.Message)
+	}
+
+	code, ok := denialStatusCodes[detail.Code]
+	if !ok {
+		code = http.StatusForbidden
+	}
+	response := deniedWithCode(code, string(message))
+	response.Result.Reason = metav1.StatusReason(detail.Message)
+	return response
+}
+
+// deniedWithCode returns a denial response with the given status code and message.
+func deniedWithCode(code int32, msg string) admission.Response {
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    code,
+				Message: msg,
+			},
+		},
+	}
+}
+
+// isDenied checks if the response is a denial, as opposed to an allowed response or an error.
+func isDenied(response admission.Response) bool {
+	if response.Allowed || response.Result == nil {
+		return false
+	}
+	return response.Result.Code == http.StatusForbidden || slices.Contains(slices.Collect(maps.Values(denialStatusCodes)), response.Result.Code)
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// denialDetail unmarshals the denial detail of a denied response.
+func denialDetail(g *WithT, response admission.Response) DenialDetail {
+	g.Expect(response.Allowed).Should(BeFalse())
+	detail := DenialDetail{}
+	g.Expect(json.Unmarshal([]byte(response.Result.Message), &detail)).Should(Succeed())
+	return detail
+}
+
+func TestDeniedWithDetail(t *testing.T) {
+	g := NewWithT(t)
+	detail := DenialDetail{Code: InvalidReasonCode, Operation: Cordon, User: regularUserExample, Reason: "for fun", Message: "Invalid reason"}
+
+	response := deniedWithDetail(detail)
+	g.Expect(response.Result.Code).Should(Equal(CodeReasonNotAllowed))
+	g.Expect(string(response.Result.Reason)).Should(Equal(detail.Message))
+	g.Expect(denialDetail(g, response)).Should(Equal(detail))
+}
+
+func TestDenialDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     string
+		reason   string
+		code     int32
+		expected DenialDetail
+	}{
+		{name: "ForbiddenUser", user: systemAdminUser, reason: "Testing", code: CodeForbiddenUser, expected: DenialDetail{Code: ForbiddenUserCode, Operation: Cordon, User: systemAdminUser}},
+		{name: "MissingReason", user: regularUserExample, code: CodeMissingReason, expected: DenialDetail{Code: MissingReasonCode, Operation: Cordon, User: regularUserExample, AllowedReasons: []string{"Testing"}}},
+		{name: "InvalidReason", user: regularUserExample, reason: "for fun", code: CodeReasonNotAllowed, expected: DenialDetail{Code: InvalidReasonCode, Operation: Cordon, User: regularUserExample, Reason: "for fun", AllowedReasons: []string{"Testing"}}},
+	}
+
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g := NewWithT(t)
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			annotations := map[string]string{}
+			if test.reason != "" {
+				annotations[reasonAnnotation] = test.reason
+			}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Result.Code).Should(Equal(test.code))
+			detail := denialDetail(g, response)
+			g.Expect(detail.Message).ShouldNot(BeEmpty())
+			detail.Message = ""
+			g.Expect(detail).Should(Equal(test.expected))
+		})
+	}
+}
+
+func TestDenialStatusCodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		user        string
+		annotations map[string]string
+		code        int32
+	}{
+		{name: "ForbiddenUser", operation: admissionv1.Update, user: systemAdminUser, annotations: map[string]string{reasonAnnotation: "Testing"}, code: CodeForbiddenUser},
+		{name: "MissingReason", operation: admissionv1.Update, user: regularUserExample, annotations: map[string]string{}, code: CodeMissingReason},
+		{name: "ReasonNotAllowed", operation: admissionv1.Update, user: regularUserExample, annotations: map[string]string{reasonAnnotation: "for fun"}, code: CodeReasonNotAllowed},
+		{name: "InvalidReason", operation: admissionv1.Update, user: regularUserExample, annotations: map[string]string{reasonAnnotation: "Testing Testing Testing"}, code: CodeInvalidReason},
+		{name: "UnexpectedReasonOnCreate", operation: admissionv1.Create, user: regularUserExample, annotations: map[string]string{reasonAnnotation: "Testing"}, code: CodeUnexpectedReason},
+		{name: "OperationNotAllowed", operation: admissionv1.Update, user: "restricted-user", annotations: map[string]string{reasonAnnotation: "Testing"}, code: CodeOperationNotAllowed},
+	}
+
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g := NewWithT(t)
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data: map[string]string{
+			reasonRegexPatternKey: "^Testing.*",
+			reasonMaxLengthKey:    "10",
+			operationAllowlistKey: "restricted-user=uncordon",
+		},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			request := newCordonRequest(g, test.name, test.user, test.annotations)
+			if test.operation == admissionv1.Create {
+				request.Operation, request.OldObject = admissionv1.Create, runtime.RawExtension{}
+			}
+
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(BeFalse())
+			g.Expect(response.Result.Code).Should(Equal(test.code))
+			g.Expect(isDenied(response)).Should(BeTrue())
+		})
+	}
+}
+
+func TestIsDenied(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(isDenied(deniedWithCode(CodeMissingReason, "denied"))).Should(BeTrue())
+	g.Expect(isDenied(deniedWithCode(http.StatusForbidden, "denied"))).Should(BeTrue())
+	g.Expect(isDenied(admission.Errored(http.StatusInternalServerError, errors.New("failed")))).Should(BeFalse())
+	g.Expect(isDenied(admission.Errored(http.StatusBadRequest, errors.New("failed")))).Should(BeFalse())
+	g.Expect(isDenied(admission.Allowed("allowed"))).Should(BeFalse())
+}
+
+func TestDenialStatusCodesNotRetried(t *testing.T) {
+	g := NewWithT(t)
+	for code, statusCode := range denialStatusCodes {
+		g.Expect(statusCode).ShouldNot(BeElementOf(int32(http.StatusConflict), int32(http.StatusTooManyRequests)), code)
+		g.Expect(statusCode).Should(BeNumerically("<", http.StatusInternalServerError), code)
+	}
+}
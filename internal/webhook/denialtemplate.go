@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+)
+
+// denialTemplateData holds the variables available to the denial message templates.
+type denialTemplateData struct {
+	Code           string
+	User           string
+	Operation      Operation
+	NodeName       string
+	Reason         string
+	AllowedReasons []string
+	Pattern        string
+}
+
+// parseDenialMessageTemplates parses a YAML map of denial codes to Go text/template strings,
+// e.g. `MissingReason: "{{.User}} must annotate {{.NodeName}} before {{.Operation}}"`.
+func parseDenialMessageTemplates(value string) (map[string]*template.Template, error) {
+	texts := map[string]string{}
+	if err := yaml.Unmarshal([]byte(value), &texts); err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]*template.Template, len(texts))
+	for code, text := range texts {
+		if !slices.Contains(denialCodes, code) {
+			return nil, fmt.Errorf("unknown denial code %q", code)
+		}
+		tmpl, err := template.New(code).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of %q: %w", code, err)
+		}
+		templates[code] = tmpl
+	}
+	return templates, nil
+}
+
+// withDenialMessageTemplate rewrites the message of a denial on the node with the template of its denial code
+// in the policy. Other responses, denials without a template, and denials whose template fails to execute
+// are returned as they are.
+func withDenialMessageTemplate(response admission.Response, node *corev1.Node, policy Policy) admission.Response {
+	if len(policy.DenialMessageTemplates) == 0 || !isDenied(response) {
+		return response
+	}
+
+	detail := DenialDetail{}
+	if err := json.Unmarshal([]byte(response.Result.Message), &detail); err != nil {
+		return response
+	}
+	tmpl, ok := policy.DenialMessageTemplates[detail.Code]
+	if !ok {
+		return response
+	}
+	message := strings.Builder{}
+	if err := tmpl.Execute(&message, denialTemplateData{
+		Code:           detail.Code,
+		User:           detail.User,
+		Operation:      detail.Operation,
+		NodeName:       node.Name,
+		Reason:         detail.Reason,
+		AllowedReasons: policy.AllowedReasons,
+		Pattern:        policy.ReasonRegexPattern,
+	}); err != nil {
+		return response
+	}
+	detail.Message = message.String()
+
+	denial := deniedWithDetail(detail)
+	response.Result.Reason, response.Result.Message = denial.Result.Reason, denial.Result.Message
+	return response
+}
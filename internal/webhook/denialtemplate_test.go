@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDenialMessageTemplates(t *testing.T) {
+	tests := []struct {
+		name        string
+		templates   string
+		user        string
+		annotations map[string]string
+		message     string
+	}{
+		{
+			name:        "MissingReason",
+			templates:   `MissingReason: "{{.User}} must explain why {{.NodeName}} needs a {{.Operation}}, e.g. {{index .AllowedReasons 0}}"`,
+			user:        regularUserExample,
+			annotations: map[string]string{},
+			message:     regularUserExample + " must explain why MissingReason needs a cordon, e.g. Testing",
+		},
+		{
+			name:        "InvalidReason",
+			templates:   `InvalidReason: "{{printf \"%q\" .Reason}} isn't one of {{.AllowedReasons}}"`,
+			user:        regularUserExample,
+			annotations: map[string]string{reasonAnnotation: "for fun"},
+			message:     `"for fun" isn't one of [Testing Maintenance]`,
+		},
+		{
+			name:        "ForbiddenUser",
+			templates:   `ForbiddenUser: "{{.User}} can't {{.Operation}} nodes"`,
+			user:        systemAdminUser,
+			annotations: map[string]string{reasonAnnotation: "Testing"},
+			message:     systemAdminUser + " can't cordon nodes",
+		},
+		{
+			name:        "OtherCode",
+			templates:   `ForbiddenUser: "{{.User}} can't {{.Operation}} nodes"`,
+			user:        regularUserExample,
+			annotations: map[string]string{},
+			message:     `You must add "node.dana.io/reason" annotation`,
+		},
+		{
+			name:        "FailingTemplate",
+			templates:   `MissingReason: "{{.Unknown}}"`,
+			user:        regularUserExample,
+			annotations: map[string]string{},
+			message:     `You must add "node.dana.io/reason" annotation`,
+		},
+		{
+			name:        "NotConfigured",
+			user:        regularUserExample,
+			annotations: map[string]string{},
+			message:     `You must add "node.dana.io/reason" annotation`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing,Maintenance"}
+			if test.templates != "" {
+				data[denialTemplatesKey] = test.templates
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, test.annotations))
+			g.Expect(response.Allowed).Should(BeFalse())
+			detail := DenialDetail{}
+			g.Expect(json.Unmarshal([]byte(response.Result.Message), &detail)).Should(Succeed())
+			g.Expect(detail.Message).Should(Equal(test.message))
+			g.Expect(string(response.Result.Reason)).Should(Equal(test.message))
+		})
+	}
+}
+
+func TestParseDenialMessageTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		codes   []string
+		wantErr bool
+	}{
+		{name: "Empty", value: "", codes: []string{}},
+		{name: "Valid", value: "MissingReason: \"{{.User}}\"\nRateLimited: \"slow down {{.User}}\"", codes: []string{MissingReasonCode, RateLimitedCode}},
+		{name: "UnknownCode", value: `missing-reason: "{{.User}}"`, wantErr: true},
+		{name: "InvalidTemplate", value: `MissingReason: "{{.User"`, wantErr: true},
+		{name: "InvalidYAML", value: "MissingReason: [", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			templates, err := parseDenialMessageTemplates(test.value)
+			if test.wantErr {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(templates).Should(HaveLen(len(test.codes)))
+			for _, code := range test.codes {
+				g.Expect(templates).Should(HaveKey(code))
+			}
+		})
+	}
+}
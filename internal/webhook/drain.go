@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	outOfServiceAnnotation   = "node.kubernetes.io/out-of-service"
+	drainRequestedAnnotation = "node.dana.io/drain-requested"
+)
+
+// isDrainOperation checks if the update of the node is the cordon step of a drain, which is
+// the case when the node is cordoned while being out of service or having a drain requested.
+func isDrainOperation(oldNode, newNode *corev1.Node) bool {
+	if oldNode.Spec.Unschedulable || !newNode.Spec.Unschedulable {
+		return false
+	}
+	for _, annotation := range []string{outOfServiceAnnotation, drainRequestedAnnotation} {
+		if _, ok := newNode.Annotations[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// drainPolicy returns the policy used to validate a drain, in which the drain-specific
+// reason rules, if any, replace the reason rules of the policy.
+func drainPolicy(policy Policy) Policy {
+	if len(policy.DrainAllowedReasons) == 0 && policy.DrainReasonRegexPattern == "" {
+		return policy
+	}
+	policy.AllowedReasons = policy.DrainAllowedReasons
+	policy.ReasonRegexPattern = policy.DrainReasonRegexPattern
+	return policy
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestIsDrainOperation(t *testing.T) {
+	tests := []struct {
+		name             string
+		oldUnschedulable bool
+		annotations      map[string]string
+		isDrain          bool
+	}{
+		{name: "PlainCordon", annotations: nil, isDrain: false},
+		{name: "DrainRequested", annotations: map[string]string{drainRequestedAnnotation: "true"}, isDrain: true},
+		{name: "OutOfService", annotations: map[string]string{outOfServiceAnnotation: "nodeshutdown"}, isDrain: true},
+		{name: "AlreadyCordoned", oldUnschedulable: true, annotations: map[string]string{drainRequestedAnnotation: "true"}, isDrain: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldNode := corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}, Spec: corev1.NodeSpec{Unschedulable: test.oldUnschedulable}}
+			newNode := *oldNode.DeepCopy()
+			newNode.Spec.Unschedulable = true
+			g.Expect(isDrainOperation(&oldNode, &newNode)).Should(Equal(test.isDrain))
+		})
+	}
+}
+
+func TestDrainPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		allowed     bool
+	}{
+		{name: "CordonWithCordonReason", annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: true},
+		{name: "CordonWithDrainReason", annotations: map[string]string{reasonAnnotation: "Decommission"}, allowed: false},
+		{name: "DrainWithDrainReason", annotations: map[string]string{reasonAnnotation: "Decommission", drainRequestedAnnotation: "true"}, allowed: true},
+		{name: "DrainWithCordonReason", annotations: map[string]string{reasonAnnotation: "Testing", drainRequestedAnnotation: "true"}, allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", drainReasonsKey: "Decommission"},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	drainSimulationFailedEvent = "DrainSimulationFailed"
+	mirrorPodAnnotation        = "kubernetes.io/config.mirror"
+)
+
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+
+// nodeSelectorOperators maps the operators of the node selector requirements to the ones of the label selectors.
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// unplaceablePod is a pod of a drained node which the drain simulation couldn't place on the remaining nodes.
+type unplaceablePod struct {
+	name   string
+	reason string
+}
+
+// simulatedNode is a node on which the drain simulation places the evicted pods, with its remaining capacity.
+type simulatedNode struct {
+	node   *corev1.Node
+	cpu    resource.Quantity
+	memory resource.Quantity
+	pods   int64
+}
+
+// validateDrainSimulation simulates the drain implied by the deletion of a node, warning about the pods which
+// couldn't be placed on the remaining nodes in the response and in a Warning event on the node. The deletion is denied
+// instead if the policy requires it. A simulation which fails to run only adds a warning, since it is best-effort.
+func (n *NodeValidator) validateDrainSimulation(ctx context.Context, node *corev1.Node, user string, policy Policy, log logr.Logger, dryRun bool, response admission.Response) admission.Response {
+	if !response.Allowed {
+		return response
+	}
+
+	unplaceable, err := n.simulateDrain(ctx, node)
+	if err != nil {
+		log.Error(err, "Failed to simulate the drain of the node")
+		response.Warnings = append(response.Warnings, fmt.Sprintf("The drain of node %q could not be simulated: %s", node.Name, err))
+		return response
+	}
+	if len(unplaceable) == 0 {
+		return response
+	}
+
+	descriptions := make([]string, 0, len(unplaceable))
+	for _, pod := range unplaceable {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", pod.name, pod.reason))
+	}
+	message := fmt.Sprintf("%d pods of node %q could not be placed on the remaining nodes: %s", len(unplaceable), node.Name, strings.Join(descriptions, ", "))
+	if !dryRun && n.Recorder != nil {
+		n.Recorder.Event(node, corev1.EventTypeWarning, drainSimulationFailedEvent, message)
+	}
+	if !policy.DenyIfDrainSimulationFails {
+		log.Info("Drain simulation found unplaceable pods", "Node", node.Name, "User", user, "UnplaceablePods", len(unplaceable))
+		response.Warnings = append(response.Warnings, message)
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: Delete, Decision: decisionDenied, DenialCode: DrainSimulationFailedCode,
+		Grounds: "drain simulation found unplaceable pods", Details: []any{"UnplaceablePods", len(unplaceable)}})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      DrainSimulationFailedCode,
+		Operation: Delete,
+		User:      user,
+		Message:   message,
+	})
+}
+
+// simulateDrain estimates whether the pods evicted from the node could be placed on the remaining schedulable nodes,
+// and returns the ones which couldn't. The pods are placed first-fit, the largest first, on the nodes they select
+// and whose taints they tolerate, within the allocatable CPU, memory and pods of the nodes left by the pods already
+// running on them. The evictions exceeding the disruptions allowed by a PodDisruptionBudget are blocked.
+// DaemonSet and mirror pods aren't evicted, so they are ignored.
+func (n *NodeValidator) simulateDrain(ctx context.Context, node *corev1.Node) ([]unplaceablePod, error) {
+	pods := corev1.PodList{}
+	if err := n.Client.List(ctx, &pods); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	nodes := corev1.NodeList{}
+	if err := n.Client.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	budgets := policyv1.PodDisruptionBudgetList{}
+	if err := n.Client.List(ctx, &budgets); err != nil {
+		return nil, fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	candidates := map[string]*simulatedNode{}
+	for i := range nodes.Items {
+		candidate := &nodes.Items[i]
+		if candidate.Name == node.Name || candidate.Spec.Unschedulable {
+			continue
+		}
+		candidates[candidate.Name] = &simulatedNode{
+			node:   candidate,
+			cpu:    candidate.Status.Allocatable.Cpu().DeepCopy(),
+			memory: candidate.Status.Allocatable.Memory().DeepCopy(),
+			pods:   candidate.Status.Allocatable.Pods().Value(),
+		}
+	}
+
+	var evicted []*corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if candidate, ok := candidates[pod.Spec.NodeName]; ok {
+			candidate.reserve(pod)
+		}
+		if pod.Spec.NodeName == node.Name && isEvictable(pod) {
+			evicted = append(evicted, pod)
+		}
+	}
+	// The largest pods are placed first, so that the smaller ones fill the remaining capacity.
+	slices.SortStableFunc(evicted, func(a, b *corev1.Pod) int {
+		aCPU, aMemory := podRequests(a)
+		bCPU, bMemory := podRequests(b)
+		if c := bCPU.Cmp(aCPU); c != 0 {
+			return c
+		}
+		if c := bMemory.Cmp(aMemory); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	allowedDisruptions := map[string]int32{}
+	for _, budget := range budgets.Items {
+		allowedDisruptions[budget.Namespace+"/"+budget.Name] = budget.Status.DisruptionsAllowed
+	}
+
+	var unplaceable []unplaceablePod
+	for _, pod := range evicted {
+		podName := pod.Namespace + "/" + pod.Name
+		if budget, blocked := blockingBudget(pod, budgets.Items, allowedDisruptions); blocked {
+			unplaceable = append(unplaceable, unplaceablePod{name: podName, reason: fmt.Sprintf("blocked by PodDisruptionBudget %q", budget)})
+			continue
+		}
+
+		placed, selected := false, false
+		for _, name := range names {
+			candidate := candidates[name]
+			if !isSchedulableOn(pod, candidate.node) {
+				continue
+			}
+			selected = true
+			if candidate.fits(pod) {
+				candidate.reserve(pod)
+				placed = true
+				break
+			}
+		}
+		switch {
+		case placed:
+		case selected:
+			unplaceable = append(unplaceable, unplaceablePod{name: podName, reason: "insufficient resources"})
+		default:
+			unplaceable = append(unplaceable, unplaceablePod{name: podName, reason: "no node matches its node affinity, selector or tolerations"})
+		}
+	}
+	return unplaceable, nil
+}
+
+// isEvictable checks whether a drain evicts the pod, which isn't the case of the DaemonSet and mirror pods.
+func isEvictable(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+	owner := metav1.GetControllerOf(pod)
+	return owner == nil || owner.Kind != "DaemonSet"
+}
+
+// blockingBudget returns the name of a PodDisruptionBudget of the pod which allows no more disruptions. Otherwise,
+// the eviction of the pod is counted against the disruptions allowed by all the budgets of the pod.
+func blockingBudget(pod *corev1.Pod, budgets []policyv1.PodDisruptionBudget, allowedDisruptions map[string]int32) (string, bool) {
+	var matching []string
+	for _, budget := range budgets {
+		if budget.Namespace != pod.Namespace || budget.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		key := budget.Namespace + "/" + budget.Name
+		if allowedDisruptions[key] <= 0 {
+			return key, true
+		}
+		matching = append(matching, key)
+	}
+	for _, key := range matching {
+		allowedDisruptions[key]--
+	}
+	return "", false
+}
+
+// isSchedulableOn checks whether the node selector and the required node affinity of the pod select the node,
+// and whether the pod tolerates the NoSchedule and NoExecute taints of the node.
+func isSchedulableOn(pod *corev1.Pod, node *corev1.Node) bool {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && !matchesNodeSelectorTerms(required.NodeSelectorTerms, node) {
+			return false
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !slices.ContainsFunc(pod.Spec.Tolerations, func(toleration corev1.Toleration) bool { return toleration.ToleratesTaint(taint) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNodeSelectorTerms checks whether any of the terms selects the node. A term selects the node
+// if all its label expressions match the labels of the node, and all its field expressions match its name.
+func matchesNodeSelectorTerms(terms []corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		if matchesNodeSelectorRequirements(term.MatchExpressions, labels.Set(node.Labels)) &&
+			matchesNodeSelectorRequirements(term.MatchFields, labels.Set{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorRequirements checks whether all the requirements match the set. An invalid requirement never matches.
+func matchesNodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement, set labels.Set) bool {
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false
+		}
+		labelRequirement, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !labelRequirement.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
+
+// podRequests returns the CPU and memory requested by the containers of the pod.
+func podRequests(pod *corev1.Pod) (resource.Quantity, resource.Quantity) {
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	for _, container := range pod.Spec.Containers {
+		cpu.Add(*container.Resources.Requests.Cpu())
+		memory.Add(*container.Resources.Requests.Memory())
+	}
+	return cpu, memory
+}
+
+// fits checks whether the remaining capacity of the node fits the requests of the pod.
+func (s *simulatedNode) fits(pod *corev1.Pod) bool {
+	cpu, memory := podRequests(pod)
+	return s.pods > 0 && cpu.Cmp(s.cpu) <= 0 && memory.Cmp(s.memory) <= 0
+}
+
+// reserve subtracts the requests of the pod from the remaining capacity of the node.
+func (s *simulatedNode) reserve(pod *corev1.Pod) {
+	cpu, memory := podRequests(pod)
+	s.cpu.Sub(cpu)
+	s.memory.Sub(memory)
+	s.pods--
+}
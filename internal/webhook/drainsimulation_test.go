@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// newSimulatedNode returns a node with the given allocatable CPU and memory.
+func newSimulatedNode(name string, cpu string, memory string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+}
+
+// newSimulatedPod returns a running pod on the node requesting the given CPU and memory.
+func newSimulatedPod(name string, nodeName string, cpu string, memory string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+			Name: "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestSimulateDrain(t *testing.T) {
+	daemonSetPod := newSimulatedPod("agent", "worker-1", "8", "1Gi", nil)
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "agent", UID: "uid", Controller: ptr(true)}}
+	gpuPod := newSimulatedPod("gpu", "worker-1", "1", "1Gi", nil)
+	gpuPod.Spec.NodeSelector = map[string]string{"accelerator": "gpu"}
+	zonePod := newSimulatedPod("zonal", "worker-1", "1", "1Gi", nil)
+	zonePod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: defaultZoneLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"zone-b"}}},
+		}}},
+	}}
+	taintedNode := newSimulatedNode("worker-3", "4", "8Gi", map[string]string{"accelerator": "gpu"})
+	taintedNode.Spec.Taints = []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	cordonedNode := newSimulatedNode("worker-4", "16", "32Gi", nil)
+	cordonedNode.Spec.Unschedulable = true
+
+	tests := []struct {
+		name        string
+		objects     []client.Object
+		unplaceable map[string]string
+	}{
+		{
+			name:    "AllPlaced",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", nil), newSimulatedPod("web", "worker-1", "2", "2Gi", nil), newSimulatedPod("api", "worker-1", "2", "2Gi", nil)},
+		},
+		{
+			name: "InsufficientResources",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", nil), newSimulatedPod("running", "worker-2", "2", "2Gi", nil),
+				newSimulatedPod("web", "worker-1", "2", "2Gi", nil), newSimulatedPod("api", "worker-1", "1", "1Gi", nil)},
+			unplaceable: map[string]string{"default/api": "insufficient resources"},
+		},
+		{
+			name:    "CordonedNodesIgnored",
+			objects: []client.Object{cordonedNode, newSimulatedPod("web", "worker-1", "2", "2Gi", nil)},
+			unplaceable: map[string]string{
+				"default/web": "no node matches its node affinity, selector or tolerations",
+			},
+		},
+		{
+			name:    "DaemonSetPodsIgnored",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", nil), daemonSetPod},
+		},
+		{
+			name:    "NodeSelectorAndTaints",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", nil), taintedNode, gpuPod},
+			unplaceable: map[string]string{
+				"default/gpu": "no node matches its node affinity, selector or tolerations",
+			},
+		},
+		{
+			name: "NodeAffinity",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", map[string]string{defaultZoneLabel: "zone-a"}),
+				newSimulatedNode("worker-3", "4", "8Gi", map[string]string{defaultZoneLabel: "zone-b"}), zonePod},
+		},
+		{
+			name: "PodDisruptionBudget",
+			objects: []client.Object{newSimulatedNode("worker-2", "4", "8Gi", nil),
+				newSimulatedPod("web-1", "worker-1", "1", "1Gi", map[string]string{"app": "web"}),
+				newSimulatedPod("web-2", "worker-1", "1", "1Gi", map[string]string{"app": "web"}),
+				&policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+					Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+					Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+				}},
+			unplaceable: map[string]string{"default/web-2": `blocked by PodDisruptionBudget "default/web"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			node := newSimulatedNode("worker-1", "4", "8Gi", nil)
+			g.Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+			for _, object := range test.objects {
+				g.Expect(fakeClient.Create(ctx, object)).Should(Succeed())
+			}
+			nv := NodeValidator{Client: fakeClient}
+
+			unplaceable, err := nv.simulateDrain(ctx, node)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			reasons := map[string]string{}
+			for _, pod := range unplaceable {
+				reasons[pod.name] = pod.reason
+			}
+			if test.unplaceable == nil {
+				g.Expect(reasons).Should(BeEmpty())
+			} else {
+				g.Expect(reasons).Should(Equal(test.unplaceable))
+			}
+		})
+	}
+}
+
+func TestDrainSimulationOnDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		allowed bool
+		warned  bool
+		event   string
+	}{
+		{name: "Disabled", data: map[string]string{}, allowed: true, event: operationApprovedEvent},
+		{name: "WarnByDefault", data: map[string]string{simulateDrainKey: "true"}, allowed: true, warned: true, event: drainSimulationFailedEvent},
+		{name: "Deny", data: map[string]string{simulateDrainKey: "true", denyDrainSimulationKey: "true"}, allowed: false, event: drainSimulationFailedEvent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			test.data[allowedReasonsKey] = "Testing"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, newSimulatedNode("worker-2", "1", "1Gi", nil))).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, newSimulatedPod("web", "worker-1", "2", "2Gi", nil))).Should(Succeed())
+			recorder := NewChannelEventRecorder(10)
+			nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}).WithEventRecorder(recorder)
+
+			response := nv.Handle(ctx, newDeleteRequest(g, "worker-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			simulationWarned := false
+			for _, warning := range response.Warnings {
+				simulationWarned = simulationWarned || strings.Contains(warning, "default/web (insufficient resources)")
+			}
+			g.Expect(simulationWarned).Should(Equal(test.warned))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(DrainSimulationFailedCode))
+			}
+
+			// The simulation event is recorded before the decision event.
+			var event RecordedEvent
+			g.Expect(recorder.Events()).Should(Receive(&event))
+			g.Expect(event.Reason).Should(Equal(test.event))
+		})
+	}
+}
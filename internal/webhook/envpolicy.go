@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// envConfigSource is the value of the CONFIG_SOURCE environment variable reading the policy from the environment.
+const envConfigSource = "env"
+
+// policyKeys are the keys of the policy ConfigMaps, which are read from the environment variables named after them
+// when the policy is read from the environment.
+var policyKeys = []string{
+	allowedReasonsKey, reasonRegexPatternKey, forbiddenUsersKey, warnOnlyKey, denialGracePeriodKey, maxCordonedPerZoneKey,
+	zoneLabelKey, reasonMinLengthKey, reasonMaxLengthKey, reasonFormatKey, monitoredTaintsKey, maintenanceWindowsKey,
+	operationAllowlistKey, reasonAttestationKey, emergencyBypassKey, allowFreetextKey, ticketURLKey, ticketStatusesKey,
+	ticketTokenSecretKey, rateLimitMaxOpsKey, rateLimitWindowKey, drainReasonsKey, drainPatternKey, reasonCategoriesKey,
+	latencyBucketsKey, allowedPrioritiesKey, reasonHistoryLimitKey, responseVerbosityKey, riskWeightsKey, riskWindowKey,
+	maxRiskScoreKey, bypassNodesKey, rateLimitByUIDKey, warningOperationsKey, trustedSANamespacesKey, saPrefixKey,
+	reasonMinPodCountKey, podSecurityCompatKey, forbiddenReasonsKey, denialTemplatesKey, reasonOwnershipKey,
+	reasonSuggestionKey, cordonCooloffKey, nodeVelocityLimitKey, nodeVelocityWindowKey, simulateDrainKey,
+	denyDrainSimulationKey, externalAPIHeadersKey, bulkCordonThresholdKey, bulkCordonWindowKey, checkCapacityKey,
+	minCapacityPercentKey, customCACertKey, validateStatusKey, auditWebhookURLKey, auditBatchSizeKey,
+	auditFlushIntervalKey, auditSecretKey, reasonAutoDeleteKey,
+}
+
+// IsEnvConfigSource returns true if the CONFIG_SOURCE environment variable is "env", in which case the policy is read
+// from the environment variables by an EnvPolicyResolver rather than from the ConfigMaps and NodeOperationPolicy objects.
+func IsEnvConfigSource() bool {
+	return os.Getenv(ConfigSourceEnv) == envConfigSource
+}
+
+// NewEnvPolicyResolver returns a resolver of the policy read from the environment variables, which applies to every
+// node. Each key of the policy ConfigMaps is read from the environment variable named after it in upper snake case,
+// e.g. ALLOWED_REASONS for "allowedReasons" and RATE_LIMIT_MAX_OPS for "rateLimit.maxOps". The default policy is used
+// if none of them is set. Since the environment doesn't change, the policy is read once, without any API call.
+func NewEnvPolicyResolver() (PolicyResolver, error) {
+	policy, err := policyFromEnv(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	return staticPolicyResolver{policy: policy}, nil
+}
+
+// policyFromEnv parses a policy out of the environment variables looked up by lookupEnv.
+func policyFromEnv(lookupEnv func(string) (string, bool)) (Policy, error) {
+	keys := slices.Clone(policyKeys)
+	for _, operation := range knownOperations {
+		keys = append(keys, string(operation)+defaultReasonSuffix)
+	}
+
+	configMap := &corev1.ConfigMap{Data: map[string]string{}}
+	for _, key := range keys {
+		if value, ok := lookupEnv(envVarName(key)); ok {
+			configMap.Data[key] = value
+		}
+	}
+	if len(configMap.Data) == 0 {
+		return defaultPolicy(), nil
+	}
+	policy, err := policyFromConfigMap(configMap)
+	if err != nil {
+		return Policy{}, fmt.Errorf("invalid policy environment variables: %w", err)
+	}
+	return policy, nil
+}
+
+// envVarName returns the name of the environment variable of a policy ConfigMap key, which is the key in upper snake
+// case: a dot, or the start of a camel case word or acronym, becomes an underscore, e.g. TICKET_API_TOKEN_SECRET_REF
+// for "ticketAPITokenSecretRef".
+func envVarName(key string) string {
+	isUpper := func(i int) bool { return i < len(key) && unicode.IsUpper(rune(key[i])) }
+	var name strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch {
+		case key[i] == '.':
+			name.WriteByte('_')
+			continue
+		case i == 0 || key[i-1] == '.' || !isUpper(i):
+		case !isUpper(i-1) || (i+1 < len(key) && !isUpper(i+1) && key[i+1] != '.'):
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(rune(key[i])))
+	}
+	return name.String()
+}
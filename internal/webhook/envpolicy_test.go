@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnvVarName(t *testing.T) {
+	tests := []struct {
+		key  string
+		name string
+	}{
+		{key: allowedReasonsKey, name: "ALLOWED_REASONS"},
+		{key: reasonRegexPatternKey, name: "REASON_REGEX_PATTERN"},
+		{key: forbiddenUsersKey, name: "FORBIDDEN_USERS"},
+		{key: rateLimitMaxOpsKey, name: "RATE_LIMIT_MAX_OPS"},
+		{key: drainReasonsKey, name: "DRAIN_ALLOWED_REASONS"},
+		{key: ticketTokenSecretKey, name: "TICKET_API_TOKEN_SECRET_REF"},
+		{key: rateLimitByUIDKey, name: "RATE_LIMIT_BY_UID"},
+		{key: auditWebhookURLKey, name: "AUDIT_WEBHOOK_URL"},
+		{key: string(Cordon) + defaultReasonSuffix, name: "CORDON_DEFAULT_REASON"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(envVarName(test.key)).Should(Equal(test.name))
+		})
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		policy Policy
+		valid  bool
+	}{
+		{name: "Empty", env: map[string]string{}, policy: defaultPolicy(), valid: true},
+		{name: "Reasons", env: map[string]string{"ALLOWED_REASONS": "Testing,Upgrade", "REASON_REGEX_PATTERN": "^OPS-[0-9]+$",
+			"FORBIDDEN_USERS": "intern", "RATE_LIMIT_MAX_OPS": "5", "RATE_LIMIT_WINDOW_SECONDS": "60", "CORDON_DEFAULT_REASON": "Testing"},
+			policy: Policy{AllowedReasons: []string{"Testing", "Upgrade"}, ReasonRegexPattern: "^OPS-[0-9]+$", ForbiddenUsers: []string{"intern"},
+				RateLimitMaxOps: 5, RateLimitWindow: time.Minute, DefaultReasons: map[Operation]string{Cordon: "Testing"}}, valid: true},
+		{name: "MissingReasons", env: map[string]string{"WARN_ONLY": "true"}, valid: false},
+		{name: "InvalidValue", env: map[string]string{"ALLOWED_REASONS": "Testing", "RATE_LIMIT_MAX_OPS": "many"}, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			policy, err := policyFromEnv(func(name string) (string, bool) {
+				value, ok := test.env[name]
+				return value, ok
+			})
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(policy.AllowedReasons).Should(Equal(test.policy.AllowedReasons))
+			g.Expect(policy.ReasonRegexPattern).Should(Equal(test.policy.ReasonRegexPattern))
+			g.Expect(policy.ForbiddenUsers).Should(Equal(test.policy.ForbiddenUsers))
+			g.Expect(policy.AllowFreetextReason).Should(Equal(test.policy.AllowFreetextReason))
+			g.Expect(policy.RateLimitMaxOps).Should(Equal(test.policy.RateLimitMaxOps))
+			g.Expect(policy.RateLimitWindow).Should(Equal(test.policy.RateLimitWindow))
+			g.Expect(policy.DefaultReasons).Should(Equal(test.policy.DefaultReasons))
+		})
+	}
+}
+
+func TestEnvPolicyResolver(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv(ConfigSourceEnv, "env")
+	t.Setenv("ALLOWED_REASONS", "Testing")
+	g.Expect(IsEnvConfigSource()).Should(BeTrue())
+
+	resolver, err := NewEnvPolicyResolver()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	// The policy applies to every node without a client.
+	nv := NodeValidator{PolicyResolver: resolver}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "gpu"}}}
+	policy, err := nv.resolvePolicy(context.Background(), node, nv.logger(context.Background()), false)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(policy.AllowedReasons).Should(Equal([]string{"Testing"}))
+
+	t.Setenv("REASON_MIN_LENGTH", "-1")
+	_, err = NewEnvPolicyResolver()
+	g.Expect(err).Should(HaveOccurred())
+}
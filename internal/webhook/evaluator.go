@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// EvaluationRequest is an operation of a user on a node to evaluate. It is serialized to JSON as the input
+// of the external policy engines.
+type EvaluationRequest struct {
+	Node      string    `json:"node"`
+	User      string    `json:"user"`
+	Groups    []string  `json:"groups,omitempty"`
+	Operation Operation `json:"operation"`
+	Reason    string    `json:"reason,omitempty"`
+	// ReasonRequired is true if the operation requires a reason, and ReasonExists if the node has one.
+	ReasonRequired bool `json:"reasonRequired"`
+	ReasonExists   bool `json:"reasonExists"`
+	// Policy is the policy applying to the node, for the built-in evaluation.
+	Policy Policy `json:"-"`
+	// Headers are the external API headers of the policy, to add to the requests of the evaluator to external APIs.
+	Headers http.Header `json:"-"`
+	// HTTPClient is the client of the validator for the requests to external APIs, trusting the custom CA certificates
+	// of the validator and of the policy. The evaluators with their own client don't use it.
+	HTTPClient *http.Client `json:"-"`
+}
+
+// EvaluationResult is the decision on an operation.
+type EvaluationResult struct {
+	Allowed bool
+	Message string
+
+	// response is the complete admission response of the built-in evaluation, with its denial details.
+	response *admission.Response
+}
+
+// PolicyEvaluator decides whether an operation of a user on a node is allowed, once it passed the other checks of
+// the validator, such as the rate limit, the forbidden users and the operation allowlist. It replaces the built-in
+// reason validation, e.g. to delegate the decision to an external policy engine such as OPA.
+type PolicyEvaluator interface {
+	Evaluate(ctx context.Context, req EvaluationRequest) (EvaluationResult, error)
+}
+
+// WithPolicyEvaluator sets the evaluator of the operations of the validator.
+// It must be called before the validator handles requests.
+func (n *NodeValidator) WithPolicyEvaluator(e PolicyEvaluator) *NodeValidator {
+	n.PolicyEvaluator = e
+	return n
+}
+
+// evaluateOperation returns the decision of the policy evaluator of the validator on an operation.
+// The forbidden users and the operations outside of the allowlist are denied before the other evaluators than
+// the built-in one are called, and their denials honor the warn-only mode of the policy. In dry run mode, these
+// evaluators, which may call external APIs, aren't called: the operation is allowed with a warning.
+func (n *NodeValidator) evaluateOperation(ctx context.Context, req EvaluationRequest, log logr.Logger, dryRun bool) admission.Response {
+	evaluator := n.PolicyEvaluator
+	if evaluator == nil {
+		evaluator = DefaultPolicyEvaluator{}
+	}
+
+	if _, ok := evaluator.(DefaultPolicyEvaluator); !ok {
+		if response, isDenied := checkUserAccess(req.Operation, req.Node, req.User, req.Groups, req.Policy, req.Reason, log); isDenied {
+			return warnOnlyDenial(req.Operation, req.Node, req.User, req.Policy, req.Reason, log, response)
+		}
+		if dryRun {
+			response := admission.Allowed(fmt.Sprintf("%s operation would be evaluated by the policy evaluator", req.Operation))
+			response.Warnings = append(response.Warnings, "The policy evaluator isn't called in dry run mode")
+			return response
+		}
+
+		headers, err := n.externalAPIHeaders(ctx, req.Policy)
+		if err != nil {
+			log.Error(err, "Failed to resolve the external API headers")
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to evaluate %s operation: %w", req.Operation, err))
+		}
+		req.Headers = headers
+		if req.HTTPClient, err = n.externalHTTPClient(ctx, req.Policy); err != nil {
+			log.Error(err, "Failed to set up the HTTP client trusting the custom CA certificates")
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to evaluate %s operation: %w", req.Operation, err))
+		}
+	}
+	result, err := evaluator.Evaluate(logr.NewContext(ctx, log), req)
+	if err != nil {
+		log.Error(err, "Failed to evaluate the operation", "Operation", req.Operation, "User", req.User)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to evaluate %s operation: %w", req.Operation, err))
+	}
+	if result.response != nil {
+		return *result.response
+	}
+	if result.Allowed {
+		logDecision(log, decisionLog{Node: req.Node, User: req.User, Operation: req.Operation, Decision: decisionAllowed, Reason: req.Reason, Grounds: "allowed by the policy evaluator"})
+		return admission.Allowed(result.Message)
+	}
+	logDecision(log, decisionLog{Node: req.Node, User: req.User, Operation: req.Operation, Decision: decisionDenied, DenialCode: ExternalPolicyDeniedCode,
+		Reason: req.Reason, Grounds: "denied by the policy evaluator"})
+	return denied(req.Policy, DenialDetail{
+		Code:      ExternalPolicyDeniedCode,
+		Operation: req.Operation,
+		User:      req.User,
+		Reason:    req.Reason,
+		Message:   result.Message,
+	})
+}
+
+// DefaultPolicyEvaluator evaluates the operations with the built-in reason validation of the policy.
+// It expects the logger of the request in the context.
+type DefaultPolicyEvaluator struct{}
+
+// Evaluate validates the user and the reason of the operation against the policy.
+func (DefaultPolicyEvaluator) Evaluate(ctx context.Context, req EvaluationRequest) (EvaluationResult, error) {
+	response := userOnlyOperation(req.Operation, req.Node, req.User, req.Groups, req.Policy, req.Reason, logr.FromContextOrDiscard(ctx),
+		req.ReasonRequired, req.ReasonExists)
+	return EvaluationResult{Allowed: response.Allowed, Message: decisionMessage(response), response: &response}, nil
+}
+
+// OPARESTEvaluator delegates the decisions to an OPA server, typically a sidecar, through its REST API.
+// The evaluation request is posted as the input of the decision, whose result must hold an "allow" boolean and
+// optionally a "message" string, e.g. {"result": {"allow": false, "message": "cordons are frozen"}}.
+// An undefined decision denies the operation.
+type OPARESTEvaluator struct {
+	// URL is the URL of the decision in the data API, e.g. http://localhost:8181/v1/data/nodeoperation.
+	URL string
+	// HTTPClient sends the requests to OPA. Defaults to the client of the validator.
+	HTTPClient *http.Client
+}
+
+// opaInput is the body of a request to the data API of OPA.
+type opaInput struct {
+	Input EvaluationRequest `json:"input"`
+}
+
+// opaDecision is the body of a response of the data API of OPA.
+type opaDecision struct {
+	Result *struct {
+		Allow   bool   `json:"allow"`
+		Message string `json:"message"`
+	} `json:"result"`
+}
+
+// Evaluate posts the request to OPA and returns its decision.
+func (e *OPARESTEvaluator) Evaluate(ctx context.Context, req EvaluationRequest) (EvaluationResult, error) {
+	body, err := json.Marshal(opaInput{Input: req})
+	if err != nil {
+		return EvaluationResult{}, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return EvaluationResult{}, err
+	}
+	setHeaders(request, req.Headers)
+	request.Header.Set("Content-Type", "application/json")
+
+	httpClient := e.HTTPClient
+	if httpClient == nil {
+		httpClient = req.HTTPClient
+	}
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+	httpResponse, err := httpClient.Do(request)
+	if err != nil {
+		return EvaluationResult{}, err
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != http.StatusOK {
+		return EvaluationResult{}, fmt.Errorf("unexpected status %q from %s", httpResponse.Status, e.URL)
+	}
+
+	decision := opaDecision{}
+	if err := json.NewDecoder(httpResponse.Body).Decode(&decision); err != nil {
+		return EvaluationResult{}, fmt.Errorf("failed to decode the response of %s: %w", e.URL, err)
+	}
+	if decision.Result == nil {
+		return EvaluationResult{Allowed: false, Message: fmt.Sprintf("The policy decision of %s is undefined", e.URL)}, nil
+	}
+	message := decision.Result.Message
+	if message == "" && decision.Result.Allow {
+		message = fmt.Sprintf("%s operation has been approved by the policy engine", req.Operation)
+	}
+	if message == "" {
+		message = fmt.Sprintf("%s operation has been denied by the policy engine", req.Operation)
+	}
+	return EvaluationResult{Allowed: decision.Result.Allow, Message: message}, nil
+}
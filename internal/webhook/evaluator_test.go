@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// newOPAServer returns a server simulating the data API of OPA, which records the inputs and responds with the body.
+func newOPAServer(t *testing.T, status int, body string, inputs *[]EvaluationRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		input := opaInput{}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&input) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*inputs = append(*inputs, input.Input)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOPARESTEvaluator(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		allowed bool
+		message string
+		valid   bool
+	}{
+		{name: "Allowed", status: http.StatusOK, body: `{"result": {"allow": true}}`, allowed: true, message: "cordon operation has been approved by the policy engine", valid: true},
+		{name: "Denied", status: http.StatusOK, body: `{"result": {"allow": false, "message": "cordons are frozen"}}`, allowed: false, message: "cordons are frozen", valid: true},
+		{name: "Undefined", status: http.StatusOK, body: `{}`, allowed: false, valid: true},
+		{name: "ServerError", status: http.StatusInternalServerError, body: `{}`, valid: false},
+		{name: "InvalidBody", status: http.StatusOK, body: `not json`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			var inputs []EvaluationRequest
+			server := newOPAServer(t, test.status, test.body, &inputs)
+			evaluator := &OPARESTEvaluator{URL: server.URL, HTTPClient: server.Client()}
+
+			request := EvaluationRequest{Node: "node-1", User: regularUserExample, Operation: Cordon, Reason: "Testing", ReasonRequired: true, ReasonExists: true}
+			result, err := evaluator.Evaluate(context.Background(), request)
+			g.Expect(inputs).Should(Equal([]EvaluationRequest{request}))
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(result.Allowed).Should(Equal(test.allowed))
+			if test.message != "" {
+				g.Expect(result.Message).Should(Equal(test.message))
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluator(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		warnOnly string
+		user     string
+		dryRun   bool
+		allowed  bool
+		code     string
+	}{
+		{name: "AllowedWithoutReason", body: `{"result": {"allow": true}}`, warnOnly: "false", allowed: true},
+		{name: "Denied", body: `{"result": {"allow": false, "message": "cordons are frozen"}}`, warnOnly: "false", allowed: false, code: ExternalPolicyDeniedCode},
+		{name: "DeniedInWarnOnlyMode", body: `{"result": {"allow": false, "message": "cordons are frozen"}}`, warnOnly: "true", allowed: true},
+		// The forbidden users are denied whatever the decision of the evaluator.
+		{name: "ForbiddenUser", body: `{"result": {"allow": true}}`, warnOnly: "false", user: systemAdminUser, allowed: false, code: ForbiddenUserCode},
+		{name: "OperationNotAllowed", body: `{"result": {"allow": true}}`, warnOnly: "false", user: "viewer", allowed: false, code: OperationNotAllowedCode},
+		{name: "DryRun", body: `{"result": {"allow": false, "message": "cordons are frozen"}}`, warnOnly: "false", dryRun: true, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", warnOnlyKey: test.warnOnly, operationAllowlistKey: "viewer=uncordon"},
+			})).Should(Succeed())
+			var inputs []EvaluationRequest
+			server := newOPAServer(t, http.StatusOK, test.body, &inputs)
+			nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}).
+				WithPolicyEvaluator(&OPARESTEvaluator{URL: server.URL, HTTPClient: server.Client()})
+
+			user := regularUserExample
+			if test.user != "" {
+				user = test.user
+			}
+			request := newCordonRequest(g, test.name, user, nil)
+			request.DryRun = &test.dryRun
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.code != "" {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(test.code))
+			}
+			if test.user != "" || test.dryRun {
+				g.Expect(inputs).Should(BeEmpty())
+				return
+			}
+			g.Expect(inputs).Should(HaveLen(1))
+			g.Expect(inputs[0].ReasonExists).Should(BeFalse())
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Message).Should(Equal("cordons are frozen"))
+			}
+		})
+	}
+}
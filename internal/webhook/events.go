@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Event reasons of the decisions recorded on the nodes.
+const (
+	operationApprovedEvent = "NodeOperationApproved"
+	operationDeniedEvent   = "NodeOperationDenied"
+	emergencyBypassEvent   = "NodeOperationEmergencyBypass"
+	nodeBypassEvent        = "NodeOperationNodeBypass"
+	dryRunEventPrefix      = "DryRun:"
+)
+
+// WithEventRecorder sets the recorder of the decision events of the validator.
+// It must be called before the validator handles requests.
+func (n *NodeValidator) WithEventRecorder(r record.EventRecorder) *NodeValidator {
+	n.Recorder = r
+	return n
+}
+
+// WithEventEmissionDisabled replaces the recorder of the validator with one logging the events at debug level
+// instead of creating them, for clusters in which the events of the many operations load the API server.
+// It must be called before the validator handles requests, and after WithEventRecorder.
+func (n *NodeValidator) WithEventEmissionDisabled() *NodeValidator {
+	n.Recorder = &loggingEventRecorder{logger: log.Log.WithName("events")}
+	return n
+}
+
+// loggingEventRecorder is a record.EventRecorder logging the events at debug level instead of creating them.
+type loggingEventRecorder struct {
+	logger logr.Logger
+}
+
+func (r *loggingEventRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	keysAndValues := []any{"Type", eventType, "Reason", reason, "Message", message}
+	if obj, ok := object.(client.Object); ok {
+		keysAndValues = append(keysAndValues, "Object", obj.GetName())
+		if obj.GetNamespace() != "" {
+			keysAndValues = append(keysAndValues, "Namespace", obj.GetNamespace())
+		}
+	}
+	r.logger.V(1).Info("Event", keysAndValues...)
+}
+
+func (r *loggingEventRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *loggingEventRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+// recordDecision records the decision on an operation, along with its reason and reason category, as an event on the node,
+// in the decision stats of the status page, and in the audit webhook of the policy if any.
+func (n *NodeValidator) recordDecision(node *corev1.Node, operation Operation, user string, reason string, policy Policy, response admission.Response) {
+	n.decisions.record(user, !response.Allowed, n.now())
+	if n.Recorder == nil && policy.AuditWebhookURL == "" {
+		return
+	}
+
+	eventType, eventReason, message := n.decisionEvent(node, operation, user, reason, policy, response)
+	if n.Recorder != nil {
+		n.Recorder.Event(node, eventType, eventReason, message)
+	}
+	if policy.AuditWebhookURL != "" {
+		n.forwardDecision(node, eventType, eventReason, message, policy)
+	}
+}
+
+// decisionEvent returns the type, reason and message of the event recording the decision on an operation.
+// The type of the event is given by eventTypeForOutcome. The reason of the denial events is prefixed
+// in dry run mode since the operation is allowed anyway.
+func (n *NodeValidator) decisionEvent(node *corev1.Node, operation Operation, user string, reason string, policy Policy, response admission.Response) (string, string, string) {
+	category := ""
+	if value, ok := node.Annotations[reasonCategoryAnnotation]; ok {
+		category = fmt.Sprintf(" of category %q", value)
+	}
+	eventType := eventTypeForOutcome(response.Allowed, operation, policy.WarningOperations)
+
+	switch {
+	case response.Allowed && reason != "":
+		return eventType, operationApprovedEvent, fmt.Sprintf("%s operation by %q has been approved with reason %q%s", operation, user, reason, category)
+	case response.Allowed:
+		return eventType, operationApprovedEvent, fmt.Sprintf("%s operation by %q has been approved", operation, user)
+	}
+	eventReason := operationDeniedEvent
+	if n.DryRun && isDenied(response) {
+		eventReason = dryRunEventPrefix + operationDeniedEvent
+	}
+	return eventType, eventReason, fmt.Sprintf("%s operation%s by %q has been denied: %s", operation, category, user, decisionMessage(response))
+}
+
+// eventTypeForOutcome returns the type of the event recording the decision on an operation: Normal for approvals
+// and Warning for denials, except that the approvals of the warning operations are Warning events too, so that they
+// show up in event-based alerting.
+func eventTypeForOutcome(allowed bool, operation Operation, warningOperations []Operation) string {
+	if !allowed || slices.Contains(warningOperations, operation) {
+		return corev1.EventTypeWarning
+	}
+	return corev1.EventTypeNormal
+}
+
+// decisionMessage returns the human-readable message of a response, which is its reason when set.
+func decisionMessage(response admission.Response) string {
+	if response.Result.Reason != "" {
+		return string(response.Result.Reason)
+	}
+	return response.Result.Message
+}
@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// RecordedEvent is an event recorded by a ChannelEventRecorder.
+type RecordedEvent struct {
+	Object    runtime.Object
+	EventType string
+	Reason    string
+	Message   string
+}
+
+// ChannelEventRecorder is a record.EventRecorder sending the events to a channel instead of the Kubernetes API,
+// for the tests to assert exactly which events were recorded.
+type ChannelEventRecorder struct {
+	events chan RecordedEvent
+}
+
+// NewChannelEventRecorder returns a ChannelEventRecorder buffering up to size events.
+// Recording an event blocks while the buffer is full.
+func NewChannelEventRecorder(size int) *ChannelEventRecorder {
+	return &ChannelEventRecorder{events: make(chan RecordedEvent, size)}
+}
+
+// Events returns the channel of the recorded events.
+func (r *ChannelEventRecorder) Events() <-chan RecordedEvent {
+	return r.events
+}
+
+func (r *ChannelEventRecorder) Event(object runtime.Object, eventType string, reason string, message string) {
+	r.events <- RecordedEvent{Object: object, EventType: eventType, Reason: reason, Message: message}
+}
+
+func (r *ChannelEventRecorder) Eventf(object runtime.Object, eventType string, reason string, messageFmt string, args ...interface{}) {
+	r.Event(object, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *ChannelEventRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventType string, reason string, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+func TestEventTypeForOutcome(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowed           bool
+		operation         Operation
+		warningOperations []Operation
+		eventType         string
+	}{
+		{name: "Approved", allowed: true, operation: Cordon, eventType: corev1.EventTypeNormal},
+		{name: "Denied", allowed: false, operation: Cordon, eventType: corev1.EventTypeWarning},
+		{name: "ApprovedWarningOperation", allowed: true, operation: Delete, warningOperations: []Operation{Delete}, eventType: corev1.EventTypeWarning},
+		{name: "ApprovedOtherOperation", allowed: true, operation: Cordon, warningOperations: []Operation{Delete}, eventType: corev1.EventTypeNormal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(eventTypeForOutcome(test.allowed, test.operation, test.warningOperations)).Should(Equal(test.eventType))
+		})
+	}
+}
+
+func TestDecisionEventType(t *testing.T) {
+	tests := []struct {
+		name              string
+		warningOperations string
+		delete            bool
+		reason            string
+		eventType         string
+	}{
+		{name: "ApprovedCordon", reason: "Testing", eventType: corev1.EventTypeNormal},
+		{name: "DeniedCordon", reason: "for fun", eventType: corev1.EventTypeWarning},
+		{name: "ApprovedWarningCordon", warningOperations: "cordon", reason: "Testing", eventType: corev1.EventTypeWarning},
+		{name: "ApprovedWarningDelete", warningOperations: "cordon, delete", delete: true, reason: "Testing", eventType: corev1.EventTypeWarning},
+		{name: "ApprovedDeleteNotWarning", warningOperations: "cordon", delete: true, reason: "Testing", eventType: corev1.EventTypeNormal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", warningOperationsKey: test.warningOperations},
+			})).Should(Succeed())
+			recorder := NewChannelEventRecorder(10)
+			nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}).WithEventRecorder(recorder)
+
+			annotations := map[string]string{reasonAnnotation: test.reason}
+			request := newCordonRequest(g, test.name, regularUserExample, annotations)
+			if test.delete {
+				request = newDeleteRequest(g, test.name, regularUserExample, annotations)
+			}
+			nv.Handle(ctx, request)
+
+			var event RecordedEvent
+			g.Expect(recorder.Events()).Should(Receive(&event))
+			g.Expect(event.EventType).Should(Equal(test.eventType))
+			g.Expect(event.Object).Should(BeAssignableToTypeOf(&corev1.Node{}))
+			g.Expect(recorder.Events()).ShouldNot(Receive())
+		})
+	}
+}
+
+func TestDecisionEventReasons(t *testing.T) {
+	tests := []struct {
+		name    string
+		dryRun  bool
+		reason  string
+		event   string
+		message string
+	}{
+		{name: "Approved", reason: "Testing", event: operationApprovedEvent, message: `cordon operation by "user" has been approved with reason "Testing"`},
+		{name: "Denied", reason: "for fun", event: operationDeniedEvent},
+		{name: "DeniedInDryRunMode", dryRun: true, reason: "for fun", event: dryRunEventPrefix + operationDeniedEvent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			recorder := NewChannelEventRecorder(10)
+			nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, DryRun: test.dryRun}).WithEventRecorder(recorder)
+
+			nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: test.reason}))
+
+			var event RecordedEvent
+			g.Expect(recorder.Events()).Should(Receive(&event))
+			g.Expect(event.Reason).Should(Equal(test.event))
+			if test.message != "" {
+				g.Expect(event.Message).Should(Equal(test.message))
+			}
+			g.Expect(recorder.Events()).ShouldNot(Receive())
+		})
+	}
+}
+
+func TestWithEventEmissionDisabled(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	recorder := NewChannelEventRecorder(10)
+	nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}).WithEventRecorder(recorder).WithEventEmissionDisabled()
+	g.Expect(nv.Recorder).Should(BeAssignableToTypeOf(&loggingEventRecorder{}))
+	var info, debug []string
+	nv.Recorder = &loggingEventRecorder{logger: recordingLogger(&info, 0)}
+
+	nv.Handle(ctx, newCordonRequest(g, "worker-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+	g.Expect(recorder.Events()).ShouldNot(Receive())
+	g.Expect(info).Should(BeEmpty())
+
+	nv.Recorder = &loggingEventRecorder{logger: recordingLogger(&debug, 1)}
+	nv.Handle(ctx, newCordonRequest(g, "worker-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+	g.Expect(debug).Should(HaveLen(1))
+	g.Expect(debug[0]).Should(And(ContainSubstring(`"Reason"="`+operationApprovedEvent+`"`), ContainSubstring(`"Object"="worker-1"`),
+		ContainSubstring(`has been approved with reason \"Testing\"`)))
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// knownOperations are the operations which can be explained.
+var knownOperations = []Operation{Create, Delete, Cordon, Uncordon, TaintAdd, TaintRemove, Drain}
+
+// Explanation is the effective policy applying to an operation on a node.
+type Explanation struct {
+	Node               string    `json:"node"`
+	Operation          Operation `json:"operation,omitempty"`
+	AllowedReasons     []string  `json:"allowedReasons"`
+	ReasonRegexPattern string    `json:"reasonRegexPattern"`
+	BypassNode         bool      `json:"bypassNode"`
+	ForbiddenUsers     []string  `json:"forbiddenUsers"`
+}
+
+// ExplainHandler serves the effective policy of a node on GET /explain?node=<name>&operation=<operation>,
+// for the operators to understand the decisions of the webhook. The operation is optional, and only changes
+// the reason rules of a drain. It isn't protected, so it must only be served on a debug address.
+type ExplainHandler struct {
+	// Client fetches the nodes.
+	Client client.Client
+	// PolicyResolver resolves the policy applying to a node. Defaults to a CRDPolicyResolver
+	// falling back to a ConfigMapPolicyResolver.
+	PolicyResolver PolicyResolver
+}
+
+// ServeHTTP responds with the JSON Explanation of the node, with 400 if the query is invalid,
+// and with 404 if the node doesn't exist.
+func (h *ExplainHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(resp, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	nodeName := req.URL.Query().Get("node")
+	if nodeName == "" {
+		http.Error(resp, "the node query parameter is required", http.StatusBadRequest)
+		return
+	}
+	operation := Operation(req.URL.Query().Get("operation"))
+	if operation != "" && !slices.Contains(knownOperations, operation) {
+		http.Error(resp, fmt.Sprintf("unknown operation %q, expected one of %v", operation, knownOperations), http.StatusBadRequest)
+		return
+	}
+
+	logger := log.FromContext(req.Context()).WithName("Explain").WithValues("node", nodeName)
+	node := corev1.Node{}
+	if err := h.Client.Get(req.Context(), client.ObjectKey{Name: nodeName}, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(resp, fmt.Sprintf("node %q not found", nodeName), http.StatusNotFound)
+			return
+		}
+		logger.Error(err, "Failed to fetch the node")
+		http.Error(resp, fmt.Sprintf("failed to fetch node %q: %s", nodeName, err), http.StatusInternalServerError)
+		return
+	}
+	policy, err := policyResolver(h.PolicyResolver, nil, h.Client).Resolve(req.Context(), &node)
+	if err != nil {
+		logger.Error(err, "Failed to resolve policy")
+		http.Error(resp, fmt.Sprintf("failed to resolve the policy of node %q: %s", nodeName, err), http.StatusInternalServerError)
+		return
+	}
+	if operation == Drain {
+		policy = drainPolicy(policy)
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(resp).Encode(Explanation{
+		Node:               nodeName,
+		Operation:          operation,
+		AllowedReasons:     policy.AllowedReasons,
+		ReasonRegexPattern: policy.ReasonRegexPattern,
+		BypassNode:         isBypassNode(nodeName, policy.BypassNodes),
+		ForbiddenUsers:     effectiveForbiddenUsers(policy.ForbiddenUsers),
+	}); err != nil {
+		logger.Error(err, "Failed to encode the explanation")
+	}
+}
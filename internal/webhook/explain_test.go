@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExplainHandler(t *testing.T) {
+	t.Setenv(ForbiddenUsersEnv, envForbiddenUserExample)
+	resolver := &fakePolicyResolver{
+		master: Policy{
+			AllowedReasons:      []string{"Maintenance"},
+			ReasonRegexPattern:  "^OPS-[0-9]+",
+			ForbiddenUsers:      []string{cmForbiddenUserExample},
+			DrainAllowedReasons: []string{"Decommission"},
+		},
+		defaultPolicy: Policy{AllowedReasons: []string{"Testing"}, BypassNodes: []string{"spot-*"}},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		status      int
+		explanation Explanation
+		message     string
+	}{
+		{name: "MasterNode", query: "?node=master-1&operation=cordon", status: http.StatusOK, explanation: Explanation{
+			Node: "master-1", Operation: Cordon, AllowedReasons: []string{"Maintenance"}, ReasonRegexPattern: "^OPS-[0-9]+",
+			ForbiddenUsers: []string{envForbiddenUserExample, cmForbiddenUserExample, systemAdminUser},
+		}},
+		{name: "Drain", query: "?node=master-1&operation=drain", status: http.StatusOK, explanation: Explanation{
+			Node: "master-1", Operation: Drain, AllowedReasons: []string{"Decommission"},
+			ForbiddenUsers: []string{envForbiddenUserExample, cmForbiddenUserExample, systemAdminUser},
+		}},
+		{name: "BypassNode", query: "?node=spot-1", status: http.StatusOK, explanation: Explanation{
+			Node: "spot-1", AllowedReasons: []string{"Testing"}, BypassNode: true,
+			ForbiddenUsers: []string{envForbiddenUserExample, systemAdminUser},
+		}},
+		{name: "UnknownNode", query: "?node=missing&operation=cordon", status: http.StatusNotFound, message: `node "missing" not found`},
+		{name: "MissingNode", query: "?operation=cordon", status: http.StatusBadRequest, message: "the node query parameter is required"},
+		{name: "UnknownOperation", query: "?node=spot-1&operation=reboot", status: http.StatusBadRequest, message: `unknown operation "reboot"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master-1", Labels: map[string]string{masterRoleLabel: ""}}})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "spot-1"}})).Should(Succeed())
+			handler := &ExplainHandler{Client: fakeClient, PolicyResolver: resolver}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/explain"+test.query, nil))
+			g.Expect(recorder.Code).Should(Equal(test.status))
+			if test.status != http.StatusOK {
+				g.Expect(recorder.Body.String()).Should(ContainSubstring(test.message))
+				return
+			}
+
+			g.Expect(recorder.Header().Get("Content-Type")).Should(Equal("application/json"))
+			explanation := Explanation{}
+			g.Expect(json.Unmarshal(recorder.Body.Bytes(), &explanation)).Should(Succeed())
+			g.Expect(explanation).Should(Equal(test.explanation))
+		})
+	}
+}
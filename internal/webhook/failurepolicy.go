@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// defaultFailurePolicyDebounce is the default minimum period between two updates of the failure policy.
+	defaultFailurePolicyDebounce = 30 * time.Second
+
+	failurePolicyUpdatedEvent = "FailurePolicyUpdated"
+	invalidFailurePolicyEvent = "InvalidFailurePolicy"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;watch;update
+
+// FailurePolicyController sets the failure policy of the webhooks of the ValidatingWebhookConfiguration of the
+// validator to the failurePolicy key of the global ConfigMap, Ignore or Fail, so that operators can switch it during
+// an incident without redeploying. The failure policy of the deployment is kept while the key is absent, and an
+// invalid value is rejected with a Warning event on the ConfigMap. The updates are debounced to avoid thrashing.
+type FailurePolicyController struct {
+	Client client.Client
+	// Recorder records the updates and the invalid values as events on the ConfigMap. No events are recorded if it is nil.
+	Recorder record.EventRecorder
+	// WebhookConfigurationName is the name of the ValidatingWebhookConfiguration of the validator.
+	WebhookConfigurationName string
+	// DebouncePeriod is the minimum period between two updates of the failure policy. Defaults to 30 seconds.
+	DebouncePeriod time.Duration
+	// Clock provides the current time. Defaults to the system clock.
+	Clock Clock
+
+	mu         sync.Mutex
+	lastUpdate time.Time
+}
+
+// SetupWithManager registers the controller with the manager, watching the global ConfigMap.
+func (c *FailurePolicyController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("failure-policy").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetNamespace() == cmNamespace && object.GetName() == cmName
+		}))).
+		Complete(c)
+}
+
+// Reconcile updates the failure policy of the webhooks to the one of the ConfigMap. An update within the debounce
+// period of the previous one is requeued after the end of the period.
+func (c *FailurePolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("Failure Policy")
+
+	configMap := corev1.ConfigMap{}
+	if err := c.Client.Get(ctx, req.NamespacedName, &configMap); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	value, ok := configMap.Data[failurePolicyKey]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	failurePolicy, err := parseFailurePolicy(value)
+	if err != nil {
+		logger.Info("Rejecting the failure policy of the ConfigMap", "Error", err.Error())
+		c.recordEvent(&configMap, corev1.EventTypeWarning, invalidFailurePolicyEvent, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	webhookConfiguration := admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: c.WebhookConfigurationName}, &webhookConfiguration); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("The ValidatingWebhookConfiguration doesn't exist, the failure policy is not updated", "Name", c.WebhookConfigurationName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch ValidatingWebhookConfiguration %q: %w", c.WebhookConfigurationName, err)
+	}
+	changed := false
+	for i := range webhookConfiguration.Webhooks {
+		webhook := &webhookConfiguration.Webhooks[i]
+		if webhook.FailurePolicy == nil || *webhook.FailurePolicy != failurePolicy {
+			webhook.FailurePolicy = &failurePolicy
+			changed = true
+		}
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if wait := c.lastUpdate.Add(c.debouncePeriod()).Sub(c.now()); wait > 0 {
+		logger.Info("Debouncing the update of the failure policy", "FailurePolicy", failurePolicy, "RequeueAfter", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	if err := c.Client.Update(ctx, &webhookConfiguration); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update ValidatingWebhookConfiguration %q: %w", c.WebhookConfigurationName, err)
+	}
+	c.lastUpdate = c.now()
+	logger.Info("Updated the failure policy of the webhooks", "FailurePolicy", failurePolicy, "ValidatingWebhookConfiguration", c.WebhookConfigurationName)
+	c.recordEvent(&configMap, corev1.EventTypeNormal, failurePolicyUpdatedEvent,
+		fmt.Sprintf("The failure policy of ValidatingWebhookConfiguration %q was set to %s", c.WebhookConfigurationName, failurePolicy))
+	return ctrl.Result{}, nil
+}
+
+// parseFailurePolicy parses a failure policy, Ignore or Fail, case-insensitively.
+func parseFailurePolicy(value string) (admissionregistrationv1.FailurePolicyType, error) {
+	for _, failurePolicy := range []admissionregistrationv1.FailurePolicyType{admissionregistrationv1.Ignore, admissionregistrationv1.Fail} {
+		if strings.EqualFold(strings.TrimSpace(value), string(failurePolicy)) {
+			return failurePolicy, nil
+		}
+	}
+	return "", fmt.Errorf("invalid %q value %q, expected %q or %q", failurePolicyKey, value, admissionregistrationv1.Ignore, admissionregistrationv1.Fail)
+}
+
+// recordEvent records an event on the ConfigMap if the controller has a recorder.
+func (c *FailurePolicyController) recordEvent(configMap *corev1.ConfigMap, eventType string, reason string, message string) {
+	if c.Recorder != nil {
+		c.Recorder.Event(configMap, eventType, reason, message)
+	}
+}
+
+// debouncePeriod returns the debounce period of the controller, or the default one if it has none.
+func (c *FailurePolicyController) debouncePeriod() time.Duration {
+	if c.DebouncePeriod <= 0 {
+		return defaultFailurePolicyDebounce
+	}
+	return c.DebouncePeriod
+}
+
+// now returns the current time according to the clock of the controller.
+func (c *FailurePolicyController) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testWebhookConfigurationName = "node-operation-validator-validating-webhook-configuration"
+
+func TestFailurePolicyController(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          map[string]string
+		failurePolicy admissionregistrationv1.FailurePolicyType
+		eventReason   string
+	}{
+		{name: "IgnoreToFail", data: map[string]string{failurePolicyKey: "Fail"}, failurePolicy: admissionregistrationv1.Fail, eventReason: failurePolicyUpdatedEvent},
+		{name: "CaseInsensitive", data: map[string]string{failurePolicyKey: "fail"}, failurePolicy: admissionregistrationv1.Fail, eventReason: failurePolicyUpdatedEvent},
+		{name: "Unchanged", data: map[string]string{failurePolicyKey: "Ignore"}, failurePolicy: admissionregistrationv1.Ignore},
+		{name: "Absent", data: map[string]string{}, failurePolicy: admissionregistrationv1.Ignore},
+		{name: "Invalid", data: map[string]string{failurePolicyKey: "Sometimes"}, failurePolicy: admissionregistrationv1.Ignore, eventReason: invalidFailurePolicyEvent},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, newWebhookConfiguration(admissionregistrationv1.Ignore))).Should(Succeed())
+			recorder := NewChannelEventRecorder(10)
+			controller := &FailurePolicyController{Client: fakeClient, Recorder: recorder, WebhookConfigurationName: testWebhookConfigurationName}
+
+			result, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: cmNamespace, Name: cmName}})
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(result).Should(Equal(ctrl.Result{}))
+			g.Expect(webhookFailurePolicies(g, fakeClient)).Should(HaveEach(test.failurePolicy))
+			if test.eventReason == "" {
+				g.Expect(recorder.Events()).ShouldNot(Receive())
+				return
+			}
+			var event RecordedEvent
+			g.Expect(recorder.Events()).Should(Receive(&event))
+			g.Expect(event.Reason).Should(Equal(test.eventReason))
+		})
+	}
+}
+
+func TestFailurePolicyControllerDebounce(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{failurePolicyKey: "Fail"},
+	}
+	g.Expect(fakeClient.Create(ctx, configMap)).Should(Succeed())
+	g.Expect(fakeClient.Create(ctx, newWebhookConfiguration(admissionregistrationv1.Ignore))).Should(Succeed())
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	controller := &FailurePolicyController{Client: fakeClient, WebhookConfigurationName: testWebhookConfigurationName,
+		DebouncePeriod: time.Minute, Clock: clock}
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(configMap)}
+
+	_, err := controller.Reconcile(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(webhookFailurePolicies(g, fakeClient)).Should(HaveEach(admissionregistrationv1.Fail))
+
+	// A change within the debounce period is requeued until its end.
+	configMap.Data[failurePolicyKey] = "Ignore"
+	g.Expect(fakeClient.Update(ctx, configMap)).Should(Succeed())
+	clock.now = clock.now.Add(20 * time.Second)
+	result, err := controller.Reconcile(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result.RequeueAfter).Should(Equal(40 * time.Second))
+	g.Expect(webhookFailurePolicies(g, fakeClient)).Should(HaveEach(admissionregistrationv1.Fail))
+
+	clock.now = clock.now.Add(40 * time.Second)
+	result, err = controller.Reconcile(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result).Should(Equal(ctrl.Result{}))
+	g.Expect(webhookFailurePolicies(g, fakeClient)).Should(HaveEach(admissionregistrationv1.Ignore))
+}
+
+// newWebhookConfiguration returns a ValidatingWebhookConfiguration of the validator with the failure policy.
+func newWebhookConfiguration(failurePolicy admissionregistrationv1.FailurePolicyType) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: testWebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "vnode.dana.io", FailurePolicy: ptr(failurePolicy), SideEffects: ptr(admissionregistrationv1.SideEffectClassNone)},
+		},
+	}
+}
+
+// webhookFailurePolicies returns the failure policies of the webhooks of the ValidatingWebhookConfiguration.
+func webhookFailurePolicies(g *WithT, c client.Client) []admissionregistrationv1.FailurePolicyType {
+	webhookConfiguration := admissionregistrationv1.ValidatingWebhookConfiguration{}
+	g.Expect(c.Get(context.Background(), client.ObjectKey{Name: testWebhookConfigurationName}, &webhookConfiguration)).Should(Succeed())
+	var failurePolicies []admissionregistrationv1.FailurePolicyType
+	for _, webhook := range webhookConfiguration.Webhooks {
+		failurePolicies = append(failurePolicies, *webhook.FailurePolicy)
+	}
+	return failurePolicies
+}
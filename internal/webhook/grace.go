@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// denialKey identifies a denied operation of a user on a node.
+type denialKey struct {
+	user      string
+	node      string
+	operation Operation
+}
+
+// denial is a denied operation along with the reason it was submitted with.
+type denial struct {
+	reason  string
+	expires time.Time
+}
+
+// denialTracker keeps the recent denials in memory so that operations re-submitted
+// within their grace period can be approved. Its zero value is ready to use.
+type denialTracker struct {
+	mu      sync.Mutex
+	denials map[denialKey]denial
+}
+
+// record stores a denial of the given operation, dropping the denials whose grace period has passed.
+func (d *denialTracker) record(key denialKey, reason string, gracePeriod time.Duration, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.denials == nil {
+		d.denials = make(map[denialKey]denial)
+	}
+	for k, v := range d.denials {
+		if now.After(v.expires) {
+			delete(d.denials, k)
+		}
+	}
+	d.denials[key] = denial{reason: reason, expires: now.Add(gracePeriod)}
+}
+
+// matches returns true if the operation was denied within the grace period with the same reason,
+// without consuming the denial.
+func (d *denialTracker) matches(key denialKey, reason string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous, ok := d.denials[key]
+	return ok && previous.reason == reason && !now.After(previous.expires)
+}
+
+// consume returns true if the operation was denied within the grace period with the same reason.
+// A denial can only be consumed once.
+func (d *denialTracker) consume(key denialKey, reason string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous, ok := d.denials[key]
+	if !ok {
+		return false
+	}
+	delete(d.denials, key)
+	return previous.reason == reason && !now.After(previous.expires)
+}
+
+// isMissingReasonDenial returns true if the response denies an operation because its reason is missing, which is
+// the only denial re-submitting the operation within its grace period approves.
+func isMissingReasonDenial(response admission.Response) bool {
+	return !response.Allowed && response.Result != nil && response.Result.Code == CodeMissingReason
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDenialGracePeriod(t *testing.T) {
+	tests := []struct {
+		name            string
+		gracePeriods    string
+		elapsed         time.Duration
+		reason          string
+		resubmitReason  string
+		resubmitAllowed bool
+	}{
+		{name: "ResubmitWithinGracePeriod", gracePeriods: "cordon=60", elapsed: 30 * time.Second, resubmitAllowed: true},
+		{name: "ResubmitAfterGracePeriod", gracePeriods: "cordon=60", elapsed: 90 * time.Second, resubmitAllowed: false},
+		{name: "ResubmitWithDifferentReason", gracePeriods: "cordon=60", elapsed: 30 * time.Second, resubmitReason: "for fun", resubmitAllowed: false},
+		// Only the denials of missing reasons can be re-submitted, a disallowed reason stays denied.
+		{name: "ResubmitDisallowedReason", gracePeriods: "cordon=60", elapsed: 30 * time.Second, reason: "for fun", resubmitReason: "for fun", resubmitAllowed: false},
+		{name: "NoGracePeriodForOperation", gracePeriods: "delete=60", elapsed: 30 * time.Second, resubmitAllowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", denialGracePeriodKey: test.gracePeriods},
+			})).Should(Succeed())
+
+			clock := &fakeClock{now: time.Now()}
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: clock}
+
+			var firstAnnotations map[string]string
+			if test.reason != "" {
+				firstAnnotations = map[string]string{reasonAnnotation: test.reason}
+			}
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, firstAnnotations))
+			g.Expect(response.Allowed).Should(BeFalse())
+
+			clock.now = clock.now.Add(test.elapsed)
+			annotations := map[string]string{}
+			if test.resubmitReason != "" {
+				annotations[reasonAnnotation] = test.resubmitReason
+			}
+			response = nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.resubmitAllowed))
+
+			// A denial can only be used once.
+			if test.resubmitAllowed {
+				response = nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, annotations))
+				g.Expect(response.Allowed).Should(BeFalse())
+			}
+		})
+	}
+}
+
+func TestParseDenialGracePeriods(t *testing.T) {
+	g := NewWithT(t)
+
+	gracePeriods, err := parseDenialGracePeriods("cordon=60, delete=30")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(gracePeriods).Should(Equal(map[Operation]time.Duration{Cordon: time.Minute, Delete: 30 * time.Second}))
+
+	_, err = parseDenialGracePeriods("cordon")
+	g.Expect(err).Should(HaveOccurred())
+
+	_, err = parseDenialGracePeriods("cordon=-1")
+	g.Expect(err).Should(HaveOccurred())
+}
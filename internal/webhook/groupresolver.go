@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// groupResolverCacheTTL is the default time the groups resolved by the UserGroupResolver are cached for.
+const groupResolverCacheTTL = 5 * time.Minute
+
+// UserGroupResolver looks up the groups of a user, e.g. in an LDAP directory or an OIDC provider, for the
+// authentication providers which don't populate the groups of the admission requests.
+type UserGroupResolver interface {
+	Resolve(ctx context.Context, username string) ([]string, error)
+}
+
+// NoopGroupResolver is a UserGroupResolver resolving no groups.
+type NoopGroupResolver struct{}
+
+// Resolve returns no groups.
+func (NoopGroupResolver) Resolve(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+// WithGroupResolver sets the resolver of the groups of the users whose requests have no groups.
+// It must be called before the validator handles requests.
+func (n *NodeValidator) WithGroupResolver(r UserGroupResolver) *NodeValidator {
+	n.GroupResolver = r
+	return n
+}
+
+// groupCache caches the groups resolved by the UserGroupResolver by username.
+type groupCache struct {
+	mu      sync.Mutex
+	entries map[string]groupCacheEntry
+}
+
+// groupCacheEntry holds the groups of a user and the time they expire at.
+type groupCacheEntry struct {
+	groups []string
+	expiry time.Time
+}
+
+// get returns the cached groups of the user, or false if they aren't cached or expired.
+func (c *groupCache) get(username string, now time.Time) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[username]
+	if !ok || !now.Before(entry.expiry) {
+		return nil, false
+	}
+	return entry.groups, true
+}
+
+// set caches the groups of the user until the expiry, dropping the expired entries.
+func (c *groupCache) set(username string, groups []string, expiry time.Time, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]groupCacheEntry)
+	}
+	for cached, entry := range c.entries {
+		if !now.Before(entry.expiry) {
+			delete(c.entries, cached)
+		}
+	}
+	c.entries[username] = groupCacheEntry{groups: groups, expiry: expiry}
+}
+
+// resolveGroups returns the groups of the request, or the groups resolved by the group resolver of the validator
+// if the request has none. If the groups can't be resolved, the user is validated without groups.
+func (n *NodeValidator) resolveGroups(ctx context.Context, user string, groups []string, log logr.Logger) []string {
+	if len(groups) > 0 || n.GroupResolver == nil {
+		return groups
+	}
+
+	now := n.now()
+	if cached, ok := n.groupCache.get(user, now); ok {
+		return cached
+	}
+	resolved, err := n.GroupResolver.Resolve(ctx, user)
+	if err != nil {
+		log.Error(err, "Failed to resolve the groups of the user, the user is validated without groups", "User", user)
+		return groups
+	}
+	ttl := n.GroupResolverCacheTTL
+	if ttl <= 0 {
+		ttl = groupResolverCacheTTL
+	}
+	n.groupCache.set(user, resolved, now.Add(ttl), now)
+	return resolved
+}
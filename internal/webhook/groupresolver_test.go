@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// fakeGroupResolver resolves the groups of the users from a map, counting its calls.
+type fakeGroupResolver struct {
+	groups map[string][]string
+	err    error
+	calls  int
+}
+
+func (f *fakeGroupResolver) Resolve(_ context.Context, username string) ([]string, error) {
+	f.calls++
+	return f.groups[username], f.err
+}
+
+func TestGroupResolver(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestGroups []string
+		resolverErr   error
+		allowed       bool
+		calls         int
+	}{
+		{name: "ResolvedGroups", allowed: false, calls: 1},
+		{name: "RequestGroups", requestGroups: []string{"sre"}, allowed: true, calls: 0},
+		{name: "ResolverError", resolverErr: errors.New("ldap unavailable"), allowed: true, calls: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resolver := &fakeGroupResolver{groups: map[string][]string{regularUserExample: {"developers"}}, err: test.resolverErr}
+			nv := (&NodeValidator{
+				Decoder:        admission.NewDecoder(scheme.Scheme),
+				PolicyResolver: &fakePolicyResolver{defaultPolicy: Policy{AllowedReasons: []string{"Testing"}, ForbiddenGroups: []string{"developers"}}},
+				Clock:          &fakeClock{},
+			}).WithGroupResolver(resolver)
+
+			request := newCordonRequest(g, "node-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"})
+			request.UserInfo.Groups = test.requestGroups
+			response := nv.Handle(context.Background(), request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(ForbiddenUserCode))
+			}
+			g.Expect(resolver.calls).Should(Equal(test.calls))
+		})
+	}
+}
+
+func TestGroupResolverCache(t *testing.T) {
+	g := NewWithT(t)
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	resolver := &fakeGroupResolver{groups: map[string][]string{regularUserExample: {"developers"}}}
+	nv := (&NodeValidator{Clock: clock}).WithGroupResolver(resolver)
+	ctx := context.Background()
+
+	g.Expect(nv.resolveGroups(ctx, regularUserExample, nil, nv.logger(ctx))).Should(Equal([]string{"developers"}))
+	resolver.groups[regularUserExample] = []string{"sre"}
+	clock.now = clock.now.Add(groupResolverCacheTTL - time.Second)
+	g.Expect(nv.resolveGroups(ctx, regularUserExample, nil, nv.logger(ctx))).Should(Equal([]string{"developers"}))
+	g.Expect(resolver.calls).Should(Equal(1))
+
+	clock.now = clock.now.Add(time.Second)
+	g.Expect(nv.resolveGroups(ctx, regularUserExample, nil, nv.logger(ctx))).Should(Equal([]string{"sre"}))
+	g.Expect(resolver.calls).Should(Equal(2))
+}
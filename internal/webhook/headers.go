@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envVarReference matches the ${VAR} references to environment variables in the values of the external API headers.
+var envVarReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExternalAPIHeader is the value of a header added to the requests to external APIs, such as OPA and Jira.
+// It is either given as Value, in which the ${VAR} references are substituted with the environment variables,
+// or read from the Key of the Secret referenced by SecretRef, as <namespace>/<name> or <name>, for sensitive headers.
+type ExternalAPIHeader struct {
+	Value     string
+	SecretRef string
+	Key       string
+}
+
+// UnmarshalJSON parses a header value either as a string or as an object with "secretRef" and "key" fields.
+func (h *ExternalAPIHeader) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &h.Value); err == nil {
+		return nil
+	}
+	secret := struct {
+		SecretRef string `json:"secretRef"`
+		Key       string `json:"key"`
+	}{}
+	if err := json.Unmarshal(data, &secret); err != nil {
+		return errors.New(`expected a string or an object with "secretRef" and "key" fields`)
+	}
+	if secret.SecretRef == "" || secret.Key == "" {
+		return errors.New(`both "secretRef" and "key" must be set`)
+	}
+	h.SecretRef, h.Key = secret.SecretRef, secret.Key
+	return nil
+}
+
+// parseExternalAPIHeaders parses a JSON map of header names to header values.
+func parseExternalAPIHeaders(value string) (map[string]ExternalAPIHeader, error) {
+	headers := map[string]ExternalAPIHeader{}
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		return nil, err
+	}
+	for name := range headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	return headers, nil
+}
+
+// externalAPIHeaders resolves the external API headers of the policy, substituting the environment variables
+// and reading the Secrets.
+func (n *NodeValidator) externalAPIHeaders(ctx context.Context, policy Policy) (http.Header, error) {
+	if len(policy.ExternalAPIHeaders) == 0 {
+		return nil, nil
+	}
+
+	headers := http.Header{}
+	for name, header := range policy.ExternalAPIHeaders {
+		if header.SecretRef == "" {
+			headers.Set(name, envVarReference.ReplaceAllStringFunc(header.Value, func(reference string) string {
+				return os.Getenv(envVarReference.FindStringSubmatch(reference)[1])
+			}))
+			continue
+		}
+		value, err := getSecretValue(ctx, n.Client, header.SecretRef, header.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve header %q: %w", name, err)
+		}
+		headers.Set(name, strings.TrimSpace(string(value)))
+	}
+	return headers, nil
+}
+
+// setHeaders sets the headers on the request.
+func setHeaders(request *http.Request, headers http.Header) {
+	for name, values := range headers {
+		request.Header[name] = values
+	}
+}
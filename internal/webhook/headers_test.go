@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseExternalAPIHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		headers map[string]ExternalAPIHeader
+		valid   bool
+	}{
+		{name: "Value", value: `{"X-Tenant": "platform"}`, headers: map[string]ExternalAPIHeader{"X-Tenant": {Value: "platform"}}, valid: true},
+		{name: "SecretRef", value: `{"X-Api-Key": {"secretRef": "default/api-key", "key": "key"}}`,
+			headers: map[string]ExternalAPIHeader{"X-Api-Key": {SecretRef: "default/api-key", Key: "key"}}, valid: true},
+		{name: "MissingKey", value: `{"X-Api-Key": {"secretRef": "api-key"}}`, valid: false},
+		{name: "InvalidValue", value: `{"X-Tenant": 1}`, valid: false},
+		{name: "InvalidName", value: `{"X Tenant": "platform"}`, valid: false},
+		{name: "InvalidJSON", value: `X-Tenant: platform`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			headers, err := parseExternalAPIHeaders(test.value)
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(headers).Should(Equal(test.headers))
+		})
+	}
+}
+
+func TestExternalAPIHeaders(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Setenv("CLUSTER_NAME", "prod-1")
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-key", Namespace: cmNamespace},
+		Data:       map[string][]byte{"key": []byte("secret-key\n")},
+	})).Should(Succeed())
+	nv := NodeValidator{Client: fakeClient}
+
+	policy := Policy{ExternalAPIHeaders: map[string]ExternalAPIHeader{
+		"X-Cluster": {Value: "cluster=${CLUSTER_NAME}"},
+		"X-Api-Key": {SecretRef: cmNamespace + "/api-key", Key: "key"},
+	}}
+	headers, err := nv.externalAPIHeaders(ctx, policy)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(headers.Get("X-Cluster")).Should(Equal("cluster=prod-1"))
+	g.Expect(headers.Get("X-Api-Key")).Should(Equal("secret-key"))
+
+	policy.ExternalAPIHeaders["X-Missing"] = ExternalAPIHeader{SecretRef: cmNamespace + "/missing", Key: "key"}
+	_, err = nv.externalAPIHeaders(ctx, policy)
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestExternalAPIHeadersSent(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		_, _ = w.Write([]byte(`{"key":"OPS-1","fields":{"status":{"name":"Open"}},"result":{"allow":true}}`))
+	}))
+	defer server.Close()
+	nv := NodeValidator{Client: newFakeClient(), HTTPClient: server.Client()}
+	policy := Policy{TicketValidationURL: server.URL, ExternalAPIHeaders: map[string]ExternalAPIHeader{
+		"X-Tenant": {Value: "platform"},
+		"Accept":   {Value: "text/plain"},
+	}}
+
+	_, _, err := nv.getTicketStatus(ctx, policy, "OPS-1")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	headers, err := nv.externalAPIHeaders(ctx, policy)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	evaluator := &OPARESTEvaluator{URL: server.URL, HTTPClient: server.Client()}
+	_, err = evaluator.Evaluate(ctx, EvaluationRequest{Node: "node-1", Operation: Cordon, Policy: policy, Headers: headers})
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	g.Expect(received).Should(HaveLen(2))
+	for _, header := range received {
+		g.Expect(header.Get("X-Tenant")).Should(Equal("platform"))
+	}
+	// The headers required by the APIs take precedence.
+	g.Expect(received[0].Get("Accept")).Should(Equal("application/json"))
+}
@@ -0,0 +1,228 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	reasonHistoryAnnotation   = "node.dana.io/reason-history"
+	dryRunPreviewAnnotation   = "node.dana.io/dry-run-preview"
+	defaultReasonHistoryLimit = 10
+)
+
+// ReasonHistoryEntry is an entry of the reason history annotation.
+type ReasonHistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user"`
+	Operation Operation `json:"operation"`
+	Reason    string    `json:"reason"`
+	// Count is the number of consecutive identical operations merged into the entry by the compaction, if more than one.
+	Count int `json:"count,omitempty"`
+	// LastSeen is the time of the last operation merged into the entry, while Timestamp is the time of the first one.
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// ReasonHistory is the sequence of entries of the reason history annotation, from the oldest to the newest.
+type ReasonHistory []ReasonHistoryEntry
+
+// Compact merges the consecutive entries of the same user, operation and reason into a single entry counting them,
+// so that repeated operations, such as the cordons of an automation retrying, don't push the older reasons out.
+func (h ReasonHistory) Compact() ReasonHistory {
+	compacted := make(ReasonHistory, 0, len(h))
+	for _, entry := range h {
+		if len(compacted) == 0 {
+			compacted = append(compacted, entry)
+			continue
+		}
+		last := &compacted[len(compacted)-1]
+		if last.User != entry.User || last.Operation != entry.Operation || last.Reason != entry.Reason {
+			compacted = append(compacted, entry)
+			continue
+		}
+		last.Count = last.count() + entry.count()
+		lastSeen := entry.Timestamp
+		if entry.LastSeen != nil {
+			lastSeen = *entry.LastSeen
+		}
+		last.LastSeen = &lastSeen
+	}
+	return compacted
+}
+
+// count returns the number of operations of the entry.
+func (e ReasonHistoryEntry) count() int {
+	return max(e.Count, 1)
+}
+
+// NodeMutator appends the reason of the operations requiring a reason to the reason history annotation of the nodes,
+// so that the sequence of reasons is kept on the node although the reason annotation is overwritten.
+// Since the mutating webhooks run before the validating ones, the entry is only persisted if the operation is approved.
+// It stamps the time the nodes are uncordoned at when the policy sets a minimum cordon cool-off period, and injects
+// the default reason of their ServiceAccount into the nodes cordoned by service accounts without a reason.
+// On dry run requests, it also stamps the predicted admission decision on the node, so that it is shown
+// by "kubectl apply --dry-run=server".
+type NodeMutator struct {
+	Decoder admission.Decoder
+	Client  client.Client
+	// PolicyResolver resolves the policy applying to a node. Defaults to a CRDPolicyResolver
+	// falling back to a ConfigMapPolicyResolver.
+	PolicyResolver PolicyResolver
+	// PolicyClient lists the NodeOperationPolicy objects for the default PolicyResolver.
+	// Defaults to a KubernetesPolicyClient using Client.
+	PolicyClient PolicyClient
+	// Clock provides the current time. Defaults to the system clock.
+	Clock Clock
+	// Validator predicts the admission decision stamped on the nodes of dry run requests. No decision is stamped if it is nil.
+	Validator *NodeValidator
+}
+
+// +kubebuilder:webhook:path=/mutate-v1-node,mutating=true,failurePolicy=ignore,sideEffects=None,groups=core,resources=nodes,verbs=update,versions=v1,name=nodereasonhistory.dana.io,admissionReviewVersions=v1
+
+// Handle patches the reason annotation of the node cordoned by a service account with a default reason,
+// its reason history annotation, and the dry run preview annotation on dry run requests.
+// The annotations are informative, so failing to update them never blocks the request.
+func (m *NodeMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx).WithName("Node Mutating Webhook").WithValues("node", req.Name)
+	ctx = withRequestUser(ctx, req.UserInfo.Username)
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("Node was not updated")
+	}
+
+	node := corev1.Node{}
+	oldNode := corev1.Node{}
+	if err := m.Decoder.DecodeRaw(req.OldObject, &oldNode); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
+	}
+	if err := m.Decoder.DecodeRaw(req.Object, &node); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
+	}
+
+	mutated := false
+	if reason, ok := m.defaultReason(ctx, req.UserInfo.Username, &oldNode, &node, logger); ok {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[ReasonAnnotationKey()] = reason
+		mutated = true
+	}
+	if history, ok := m.reasonHistory(ctx, req.UserInfo.Username, &oldNode, &node, logger); ok {
+		node.Annotations[reasonHistoryAnnotation] = history
+		mutated = true
+	}
+	if lastUncordoned, ok := m.lastUncordoned(ctx, &oldNode, &node, logger); ok {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[lastUncordonedAnnotation] = lastUncordoned
+		mutated = true
+	}
+	if isDryRunRequest(req) && m.Validator != nil {
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[dryRunPreviewAnnotation] = m.previewDecision(ctx, req, logger)
+		mutated = true
+	}
+	if !mutated {
+		return admission.Allowed("Node was not mutated")
+	}
+
+	patched, err := json.Marshal(&node)
+	if err != nil {
+		logger.Error(err, "Failed to encode the node, the annotations are not updated")
+		return admission.Allowed("Node was not mutated")
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, patched)
+}
+
+// reasonHistory returns the reason history of the node with the reason of the operation appended,
+// or false if the operation doesn't require a reason or the history couldn't be updated.
+func (m *NodeMutator) reasonHistory(ctx context.Context, user string, oldNode *corev1.Node, node *corev1.Node, logger logr.Logger) (string, bool) {
+	reason, doesReasonExist := node.Annotations[ReasonAnnotationKey()]
+	if !doesReasonExist {
+		return "", false
+	}
+
+	policy, err := policyResolver(m.PolicyResolver, m.PolicyClient, m.Client).Resolve(ctx, node)
+	if err != nil {
+		logger.Error(err, "Failed to resolve policy, the reason history is not updated")
+		return "", false
+	}
+	operation, isReasonRequired := updateOperation(oldNode, node, policy)
+	if !isReasonRequired {
+		return "", false
+	}
+
+	history, err := appendReasonHistory(node.Annotations[reasonHistoryAnnotation], ReasonHistoryEntry{
+		Timestamp: m.now().UTC(),
+		User:      user,
+		Operation: operation,
+		Reason:    reason,
+	}, policy.ReasonHistoryLimit)
+	if err != nil {
+		logger.Error(err, "Failed to update the reason history")
+		return "", false
+	}
+	return history, true
+}
+
+// appendReasonHistory appends the entry to the JSON array of the history, compacting it once it exceeds 80% of the limit,
+// and dropping the oldest entries beyond the limit. A limit of zero means the default limit.
+func appendReasonHistory(history string, entry ReasonHistoryEntry, limit int) (string, error) {
+	if limit <= 0 {
+		limit = defaultReasonHistoryLimit
+	}
+
+	var entries ReasonHistory
+	if history != "" {
+		if err := json.Unmarshal([]byte(history), &entries); err != nil {
+			return "", fmt.Errorf("invalid %q annotation: %w", reasonHistoryAnnotation, err)
+		}
+	}
+	entries = append(entries, entry)
+	if len(entries)*5 > limit*4 {
+		entries = entries.Compact()
+	}
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	updated, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	return string(updated), nil
+}
+
+// previewDecision returns the admission decision the validator would make on the request.
+func (m *NodeMutator) previewDecision(ctx context.Context, req admission.Request, logger logr.Logger) string {
+	allowed, reason, err := m.Validator.DryRunHandle(ctx, req)
+	switch {
+	case err != nil:
+		logger.Error(err, "Failed to predict the admission decision")
+		return fmt.Sprintf("error: %s", err)
+	case allowed:
+		return fmt.Sprintf("allowed: %s", reason)
+	default:
+		return fmt.Sprintf("denied: %s", reason)
+	}
+}
+
+// now returns the current time according to the clock of the mutator.
+func (m *NodeMutator) now() time.Time {
+	if m.Clock == nil {
+		return time.Now()
+	}
+	return m.Clock.Now()
+}
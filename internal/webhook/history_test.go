@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestAppendReasonHistory(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(reason string) ReasonHistoryEntry {
+		return ReasonHistoryEntry{Timestamp: now, User: regularUserExample, Operation: Cordon, Reason: reason}
+	}
+	history := func(entries ...ReasonHistoryEntry) string {
+		data, err := json.Marshal(entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name     string
+		history  string
+		limit    int
+		expected string
+		valid    bool
+	}{
+		{name: "EmptyHistory", history: "", expected: history(entry("Testing")), valid: true},
+		{name: "Accumulates", history: history(entry("First")), expected: history(entry("First"), entry("Testing")), valid: true},
+		{name: "DropsOldest", history: history(entry("First"), entry("Second")), limit: 2, expected: history(entry("Second"), entry("Testing")), valid: true},
+		{name: "CorruptHistory", history: "not json", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			updated, err := appendReasonHistory(test.history, entry("Testing"), test.limit)
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(updated).Should(MatchJSON(test.expected))
+		})
+	}
+}
+
+func TestAppendReasonHistoryDefaultLimit(t *testing.T) {
+	g := NewWithT(t)
+	history := ""
+	for i := range defaultReasonHistoryLimit + 5 {
+		var err error
+		history, err = appendReasonHistory(history, ReasonHistoryEntry{Reason: fmt.Sprint(i)}, 0)
+		g.Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	var entries []ReasonHistoryEntry
+	g.Expect(json.Unmarshal([]byte(history), &entries)).Should(Succeed())
+	g.Expect(entries).Should(HaveLen(defaultReasonHistoryLimit))
+	g.Expect(entries[0].Reason).Should(Equal("5"))
+}
+
+func TestReasonHistoryCompact(t *testing.T) {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	second, third := first.Add(time.Minute), first.Add(2*time.Minute)
+	entry := func(timestamp time.Time, user string, reason string) ReasonHistoryEntry {
+		return ReasonHistoryEntry{Timestamp: timestamp, User: user, Operation: Cordon, Reason: reason}
+	}
+	merged := func(entry ReasonHistoryEntry, count int, lastSeen time.Time) ReasonHistoryEntry {
+		entry.Count, entry.LastSeen = count, &lastSeen
+		return entry
+	}
+
+	tests := []struct {
+		name     string
+		history  ReasonHistory
+		expected ReasonHistory
+	}{
+		{name: "Empty", history: ReasonHistory{}, expected: ReasonHistory{}},
+		{name: "Distinct", history: ReasonHistory{entry(first, "alice", "Testing"), entry(second, "bob", "Testing"), entry(third, "alice", "Upgrade")},
+			expected: ReasonHistory{entry(first, "alice", "Testing"), entry(second, "bob", "Testing"), entry(third, "alice", "Upgrade")}},
+		{name: "Consecutive", history: ReasonHistory{entry(first, "alice", "Testing"), entry(second, "alice", "Testing"), entry(third, "alice", "Testing")},
+			expected: ReasonHistory{merged(entry(first, "alice", "Testing"), 3, third)}},
+		{name: "NotConsecutive", history: ReasonHistory{entry(first, "alice", "Testing"), entry(second, "alice", "Upgrade"), entry(third, "alice", "Testing")},
+			expected: ReasonHistory{entry(first, "alice", "Testing"), entry(second, "alice", "Upgrade"), entry(third, "alice", "Testing")}},
+		{name: "AlreadyCompacted", history: ReasonHistory{merged(entry(first, "alice", "Testing"), 2, second), merged(entry(third, "alice", "Testing"), 2, third.Add(time.Minute))},
+			expected: ReasonHistory{merged(entry(first, "alice", "Testing"), 4, third.Add(time.Minute))}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(test.history.Compact()).Should(Equal(test.expected))
+		})
+	}
+}
+
+func TestAppendReasonHistoryCompacts(t *testing.T) {
+	g := NewWithT(t)
+	history, err := appendReasonHistory("", ReasonHistoryEntry{User: regularUserExample, Operation: Cordon, Reason: "First"}, 0)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	// An automation retrying the same cordon doesn't push the first reason out of the history.
+	for range defaultReasonHistoryLimit * 2 {
+		history, err = appendReasonHistory(history, ReasonHistoryEntry{User: regularUserExample, Operation: Cordon, Reason: "Testing"}, 0)
+		g.Expect(err).ShouldNot(HaveOccurred())
+	}
+
+	var entries []ReasonHistoryEntry
+	g.Expect(json.Unmarshal([]byte(history), &entries)).Should(Succeed())
+	g.Expect(len(entries)).Should(BeNumerically("<=", defaultReasonHistoryLimit))
+	g.Expect(entries[0].Reason).Should(Equal("First"))
+	count := 0
+	for _, entry := range entries[1:] {
+		g.Expect(entry.Reason).Should(Equal("Testing"))
+		count += entry.count()
+	}
+	g.Expect(count).Should(Equal(defaultReasonHistoryLimit * 2))
+}
+
+// errorPolicyResolver is a PolicyResolver which always fails.
+type errorPolicyResolver struct{}
+
+func (errorPolicyResolver) Resolve(context.Context, *corev1.Node) (Policy, error) {
+	return Policy{}, errors.New("policy unavailable")
+}
+
+func TestNodeMutator(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		resolver    PolicyResolver
+		reasons     []string
+	}{
+		{name: "FirstEntry", annotations: map[string]string{reasonAnnotation: "Testing"}, reasons: []string{"Testing"}},
+		{name: "AppendsEntry", annotations: map[string]string{reasonAnnotation: "Testing", reasonHistoryAnnotation: `[{"reason":"First"},{"reason":"Second"}]`}, reasons: []string{"Second", "Testing"}},
+		{name: "NoReason", annotations: map[string]string{}},
+		{name: "CorruptHistory", annotations: map[string]string{reasonAnnotation: "Testing", reasonHistoryAnnotation: "not json"}},
+		{name: "PolicyError", annotations: map[string]string{reasonAnnotation: "Testing"}, resolver: errorPolicyResolver{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", reasonHistoryLimitKey: "2"},
+			})).Should(Succeed())
+			nm := NodeMutator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, PolicyResolver: test.resolver, Clock: &fakeClock{now: now}}
+
+			response := nm.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(BeTrue())
+			if len(test.reasons) == 0 {
+				g.Expect(response.Patches).Should(BeEmpty())
+				return
+			}
+
+			g.Expect(response.Patches).ShouldNot(BeEmpty())
+			history, ok := patchedAnnotation(response, reasonHistoryAnnotation)
+			g.Expect(ok).Should(BeTrue())
+			var entries []ReasonHistoryEntry
+			g.Expect(json.Unmarshal([]byte(history), &entries)).Should(Succeed())
+			var reasons []string
+			for _, entry := range entries {
+				reasons = append(reasons, entry.Reason)
+			}
+			g.Expect(reasons).Should(Equal(test.reasons))
+			last := entries[len(entries)-1]
+			g.Expect(last).Should(Equal(ReasonHistoryEntry{Timestamp: now, User: regularUserExample, Operation: Cordon, Reason: "Testing"}))
+		})
+	}
+}
+
+func TestDryRunPreview(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		annotations map[string]string
+		preview     string
+	}{
+		{name: "Allowed", dryRun: true, annotations: map[string]string{reasonAnnotation: "Testing"}, preview: "allowed: cordon operation has been approved"},
+		{name: "Denied", dryRun: true, annotations: map[string]string{reasonAnnotation: "for fun"}, preview: `denied: Invalid reason "for fun". Allowed reasons: [Testing]`},
+		{name: "DeniedWithoutAnnotations", dryRun: true, annotations: nil, preview: `denied: You must add "node.dana.io/reason" annotation`},
+		{name: "NotDryRun", dryRun: false, annotations: map[string]string{reasonAnnotation: "Testing"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			decoder := admission.NewDecoder(scheme.Scheme)
+			nm := NodeMutator{Decoder: decoder, Client: fakeClient, Validator: &NodeValidator{Decoder: decoder, Client: fakeClient}}
+
+			request := newCordonRequest(g, test.name, regularUserExample, test.annotations)
+			request.DryRun = &test.dryRun
+			response := nm.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(BeTrue())
+
+			preview, ok := patchedAnnotation(response, dryRunPreviewAnnotation)
+			g.Expect(ok).Should(Equal(test.dryRun))
+			g.Expect(preview).Should(Equal(test.preview))
+		})
+	}
+}
+
+// patchedAnnotation returns the value the patches of the response set to the annotation.
+func patchedAnnotation(response admission.Response, key string) (string, bool) {
+	for _, patch := range response.Patches {
+		switch patch.Path {
+		case "/metadata/annotations/" + strings.ReplaceAll(key, "/", "~1"):
+			return patch.Value.(string), true
+		case "/metadata/annotations":
+			value, ok := patch.Value.(map[string]interface{})[key]
+			if ok {
+				return value.(string), true
+			}
+		}
+	}
+	return "", false
+}
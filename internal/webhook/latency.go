@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// admissionLatency observes the latency of the admission requests handled by the webhook.
+var admissionLatency = newLatencyHistogram(metrics.Registry)
+
+// HistogramBucketConfig holds the upper bounds, in seconds, of the buckets of the admission latency histogram.
+// An empty config means the default Prometheus buckets are used.
+type HistogramBucketConfig []float64
+
+// parseHistogramBucketConfig parses a comma separated list of bucket upper bounds in milliseconds, e.g. "50,100,500".
+// The buckets are sorted, and must be positive and unique.
+func parseHistogramBucketConfig(value string) (HistogramBucketConfig, error) {
+	var buckets HistogramBucketConfig
+	for _, milliseconds := range strings.Split(value, ",") {
+		if strings.TrimSpace(milliseconds) == "" {
+			continue
+		}
+		bucket, err := strconv.ParseFloat(strings.TrimSpace(milliseconds), 64)
+		if err != nil || bucket <= 0 {
+			return nil, fmt.Errorf("invalid bucket %q, expected a positive number of milliseconds", milliseconds)
+		}
+		buckets = append(buckets, bucket/float64(time.Second/time.Millisecond))
+	}
+
+	slices.Sort(buckets)
+	if len(slices.Compact(slices.Clone(buckets))) != len(buckets) {
+		return nil, fmt.Errorf("buckets %q are not unique", value)
+	}
+	return buckets, nil
+}
+
+// latencyHistogram is a histogram whose buckets can be changed at runtime, by replacing the registered histogram.
+type latencyHistogram struct {
+	mu        sync.Mutex
+	registry  prometheus.Registerer
+	buckets   HistogramBucketConfig
+	histogram *prometheus.HistogramVec
+}
+
+// newLatencyHistogram registers a latency histogram with the default buckets in the registry.
+func newLatencyHistogram(registry prometheus.Registerer) *latencyHistogram {
+	h := &latencyHistogram{registry: registry, histogram: newLatencyHistogramVec(nil)}
+	registry.MustRegister(h.histogram)
+	return h
+}
+
+// newLatencyHistogramVec returns an unregistered admission latency histogram with the given buckets.
+func newLatencyHistogramVec(buckets HistogramBucketConfig) *prometheus.HistogramVec {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "node_operation_validator_admission_duration_seconds",
+		Help:    "Latency of the admission requests handled by the node operation validator.",
+		Buckets: buckets,
+	}, []string{"allowed"})
+}
+
+// configure re-registers the histogram with the given buckets if they changed. The observations
+// of the previous histogram are dropped, since they can't be redistributed to the new buckets.
+func (h *latencyHistogram) configure(buckets HistogramBucketConfig) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if slices.Equal(h.buckets, buckets) {
+		return nil
+	}
+	histogram := newLatencyHistogramVec(buckets)
+	h.registry.Unregister(h.histogram)
+	if err := h.registry.Register(histogram); err != nil {
+		// Keep the previous histogram registered, so that the latency is still observed.
+		h.registry.MustRegister(h.histogram)
+		return fmt.Errorf("failed to register the admission latency histogram: %w", err)
+	}
+	h.histogram, h.buckets = histogram, buckets
+	return nil
+}
+
+// observe records the latency of an admission request.
+func (h *latencyHistogram) observe(latency time.Duration, allowed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.histogram.WithLabelValues(strconv.FormatBool(allowed)).Observe(latency.Seconds())
+}
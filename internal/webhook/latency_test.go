@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestParseHistogramBucketConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	buckets, err := parseHistogramBucketConfig("500, 50,100")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(buckets).Should(Equal(HistogramBucketConfig{0.05, 0.1, 0.5}))
+
+	for _, value := range []string{"fast", "-5", "0", "50,50"} {
+		_, err = parseHistogramBucketConfig(value)
+		g.Expect(err).Should(HaveOccurred(), value)
+	}
+}
+
+func TestLatencyHistogramConfigure(t *testing.T) {
+	g := NewWithT(t)
+	registry := prometheus.NewRegistry()
+	histogram := newLatencyHistogram(registry)
+	histogram.observe(30*time.Millisecond, true)
+
+	g.Expect(histogram.configure(HistogramBucketConfig{0.05, 0.1})).Should(Succeed())
+	histogram.observe(30*time.Millisecond, true)
+	g.Expect(gatheredBuckets(g, registry)).Should(Equal([]float64{0.05, 0.1}))
+
+	// Configuring the same buckets again keeps the registered histogram.
+	g.Expect(histogram.configure(HistogramBucketConfig{0.05, 0.1})).Should(Succeed())
+	g.Expect(gatheredBuckets(g, registry)).Should(Equal([]float64{0.05, 0.1}))
+
+	g.Expect(histogram.configure(nil)).Should(Succeed())
+	histogram.observe(30*time.Millisecond, true)
+	g.Expect(gatheredBuckets(g, registry)).Should(Equal(prometheus.DefBuckets))
+}
+
+// gatheredBuckets returns the upper bounds of the buckets of the single histogram gathered from the registry.
+func gatheredBuckets(g *WithT, registry *prometheus.Registry) []float64 {
+	families, err := registry.Gather()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(families).Should(HaveLen(1))
+
+	var bounds []float64
+	for _, bucket := range families[0].GetMetric()[0].GetHistogram().GetBucket() {
+		bounds = append(bounds, bucket.GetUpperBound())
+	}
+	return bounds
+}
+
+func TestLatencyBucketsPolicy(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Cleanup(func() { _ = admissionLatency.configure(nil) })
+	buckets := func() HistogramBucketConfig {
+		admissionLatency.mu.Lock()
+		defer admissionLatency.mu.Unlock()
+		return admissionLatency.buckets
+	}
+	g.Expect(admissionLatency.configure(nil)).Should(Succeed())
+
+	policy := Policy{AllowedReasons: []string{"Testing"}, LatencyBuckets: HistogramBucketConfig{0.05, 0.1}}
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), PolicyResolver: staticPolicyResolver{policy: policy}}
+	request := newCordonRequest(g, "node-1", regularUserExample, map[string]string{reasonAnnotation: "Testing"})
+
+	// The dry runs don't configure the histogram.
+	_, _, err := nv.DryRunHandle(ctx, request)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(buckets()).Should(BeNil())
+
+	g.Expect(nv.Handle(ctx, request).Allowed).Should(BeTrue())
+	g.Expect(buckets()).Should(Equal(policy.LatencyBuckets))
+
+	// The histogram is only configured again when the policy changes.
+	g.Expect(nv.appliedPolicySettings.changed(policy)).Should(BeFalse())
+	policy.LatencyBuckets = HistogramBucketConfig{0.5}
+	g.Expect(nv.appliedPolicySettings.changed(policy)).Should(BeTrue())
+}
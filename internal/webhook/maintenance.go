@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database, since the manager image doesn't have one.
+	_ "time/tzdata"
+)
+
+const daysPerWeek = 7
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// MaintenanceWindow is a recurring weekly time window in which operations are allowed.
+// A window ending before it starts spans midnight, and ends on the day after each of its days.
+type MaintenanceWindow struct {
+	days        [daysPerWeek]bool
+	startMinute int
+	endMinute   int
+	location    *time.Location
+	expression  string
+}
+
+// String returns the expression the window was parsed from.
+func (w MaintenanceWindow) String() string {
+	return w.expression
+}
+
+// contains checks if the given time is within the window.
+func (w MaintenanceWindow) contains(t time.Time) bool {
+	local := t.In(w.location)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + daysPerWeek - 1) % daysPerWeek
+
+	switch {
+	case w.startMinute == w.endMinute:
+		return w.days[today]
+	case w.startMinute < w.endMinute:
+		return w.days[today] && minute >= w.startMinute && minute < w.endMinute
+	default:
+		return (w.days[today] && minute >= w.startMinute) || (w.days[yesterday] && minute < w.endMinute)
+	}
+}
+
+// nextStart returns the first start of the window after the given time.
+func (w MaintenanceWindow) nextStart(t time.Time) time.Time {
+	local := t.In(w.location)
+	for i := 0; i <= daysPerWeek; i++ {
+		start := time.Date(local.Year(), local.Month(), local.Day()+i, w.startMinute/60, w.startMinute%60, 0, 0, w.location)
+		if w.days[start.Weekday()] && start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// parseMaintenanceWindows parses a comma separated list of maintenance windows.
+func parseMaintenanceWindows(value string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, expression := range strings.Split(value, ",") {
+		if strings.TrimSpace(expression) == "" {
+			continue
+		}
+		window, err := parseMaintenanceWindow(expression)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// parseMaintenanceWindow parses a maintenance window expression of the form "<days> <HH:MM>-<HH:MM> <time zone>",
+// where days is either a single day or a range of days, e.g. "Mon-Fri 22:00-06:00 UTC" or "Sat 00:00-00:00 Europe/London".
+func parseMaintenanceWindow(expression string) (MaintenanceWindow, error) {
+	return parseWeeklyWindow("maintenance window", expression)
+}
+
+// parseWeeklyWindow parses a weekly window expression of the form "<days> <HH:MM>-<HH:MM> <time zone>".
+// The kind of the window names it in the errors.
+func parseWeeklyWindow(kind string, expression string) (MaintenanceWindow, error) {
+	window := MaintenanceWindow{expression: strings.TrimSpace(expression)}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 3 {
+		return MaintenanceWindow{}, fmt.Errorf("%s %q must be of the form \"<days> <HH:MM>-<HH:MM> <time zone>\"", kind, window.expression)
+	}
+
+	firstDay, lastDay, isRange := strings.Cut(fields[0], "-")
+	if !isRange {
+		lastDay = firstDay
+	}
+	first, ok := weekdays[strings.ToLower(firstDay)]
+	if !ok {
+		return MaintenanceWindow{}, fmt.Errorf("invalid day %q in %s %q", firstDay, kind, window.expression)
+	}
+	last, ok := weekdays[strings.ToLower(lastDay)]
+	if !ok {
+		return MaintenanceWindow{}, fmt.Errorf("invalid day %q in %s %q", lastDay, kind, window.expression)
+	}
+	for day := first; ; day = (day + 1) % daysPerWeek {
+		window.days[day] = true
+		if day == last {
+			break
+		}
+	}
+
+	start, end, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return MaintenanceWindow{}, fmt.Errorf("invalid time range %q in %s %q", fields[1], kind, window.expression)
+	}
+	var err error
+	if window.startMinute, err = parseMinuteOfDay(start); err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid start time in %s %q: %w", kind, window.expression, err)
+	}
+	if window.endMinute, err = parseMinuteOfDay(end); err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid end time in %s %q: %w", kind, window.expression, err)
+	}
+
+	if window.location, err = time.LoadLocation(fields[2]); err != nil {
+		return MaintenanceWindow{}, fmt.Errorf("invalid time zone in %s %q: %w", kind, window.expression, err)
+	}
+	return window, nil
+}
+
+// parseMinuteOfDay parses a HH:MM time into the number of minutes since midnight.
+func parseMinuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// isWithinMaintenanceWindow checks if the given time is within any of the maintenance windows.
+func isWithinMaintenanceWindow(windows []MaintenanceWindow, now time.Time) bool {
+	for _, window := range windows {
+		if window.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMaintenanceWindow returns the maintenance window starting the soonest after the given time, and its start time.
+func nextMaintenanceWindow(windows []MaintenanceWindow, now time.Time) (MaintenanceWindow, time.Time) {
+	var next MaintenanceWindow
+	var nextStart time.Time
+	for _, window := range windows {
+		start := window.nextStart(now)
+		if nextStart.IsZero() || start.Before(nextStart) {
+			next, nextStart = window, start
+		}
+	}
+	return next, nextStart
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestIsWithinMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows string
+		now     time.Time
+		within  bool
+	}{
+		{name: "InsideWindow", windows: "Mon-Fri 22:00-06:00 UTC", now: time.Date(2026, 10, 12, 23, 0, 0, 0, time.UTC), within: true},
+		{name: "InsideWindowAfterMidnight", windows: "Mon-Fri 22:00-06:00 UTC", now: time.Date(2026, 10, 13, 5, 0, 0, 0, time.UTC), within: true},
+		{name: "InsideWindowSpanningToWeekend", windows: "Mon-Fri 22:00-06:00 UTC", now: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), within: true},
+		{name: "OutsideWindowOnFirstDayMorning", windows: "Mon-Fri 22:00-06:00 UTC", now: time.Date(2026, 10, 12, 5, 0, 0, 0, time.UTC), within: false},
+		{name: "OutsideWindow", windows: "Mon-Fri 22:00-06:00 UTC", now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), within: false},
+		{name: "WholeDayWindow", windows: "Sat 00:00-00:00 UTC", now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), within: true},
+		{name: "MultipleWindows", windows: "Mon 01:00-02:00 UTC, Wed 11:00-13:00 UTC", now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), within: true},
+		{name: "LocalTimeInsideWindow", windows: "Mon-Fri 22:00-06:00 Asia/Tokyo", now: time.Date(2026, 10, 12, 14, 0, 0, 0, time.UTC), within: true},
+		{name: "LocalTimeOutsideWindow", windows: "Mon-Fri 22:00-06:00 Asia/Tokyo", now: time.Date(2026, 10, 12, 23, 0, 0, 0, time.UTC), within: false},
+		{name: "NoWindows", windows: "", now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), within: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			windows, err := parseMaintenanceWindows(test.windows)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(isWithinMaintenanceWindow(windows, test.now)).Should(Equal(test.within))
+		})
+	}
+}
+
+func TestNextMaintenanceWindow(t *testing.T) {
+	g := NewWithT(t)
+	windows, err := parseMaintenanceWindows("Sat 10:00-12:00 UTC,Mon-Fri 22:00-06:00 UTC")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	window, start := nextMaintenanceWindow(windows, time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	g.Expect(window.String()).Should(Equal("Mon-Fri 22:00-06:00 UTC"))
+	g.Expect(start).Should(BeTemporally("==", time.Date(2026, 10, 14, 22, 0, 0, 0, time.UTC)))
+}
+
+func TestParseMaintenanceWindowErrors(t *testing.T) {
+	for _, expression := range []string{"Mon-Fri 22:00-06:00", "Funday 22:00-06:00 UTC", "Mon 22:00 UTC", "Mon 25:00-06:00 UTC", "Mon 22:00-06:00 Mars/Olympus"} {
+		t.Run(expression, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := parseMaintenanceWindow(expression)
+			g.Expect(err).Should(HaveOccurred())
+		})
+	}
+}
+
+func TestMaintenanceWindowEnforcement(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		now     time.Time
+		allowed bool
+	}{
+		{name: "InsideWindow", data: map[string]string{maintenanceWindowsKey: "Mon-Fri 22:00-06:00 UTC"}, now: time.Date(2026, 10, 12, 23, 0, 0, 0, time.UTC), allowed: true},
+		{name: "OutsideWindow", data: map[string]string{maintenanceWindowsKey: "Mon-Fri 22:00-06:00 UTC"}, now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), allowed: false},
+		{name: "NoWindowsConfigured", data: map[string]string{}, now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+
+			test.data[allowedReasonsKey] = "Testing"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: test.now}}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(OutsideMaintenanceWindowCode))
+				g.Expect(detail.Operation).Should(Equal(Cordon))
+				g.Expect(detail.Message).Should(ContainSubstring("2026-10-14T22:00:00Z"))
+			}
+		})
+	}
+}
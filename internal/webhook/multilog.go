@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WithAdditionalLogger registers a logger to which the logs of the validator are written in addition to the logger
+// of the request context, e.g. to send JSON logs to a SIEM while writing human-readable logs to stdout.
+// It must be called before the validator handles requests.
+func (n *NodeValidator) WithAdditionalLogger(l logr.Logger) *NodeValidator {
+	n.additionalLoggers = append(n.additionalLoggers, l)
+	return n
+}
+
+// logger returns the logger of the request context, fanning out to the additional loggers of the validator.
+func (n *NodeValidator) logger(ctx context.Context) logr.Logger {
+	logger := log.FromContext(ctx)
+	if len(n.additionalLoggers) == 0 {
+		return logger
+	}
+
+	sinks := []logr.LogSink{logger.GetSink()}
+	for _, additional := range n.additionalLoggers {
+		sinks = append(sinks, additional.GetSink())
+	}
+	return logr.New(newMultiSink(sinks...))
+}
+
+// multiSink is a logr.LogSink writing every log line to all of its sinks.
+type multiSink struct {
+	sinks []logr.LogSink
+}
+
+// newMultiSink returns a sink writing to all of the given sinks, ignoring the nil sinks of discarding loggers.
+func newMultiSink(sinks ...logr.LogSink) *multiSink {
+	s := &multiSink{}
+	for _, sink := range sinks {
+		if sink != nil {
+			s.sinks = append(s.sinks, sink)
+		}
+	}
+	return s
+}
+
+// Init initializes the sinks, accounting for the additional call frame of the multiSink.
+func (s *multiSink) Init(info logr.RuntimeInfo) {
+	info.CallDepth++
+	for _, sink := range s.sinks {
+		sink.Init(info)
+	}
+}
+
+// Enabled returns true if any of the sinks is enabled at the level.
+func (s *multiSink) Enabled(level int) bool {
+	for _, sink := range s.sinks {
+		if sink.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Info writes the log line to the sinks which are enabled at the level.
+func (s *multiSink) Info(level int, msg string, keysAndValues ...any) {
+	for _, sink := range s.sinks {
+		if sink.Enabled(level) {
+			sink.Info(level, msg, keysAndValues...)
+		}
+	}
+}
+
+// Error writes the error to all of the sinks.
+func (s *multiSink) Error(err error, msg string, keysAndValues ...any) {
+	for _, sink := range s.sinks {
+		sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+// WithValues returns a multiSink whose sinks have the additional key-value pairs.
+func (s *multiSink) WithValues(keysAndValues ...any) logr.LogSink {
+	sinks := make([]logr.LogSink, 0, len(s.sinks))
+	for _, sink := range s.sinks {
+		sinks = append(sinks, sink.WithValues(keysAndValues...))
+	}
+	return &multiSink{sinks: sinks}
+}
+
+// WithName returns a multiSink whose sinks have the name appended.
+func (s *multiSink) WithName(name string) logr.LogSink {
+	sinks := make([]logr.LogSink, 0, len(s.sinks))
+	for _, sink := range s.sinks {
+		sinks = append(sinks, sink.WithName(name))
+	}
+	return &multiSink{sinks: sinks}
+}
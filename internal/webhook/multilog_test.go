@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// recordingLogger returns a logger recording its log lines, up to the given verbosity.
+func recordingLogger(lines *[]string, verbosity int) logr.Logger {
+	return funcr.New(func(prefix, args string) {
+		*lines = append(*lines, prefix+" "+args)
+	}, funcr.Options{Verbosity: verbosity})
+}
+
+func TestMultiSink(t *testing.T) {
+	g := NewWithT(t)
+	var primary, secondary []string
+	logger := logr.New(newMultiSink(recordingLogger(&primary, 0).GetSink(), nil, recordingLogger(&secondary, 1).GetSink()))
+
+	logger.WithName("Test").WithValues("node", "node-1").Info("info")
+	logger.V(1).Info("debug")
+	logger.Error(errors.New("failure"), "error")
+
+	g.Expect(primary).Should(HaveLen(2))
+	g.Expect(primary[0]).Should(And(HavePrefix("Test"), ContainSubstring(`"msg"="info"`), ContainSubstring(`"node"="node-1"`)))
+	g.Expect(primary[1]).Should(ContainSubstring(`"error"="failure"`))
+	g.Expect(secondary).Should(HaveLen(3))
+	g.Expect(secondary[1]).Should(ContainSubstring(`"msg"="debug"`))
+}
+
+func TestWithAdditionalLogger(t *testing.T) {
+	g := NewWithT(t)
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	var primary, additional []string
+	ctx := log.IntoContext(context.Background(), recordingLogger(&primary, 0))
+	nv := (&NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}).WithAdditionalLogger(recordingLogger(&additional, 0))
+
+	response := nv.Handle(ctx, newCordonRequest(g, "node-1", regularUserExample, nil))
+	g.Expect(response.Allowed).Should(BeFalse())
+	g.Expect(primary).ShouldNot(BeEmpty())
+	g.Expect(additional).Should(Equal(primary))
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const defaultReasonAnnotation = "node.dana.io/default-reason"
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+
+// defaultReason returns the reason to inject into a node cordoned by a service account without a reason: the value of
+// the default reason annotation of its ServiceAccount. It returns false if the node already has a reason, so that
+// an existing reason is never overwritten, or if the ServiceAccount has no default reason, in which case
+// the validating webhook enforces the usual rules. Deletions can't be mutated, so only cordons get a default reason.
+func (m *NodeMutator) defaultReason(ctx context.Context, user string, oldNode *corev1.Node, node *corev1.Node, logger logr.Logger) (string, bool) {
+	if oldNode.Spec.Unschedulable || !node.Spec.Unschedulable {
+		return "", false
+	}
+	if _, ok := node.Annotations[ReasonAnnotationKey()]; ok {
+		return "", false
+	}
+	if _, ok := node.Annotations[reasonSecretRefAnnotation]; ok {
+		return "", false
+	}
+	policy, err := policyResolver(m.PolicyResolver, m.PolicyClient, m.Client).Resolve(ctx, node)
+	if err != nil {
+		logger.Error(err, "Failed to resolve policy, no default reason is injected")
+		return "", false
+	}
+	key, ok := serviceAccountKey(user, policy.serviceAccountPrefix())
+	if !ok {
+		return "", false
+	}
+
+	serviceAccount := corev1.ServiceAccount{}
+	if err := m.Client.Get(ctx, key, &serviceAccount); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to fetch the ServiceAccount, no default reason is injected", "ServiceAccount", key.String())
+		}
+		return "", false
+	}
+	reason := serviceAccount.Annotations[defaultReasonAnnotation]
+	return reason, reason != ""
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestServiceAccountDefaultReason(t *testing.T) {
+	const automationUser = serviceAccountUser + "ci:node-drainer"
+
+	tests := []struct {
+		name          string
+		user          string
+		saAnnotations map[string]string
+		annotations   map[string]string
+		reason        string
+		allowed       bool
+	}{
+		{name: "Injected", user: automationUser, saAnnotations: map[string]string{defaultReasonAnnotation: "Testing"}, reason: "Testing", allowed: true},
+		{name: "NoDefaultReason", user: automationUser, allowed: false},
+		{name: "ExistingReasonKept", user: automationUser, saAnnotations: map[string]string{defaultReasonAnnotation: "Testing"},
+			annotations: map[string]string{reasonAnnotation: "Upgrade"}, allowed: true},
+		{name: "RegularUser", user: regularUserExample, allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing,Upgrade", trustedSANamespacesKey: "monitoring"},
+			})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-drainer", Namespace: "ci", Annotations: test.saAnnotations},
+			})).Should(Succeed())
+			decoder := admission.NewDecoder(scheme.Scheme)
+			nm := NodeMutator{Decoder: decoder, Client: fakeClient}
+			nv := NodeValidator{Decoder: decoder, Client: fakeClient}
+
+			response := nm.Handle(ctx, newCordonRequest(g, test.name, test.user, test.annotations))
+			g.Expect(response.Allowed).Should(BeTrue())
+			reason, injected := patchedAnnotation(response, reasonAnnotation)
+			g.Expect(injected).Should(Equal(test.reason != ""))
+			g.Expect(reason).Should(Equal(test.reason))
+
+			// The validating webhook sees the node as patched by the mutating webhook.
+			annotations := map[string]string{}
+			for key, value := range test.annotations {
+				annotations[key] = value
+			}
+			if injected {
+				annotations[reasonAnnotation] = reason
+			}
+			response = nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(MissingReasonCode))
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// isBypassNode checks if the node name matches any of the glob patterns, e.g. "spot-*".
+// Invalid patterns match no node.
+func isBypassNode(nodeName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, nodeName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBypassNodes parses a comma separated list of node name glob patterns.
+func parseBypassNodes(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid node name pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// bypassNode allows any operation on a node matching the bypass nodes of the policy, such as disposable spot nodes,
+// and records it as an event on the node unless in dry run mode. It returns false if the node doesn't match.
+func (n *NodeValidator) bypassNode(operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool) {
+	if !isBypassNode(node.Name, policy.BypassNodes) {
+		return admission.Response{}, false
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionAllowed, Grounds: "node matches the bypass nodes"})
+	if !dryRun {
+		n.decisions.record(user, false, n.now())
+	}
+	if !dryRun && n.Recorder != nil {
+		n.Recorder.Eventf(node, corev1.EventTypeNormal, nodeBypassEvent, "%s operation by %q has been approved without validation since the node matches the bypass nodes", operation, user)
+	}
+	return admission.Allowed(fmt.Sprintf("%s operation has been approved since the node matches the bypass nodes", operation)), true
+}
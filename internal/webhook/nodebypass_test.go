@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestBypassNodes(t *testing.T) {
+	tests := []struct {
+		name        string
+		bypassNodes *string
+		nodeName    string
+		allowed     bool
+	}{
+		{name: "ExactMatch", bypassNodes: ptr("gpu-1,spot-node"), nodeName: "spot-node", allowed: true},
+		{name: "WildcardMatch", bypassNodes: ptr("gpu-1, spot-*"), nodeName: "spot-a1b2", allowed: true},
+		{name: "NoMatch", bypassNodes: ptr("spot-*"), nodeName: "worker-1", allowed: false},
+		{name: "EmptyKey", bypassNodes: ptr(""), nodeName: "spot-a1b2", allowed: false},
+		{name: "NoKey", bypassNodes: nil, nodeName: "spot-a1b2", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing"}
+			if test.bypassNodes != nil {
+				data[bypassNodesKey] = *test.bypassNodes
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			recorder := record.NewFakeRecorder(10)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Recorder: recorder}
+
+			response := nv.Handle(ctx, newDeleteRequest(g, test.nodeName, regularUserExample, nil))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.allowed {
+				g.Expect(recorder.Events).Should(Receive(HavePrefix(corev1.EventTypeNormal + " " + nodeBypassEvent)))
+			}
+		})
+	}
+}
+
+func TestParseBypassNodes(t *testing.T) {
+	g := NewWithT(t)
+	patterns, err := parseBypassNodes("spot-*, ,preemptible-?")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(patterns).Should(Equal([]string{"spot-*", "preemptible-?"}))
+
+	_, err = parseBypassNodes("spot-[")
+	g.Expect(err).Should(HaveOccurred())
+}
+
+// ptr returns a pointer to the value.
+func ptr[T any](value T) *T {
+	return &value
+}
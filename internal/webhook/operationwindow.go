@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const operationWindowAnnotation = "node.dana.io/operation-window"
+
+// OperationWindow is a recurring weekly time window in which a specific node may be cordoned or deleted.
+// It has the same format and semantics as a MaintenanceWindow.
+type OperationWindow struct {
+	MaintenanceWindow
+}
+
+// parseOperationWindow parses an operation window expression of the form "<days> <HH:MM>-<HH:MM> <time zone>",
+// e.g. "Sat 00:00-06:00 UTC".
+func parseOperationWindow(s string) (OperationWindow, error) {
+	window, err := parseWeeklyWindow("operation window", s)
+	if err != nil {
+		return OperationWindow{}, err
+	}
+	return OperationWindow{MaintenanceWindow: window}, nil
+}
+
+// isOperationWindowOperation checks if the operation is restricted by the operation window of the node.
+func isOperationWindowOperation(operation Operation) bool {
+	return operation == Cordon || operation == Drain || operation == Delete
+}
+
+// checkOperationWindow denies cordoning or deleting a node outside of the operation window set by the operation
+// window annotation of the node. An invalid operation window denies the operation, since the intent of the owner
+// of the node is unknown. The forbidden users and the service accounts of the trusted namespaces aren't restricted,
+// the former being denied anyway. It returns false if the operation is denied.
+func (n *NodeValidator) checkOperationWindow(operation Operation, node *corev1.Node, user string, groups []string, policy Policy, log logr.Logger) (admission.Response, bool) {
+	expression, hasWindow := node.Annotations[operationWindowAnnotation]
+	if !hasWindow || !isOperationWindowOperation(operation) || isForbidden(user, groups, policy) || isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		return admission.Response{}, true
+	}
+
+	window, err := parseOperationWindow(expression)
+	if err != nil {
+		logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: InvalidOperationWindowCode,
+			Grounds: "invalid operation window", Details: []any{"Error", err.Error()}})
+		return denied(policy, DenialDetail{
+			Code:      InvalidOperationWindowCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("%s operation is denied since the %q annotation is invalid: %s", operation, operationWindowAnnotation, err),
+		}), false
+	}
+
+	if now := n.now(); !window.contains(now) {
+		logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: OutsideOperationWindowCode, Grounds: "outside of operation window"})
+		return denied(policy, DenialDetail{
+			Code:      OutsideOperationWindowCode,
+			Operation: operation,
+			User:      user,
+			Message: fmt.Sprintf("%s operation is only allowed on node %q during its operation window. The next window %q starts at %s",
+				operation, node.Name, window, window.nextStart(now).Format(time.RFC3339)),
+		}), false
+	}
+	return admission.Response{}, true
+}
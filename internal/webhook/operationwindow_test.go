@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestOperationWindowContains(t *testing.T) {
+	tests := []struct {
+		name   string
+		window string
+		now    time.Time
+		within bool
+	}{
+		{name: "InsideWindow", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), within: true},
+		{name: "AtWindowStart", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), within: true},
+		{name: "AtWindowEnd", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC), within: false},
+		{name: "OtherDay", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC), within: false},
+		{name: "WindowSpanningMidnight", window: "Sat 22:00-02:00 UTC", now: time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC), within: true},
+		{name: "TimeZoneAheadOfUTC", window: "Sat 00:00-06:00 Asia/Tokyo", now: time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC), within: true},
+		{name: "TimeZoneAheadOfUTCOutside", window: "Sat 00:00-06:00 Asia/Tokyo", now: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), within: false},
+		{name: "TimeZoneBehindUTC", window: "Fri 22:00-23:00 America/New_York", now: time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC), within: true},
+		{name: "SummerTime", window: "Sat 00:00-06:00 Europe/London", now: time.Date(2026, 7, 3, 23, 30, 0, 0, time.UTC), within: true},
+		{name: "WinterTime", window: "Sat 00:00-06:00 Europe/London", now: time.Date(2026, 1, 2, 23, 30, 0, 0, time.UTC), within: false},
+		{name: "BeforeSpringForward", window: "Sun 01:00-03:00 Europe/London", now: time.Date(2026, 3, 29, 0, 30, 0, 0, time.UTC), within: false},
+		{name: "AfterSpringForward", window: "Sun 01:00-03:00 Europe/London", now: time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC), within: true},
+		{name: "AfterSpringForwardWindowEnd", window: "Sun 01:00-03:00 Europe/London", now: time.Date(2026, 3, 29, 2, 0, 0, 0, time.UTC), within: false},
+		{name: "BeforeFallBack", window: "Sun 00:00-02:00 Europe/London", now: time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC), within: true},
+		{name: "RepeatedHourAfterFallBack", window: "Sun 00:00-02:00 Europe/London", now: time.Date(2026, 10, 25, 1, 30, 0, 0, time.UTC), within: true},
+		{name: "AfterFallBackWindowEnd", window: "Sun 00:00-02:00 Europe/London", now: time.Date(2026, 10, 25, 2, 0, 0, 0, time.UTC), within: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			window, err := parseOperationWindow(test.window)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(window.contains(test.now)).Should(Equal(test.within))
+		})
+	}
+}
+
+func TestOperationWindowNextStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		window string
+		now    time.Time
+		start  time.Time
+	}{
+		{name: "LaterThisWeek", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC), start: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{name: "NextWeek", window: "Sat 00:00-06:00 UTC", now: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC), start: time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC)},
+		{name: "BeforeFallBack", window: "Sun 00:00-02:00 Europe/London", now: time.Date(2026, 10, 24, 12, 0, 0, 0, time.UTC), start: time.Date(2026, 10, 24, 23, 0, 0, 0, time.UTC)},
+		{name: "AfterFallBack", window: "Sun 00:00-02:00 Europe/London", now: time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC), start: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			window, err := parseOperationWindow(test.window)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(window.nextStart(test.now)).Should(BeTemporally("==", test.start))
+		})
+	}
+}
+
+func TestParseOperationWindowErrors(t *testing.T) {
+	for _, expression := range []string{"", "Sat 00:00-06:00", "Sat,Sun 00:00-06:00 UTC", "Sat 00:00 UTC", "Sat 00:00-24:00 UTC", "Sat 00:00-06:00 Mars/Olympus"} {
+		t.Run(expression, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := parseOperationWindow(expression)
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(err.Error()).Should(ContainSubstring("operation window"))
+		})
+	}
+}
+
+func TestOperationWindowEnforcement(t *testing.T) {
+	insideWindow := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+	outsideWindow := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		window    string
+		user      string
+		operation Operation
+		now       time.Time
+		allowed   bool
+		code      string
+	}{
+		{name: "CordonInsideWindow", window: "Sat 00:00-06:00 UTC", user: regularUserExample, operation: Cordon, now: insideWindow, allowed: true},
+		{name: "CordonOutsideWindow", window: "Sat 00:00-06:00 UTC", user: regularUserExample, operation: Cordon, now: outsideWindow, allowed: false, code: OutsideOperationWindowCode},
+		{name: "DeleteOutsideWindow", window: "Sat 00:00-06:00 UTC", user: regularUserExample, operation: Delete, now: outsideWindow, allowed: false, code: OutsideOperationWindowCode},
+		{name: "UncordonOutsideWindow", window: "Sat 00:00-06:00 UTC", user: regularUserExample, operation: Uncordon, now: outsideWindow, allowed: true},
+		{name: "ServiceAccountOutsideWindow", window: "Sat 00:00-06:00 UTC", user: trustedServiceAccount, operation: Cordon, now: outsideWindow, allowed: true},
+		{name: "InvalidWindow", window: "Saturday night", user: regularUserExample, operation: Cordon, now: insideWindow, allowed: false, code: InvalidOperationWindowCode},
+		{name: "NoWindow", user: regularUserExample, operation: Cordon, now: outsideWindow, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: test.now}}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.window != "" {
+				annotations[operationWindowAnnotation] = test.window
+			}
+
+			var response admission.Response
+			switch test.operation {
+			case Cordon:
+				response = nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			case Uncordon:
+				delete(annotations, reasonAnnotation)
+				node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name, Annotations: annotations}, Spec: corev1.NodeSpec{Unschedulable: true}}
+				uncordonedNode := *node.DeepCopy()
+				uncordonedNode.Spec.Unschedulable = false
+				response = nv.Handle(ctx, newUpdateRequest(g, test.user, node, uncordonedNode))
+			case Delete:
+				response = nv.Handle(ctx, newDeleteRequest(g, test.name, test.user, annotations))
+			}
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(test.code))
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const reasonAuthorAnnotation = "node.dana.io/reason-author"
+
+// validateReasonOwnership denies an approved operation unless the reason author annotation of the node is the
+// requesting user, so that a reason added by one user can't be used by another user to perform the operation.
+// In warn only mode, the denial message is added to the warnings of the given response.
+func validateReasonOwnership(operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	if !response.Allowed {
+		return response
+	}
+
+	author, ok := node.Annotations[reasonAuthorAnnotation]
+	switch {
+	case !ok:
+		logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: MissingReasonAuthorCode,
+			Grounds: "reason author annotation doesn't exist"})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      MissingReasonAuthorCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("You must add %q annotation with your username along with the reason", reasonAuthorAnnotation),
+		})
+
+	case author != user:
+		logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: ReasonAuthorMismatchCode,
+			Grounds: "reason added by another user", Details: []any{"Author", author}})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      ReasonAuthorMismatchCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("The reason was added by %q, so %q user cannot use it. Please set the reason and the %q annotation yourself", author, user, reasonAuthorAnnotation),
+		})
+
+	default:
+		return response
+	}
+}
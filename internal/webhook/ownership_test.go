@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestReasonOwnership(t *testing.T) {
+	const serviceAccount = serviceAccountUser + "ci:node-drainer"
+
+	tests := []struct {
+		name      string
+		ownership string
+		user      string
+		author    string
+		warnOnly  bool
+		allowed   bool
+		code      string
+	}{
+		{name: "AuthorMatches", ownership: "true", user: regularUserExample, author: regularUserExample, allowed: true},
+		{name: "AuthorMismatches", ownership: "true", user: regularUserExample, author: "someone-else", allowed: false, code: ReasonAuthorMismatchCode},
+		{name: "AuthorAbsent", ownership: "true", user: regularUserExample, allowed: false, code: MissingReasonAuthorCode},
+		{name: "AuthorMismatchesInWarnOnlyMode", ownership: "true", user: regularUserExample, author: "someone-else", warnOnly: true, allowed: true},
+		{name: "ServiceAccountExempt", ownership: "true", user: serviceAccount, allowed: true},
+		{name: "OwnershipNotRequired", ownership: "false", user: regularUserExample, author: "someone-else", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing", reasonOwnershipKey: test.ownership, trustedSANamespacesKey: "kube-system"}
+			if test.warnOnly {
+				data[warnOnlyKey] = "true"
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.author != "" {
+				annotations[reasonAuthorAnnotation] = test.author
+			}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(test.code))
+			}
+			if test.warnOnly {
+				g.Expect(response.Warnings).Should(ContainElement(ContainSubstring(reasonAuthorAnnotation)))
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultForbiddenReasonPatterns match the template placeholders which are submitted as reasons by mistake,
+// e.g. by CI pipelines: unexpanded shell variables, XML-like placeholders, and TODO markers.
+var defaultForbiddenReasonPatterns = []string{
+	`^\$[A-Z_]+$`,
+	`^<.+>$`,
+	`(?i)^(TODO|FIXME|TBD)$`,
+}
+
+// forbiddenReasonPattern returns the first of the default and the given forbidden reason patterns
+// matching the reason, or false if none matches.
+func forbiddenReasonPattern(patterns []string, reason string) (string, bool) {
+	trimmed := strings.TrimSpace(reason)
+	for _, pattern := range append(defaultForbiddenReasonPatterns, patterns...) {
+		if compiledPattern, err := compilePattern(pattern); err == nil && compiledPattern != nil && compiledPattern.MatchString(trimmed) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// parseForbiddenReasonPatterns parses a newline separated list of regular expressions.
+// The patterns are separated by newlines rather than commas, since commas are common in regular expressions.
+func parseForbiddenReasonPatterns(value string) ([]string, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, "\n") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestForbiddenReasonPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		reason  string
+		allowed bool
+	}{
+		{name: "ShellVariable", reason: "$REASON", allowed: false},
+		{name: "XMLPlaceholder", reason: "<reason>", allowed: false},
+		{name: "TODO", reason: "TODO", allowed: false},
+		{name: "LowercaseTBD", reason: "tbd", allowed: false},
+		{name: "PaddedFIXME", reason: " FIXME ", allowed: false},
+		{name: "CustomPattern", reason: "N/A", allowed: false},
+		{name: "ReasonMentioningTODO", reason: "TODO list item 3: replace disk", allowed: true},
+		{name: "ReasonWithDollar", reason: "Cost $100 saved by scaling down", allowed: true},
+		{name: "ValidReason", reason: "Kernel upgrade", allowed: true},
+	}
+
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data: map[string]string{
+			allowFreetextKey:    "true",
+			forbiddenReasonsKey: "(?i)^n/?a$\n^-+$\n",
+		},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: test.reason}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(PlaceholderReasonCode))
+				g.Expect(response.Result.Code).Should(Equal(CodeInvalidReason))
+			}
+		})
+	}
+}
+
+func TestParseForbiddenReasonPatterns(t *testing.T) {
+	g := NewWithT(t)
+	patterns, err := parseForbiddenReasonPatterns("^N/A$\n\n  ^x{2,3}$  \n")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(patterns).Should(Equal([]string{"^N/A$", "^x{2,3}$"}))
+
+	_, err = parseForbiddenReasonPatterns("^N/A$\n[")
+	g.Expect(err).Should(HaveOccurred())
+}
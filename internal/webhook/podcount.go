@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// podNodeNameField is the field selector by which the pods of a node are listed.
+const podNodeNameField = "spec.nodeName"
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// skipReasonForPodCount approves an operation requiring a reason on a node running fewer pods than the minimum
+// pod count of the policy, since a reason adds friction without benefit on idle nodes. It returns false if
+// the reason is required.
+func (n *NodeValidator) skipReasonForPodCount(ctx context.Context, operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger) (admission.Response, bool) {
+	if policy.RequireReasonMinPodCount <= 0 {
+		return admission.Response{}, false
+	}
+
+	podCount, err := n.countRunningPods(ctx, node.Name)
+	if err != nil {
+		log.Error(err, "Failed to count the running pods of the node")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to count the running pods of node %q: %w", node.Name, err)), true
+	}
+	if podCount >= policy.RequireReasonMinPodCount {
+		return admission.Response{}, false
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionAllowed,
+		Grounds: "node runs fewer pods than required for a reason", Details: []any{"PodCount", podCount}})
+	return admission.Allowed(fmt.Sprintf("%s operation has been approved since the node runs %d pods, fewer than the %d requiring a reason",
+		operation, podCount, policy.RequireReasonMinPodCount)), true
+}
+
+// countRunningPods returns the number of running pods on the node.
+func (n *NodeValidator) countRunningPods(ctx context.Context, nodeName string) (int, error) {
+	pods := corev1.PodList{}
+	if err := n.Client.List(ctx, &pods, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			count++
+		}
+	}
+	return count, nil
+}
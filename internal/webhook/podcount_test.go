@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestRequireReasonMinPodCount(t *testing.T) {
+	const nodeName = "dev-node"
+
+	tests := []struct {
+		name          string
+		minPodCount   string
+		runningPods   int
+		completedPods int
+		user          string
+		listError     bool
+		allowed       bool
+		code          int32
+	}{
+		{name: "BelowMinPodCount", minPodCount: "2", runningPods: 1, user: regularUserExample, allowed: true},
+		{name: "AtMinPodCount", minPodCount: "2", runningPods: 2, user: regularUserExample, allowed: false, code: CodeMissingReason},
+		{name: "CompletedPodsNotCounted", minPodCount: "2", runningPods: 1, completedPods: 3, user: regularUserExample, allowed: true},
+		{name: "AlwaysRequired", minPodCount: "0", runningPods: 0, user: regularUserExample, allowed: false, code: CodeMissingReason},
+		{name: "ForbiddenUser", minPodCount: "2", runningPods: 0, user: systemAdminUser, allowed: false, code: CodeForbiddenUser},
+		{name: "ListError", minPodCount: "2", listError: true, user: regularUserExample, allowed: false, code: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			objects := []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", reasonMinPodCountKey: test.minPodCount},
+			}}
+			for i := range test.runningPods + test.completedPods {
+				phase := corev1.PodRunning
+				if i >= test.runningPods {
+					phase = corev1.PodSucceeded
+				}
+				objects = append(objects, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"},
+					Spec:       corev1.PodSpec{NodeName: nodeName},
+					Status:     corev1.PodStatus{Phase: phase},
+				})
+			}
+			objects = append(objects, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-node-pod", Namespace: "default"},
+				Spec:       corev1.PodSpec{NodeName: "other-node"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+			fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(objects...).
+				WithIndex(&corev1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+					return []string{obj.(*corev1.Pod).Spec.NodeName}
+				}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := list.(*corev1.PodList); ok && test.listError {
+							return errors.New("connection refused")
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).Build()
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, nodeName, test.user, nil))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(response.Result.Code).Should(Equal(test.code))
+			}
+		})
+	}
+}
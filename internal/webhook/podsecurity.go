@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// EnforcementMode is the way the denials of the operations of a user are enforced.
+type EnforcementMode string
+
+const (
+	// EnforceMode denies the operations.
+	EnforceMode EnforcementMode = "enforce"
+	// WarnMode allows the operations which would be denied, returning the denial message as an admission warning.
+	WarnMode EnforcementMode = "warn"
+	// AuditMode allows the operations which would be denied, only logging the denials.
+	AuditMode EnforcementMode = "audit"
+
+	podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// enforcementMode returns the enforcement mode of the operations of the user. When the policy is in pod security
+// compatibility mode, the mode of a service account follows the Pod Security Admission mode labels of its namespace:
+// the enforce label wins over the warn label, which wins over the audit label. The operations are enforced otherwise.
+func (n *NodeValidator) enforcementMode(ctx context.Context, user string, policy Policy) (EnforcementMode, error) {
+	namespaceAndName, isServiceAccount := strings.CutPrefix(user, policy.serviceAccountPrefix())
+	if !policy.PodSecurityCompatMode || !isServiceAccount {
+		return EnforceMode, nil
+	}
+
+	namespaceName, _, _ := strings.Cut(namespaceAndName, ":")
+	namespace := corev1.Namespace{}
+	if err := n.Client.Get(ctx, client.ObjectKey{Name: namespaceName}, &namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return EnforceMode, nil
+		}
+		return "", fmt.Errorf("failed to fetch namespace %q: %w", namespaceName, err)
+	}
+	for _, mode := range []EnforcementMode{EnforceMode, WarnMode, AuditMode} {
+		if _, ok := namespace.Labels[podSecurityLabelPrefix+string(mode)]; ok {
+			return mode, nil
+		}
+	}
+	return EnforceMode, nil
+}
+
+// validateWithEnforcementMode validates the operation in the enforcement mode of the user. In warn mode,
+// the policy is validated as a warn only policy. In audit mode, the denials are logged and the operation is allowed.
+func (n *NodeValidator) validateWithEnforcementMode(ctx context.Context, operation Operation, node *corev1.Node, user string, uid string, groups []string, policy Policy, log logr.Logger, isReasonRequired bool, dryRun bool) admission.Response {
+	mode, err := n.enforcementMode(ctx, user, policy)
+	if err != nil {
+		log.Error(err, "Failed to resolve the enforcement mode", "User", user)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve the enforcement mode of %q: %w", user, err))
+	}
+	if mode == WarnMode {
+		policy.WarnOnly = true
+	}
+
+	response := n.validateOperation(ctx, operation, node, user, uid, groups, policy, log, isReasonRequired, dryRun)
+	if mode == AuditMode && isDenied(response) {
+		log.Info("Denial allowed in audit mode", "Operation", operation, "User", user, "Denial", decisionMessage(response))
+		return admission.Allowed("Operation approved in audit mode")
+	}
+	return response
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestPodSecurityCompatMode(t *testing.T) {
+	const ciServiceAccount = serviceAccountUser + "ci:deployer"
+
+	tests := []struct {
+		name         string
+		compatMode   string
+		labels       map[string]string
+		noNamespace  bool
+		user         string
+		allowed      bool
+		warningCount int
+	}{
+		{name: "Enforce", compatMode: "true", labels: map[string]string{podSecurityLabelPrefix + "enforce": "restricted"}, user: ciServiceAccount, allowed: false},
+		{name: "Warn", compatMode: "true", labels: map[string]string{podSecurityLabelPrefix + "warn": "restricted"}, user: ciServiceAccount, allowed: true, warningCount: 1},
+		{name: "Audit", compatMode: "true", labels: map[string]string{podSecurityLabelPrefix + "audit": "restricted"}, user: ciServiceAccount, allowed: true},
+		{name: "EnforceWinsOverAudit", compatMode: "true", labels: map[string]string{podSecurityLabelPrefix + "enforce": "baseline", podSecurityLabelPrefix + "audit": "restricted"}, user: ciServiceAccount, allowed: false},
+		{name: "NoLabels", compatMode: "true", user: ciServiceAccount, allowed: false},
+		{name: "MissingNamespace", compatMode: "true", noNamespace: true, user: ciServiceAccount, allowed: false},
+		{name: "RegularUser", compatMode: "true", user: regularUserExample, allowed: false},
+		{name: "CompatModeDisabled", compatMode: "false", labels: map[string]string{podSecurityLabelPrefix + "audit": "restricted"}, user: ciServiceAccount, allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data: map[string]string{
+					allowedReasonsKey:      "Testing",
+					trustedSANamespacesKey: "automation",
+					podSecurityCompatKey:   test.compatMode,
+				},
+			})).Should(Succeed())
+			if !test.noNamespace {
+				g.Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ci", Labels: test.labels}})).Should(Succeed())
+			}
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, nil))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			g.Expect(response.Warnings).Should(HaveLen(test.warningCount))
+		})
+	}
+}
@@ -0,0 +1,621 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	policiesCMName         = "node-operation-validator-policies"
+	selectorsKey           = "selectors"
+	allowedReasonsKey      = "allowedReasons"
+	reasonRegexPatternKey  = "reasonRegexPattern"
+	forbiddenUsersKey      = "forbiddenUsers"
+	warnOnlyKey            = "warnOnly"
+	denialGracePeriodKey   = "denialGracePeriodSeconds"
+	maxCordonedPerZoneKey  = "maxCordonedNodesPerZone"
+	zoneLabelKey           = "zoneLabel"
+	reasonMinLengthKey     = "reasonMinLength"
+	reasonMaxLengthKey     = "reasonMaxLength"
+	reasonFormatKey        = "sanitizeAndValidateReasonFormat"
+	monitoredTaintsKey     = "monitoredTaints"
+	maintenanceWindowsKey  = "maintenanceWindows"
+	operationAllowlistKey  = "operationAllowlist"
+	reasonAttestationKey   = "requireReasonAttestation"
+	emergencyBypassKey     = "emergencyBypassSecret"
+	allowFreetextKey       = "allowFreetextReason"
+	defaultReasonSuffix    = ".defaultReason"
+	ticketURLKey           = "ticketValidationURL"
+	ticketStatusesKey      = "ticketRequiredStatuses"
+	ticketTokenSecretKey   = "ticketAPITokenSecretRef"
+	rateLimitMaxOpsKey     = "rateLimit.maxOps"
+	rateLimitWindowKey     = "rateLimit.windowSeconds"
+	drainReasonsKey        = "drain.allowedReasons"
+	drainPatternKey        = "drain.reasonRegexPattern"
+	reasonCategoriesKey    = "allowedReasonCategories"
+	latencyBucketsKey      = "metricsLatencyBuckets"
+	allowedPrioritiesKey   = "allowedPriorities"
+	reasonHistoryLimitKey  = "reasonHistoryLimit"
+	responseVerbosityKey   = "responseVerbosity"
+	riskWeightsKey         = "riskWeights"
+	riskWindowKey          = "riskWindowSeconds"
+	maxRiskScoreKey        = "maxRiskScorePerWindow"
+	bypassNodesKey         = "bypassNodes"
+	rateLimitByUIDKey      = "rateLimitByUID"
+	warningOperationsKey   = "warningOperations"
+	trustedSANamespacesKey = "trustedServiceAccountNamespaces"
+	saPrefixKey            = "serviceAccountPrefix"
+	reasonMinPodCountKey   = "requireReasonMinPodCount"
+	podSecurityCompatKey   = "podSecurityCompatMode"
+	forbiddenReasonsKey    = "forbiddenReasonPatterns"
+	denialTemplatesKey     = "denialMessageTemplates"
+	reasonOwnershipKey     = "requireReasonOwnership"
+	reasonSuggestionKey    = "reasonSuggestionMaxDistance"
+	cordonCooloffKey       = "minCordonCooloffSeconds"
+	nodeVelocityLimitKey   = "nodeOperationVelocityLimit"
+	nodeVelocityWindowKey  = "nodeOperationVelocityWindowSeconds"
+	failurePolicyKey       = "failurePolicy"
+	simulateDrainKey       = "simulateDrainOnDelete"
+	denyDrainSimulationKey = "denyIfDrainSimulationFails"
+	externalAPIHeadersKey  = "externalAPIHeaders"
+	bulkCordonThresholdKey = "bulkCordonThreshold"
+	bulkCordonWindowKey    = "bulkCordonWindowSeconds"
+	checkCapacityKey       = "checkResourceQuotaOnDelete"
+	minCapacityPercentKey  = "minRemainingCapacityPercent"
+	customCACertKey        = "customCACertSecretRef"
+	validateStatusKey      = "validateStatusSubresource"
+	auditWebhookURLKey     = "auditWebhookURL"
+	auditBatchSizeKey      = "auditWebhookBatchSize"
+	auditFlushIntervalKey  = "auditWebhookFlushIntervalSeconds"
+	auditSecretKey         = "auditWebhookSecretRef"
+	reasonAutoDeleteKey    = "reasonAnnotationAutoDeleteAfterSeconds"
+)
+
+// Policy holds the validation rules that apply to a node.
+type Policy struct {
+	AllowedReasons     []string
+	ReasonRegexPattern string
+	ForbiddenUsers     []string
+	// ForbiddenGroups holds the groups whose users are forbidden.
+	ForbiddenGroups []string
+	// WarnOnly allows denied operations, returning the denial message as an admission warning.
+	WarnOnly bool
+	// DenialGracePeriods holds, per operation, the period in which a denied operation can be re-submitted and approved.
+	DenialGracePeriods map[Operation]time.Duration
+	// MaxCordonedNodesPerZone is the maximum number of nodes which can be cordoned simultaneously in a zone.
+	// Zero means there is no limit.
+	MaxCordonedNodesPerZone int
+	// ZoneLabel is the node label holding the zone of the node.
+	ZoneLabel string
+	// ReasonMinLength and ReasonMaxLength bound the length of the reason. Zero means the bound isn't enforced.
+	ReasonMinLength int
+	ReasonMaxLength int
+	// ValidateReasonFormat denies reasons containing copy-paste artifacts.
+	ValidateReasonFormat bool
+	// MonitoredTaints holds the keys of the taints whose addition or removal is validated.
+	MonitoredTaints []string
+	// MaintenanceWindows restrict the operations requiring a reason to the time within the windows.
+	MaintenanceWindows []MaintenanceWindow
+	// OperationAllowlist restricts the operations of users and groups. Users without an entry may perform any operation.
+	OperationAllowlist map[string][]Operation
+	// RequireReasonAttestation requires the reason to be attested by a service account allowed to update nodes.
+	RequireReasonAttestation bool
+	// RequireReasonOwnership requires the reason author annotation to be the requesting user. Service accounts are exempt.
+	RequireReasonOwnership bool
+	// EmergencyBypassSecret references the Secret holding the HMAC key of the emergency bypass tokens,
+	// as <namespace>/<name> or <name>. Emergency bypass is disabled if it is empty.
+	EmergencyBypassSecret string
+	// AllowFreetextReason allows any non-empty reason.
+	AllowFreetextReason bool
+	// DefaultReasons holds, per operation, the reason used when the reason annotation is absent and freetext is allowed.
+	DefaultReasons map[Operation]string
+	// TicketValidationURL is the base URL of the Jira instance in which the tickets referenced by the reasons must exist.
+	// Ticket validation is disabled if it is empty.
+	TicketValidationURL string
+	// TicketRequiredStatuses holds the statuses one of which the ticket must be in. Any status is allowed if it is empty.
+	TicketRequiredStatuses []string
+	// TicketAPITokenSecretRef references the Secret holding the token of the Jira API, as <namespace>/<name> or <name>.
+	TicketAPITokenSecretRef string
+	// CustomCACertSecretRef references the Secret holding in its "ca.crt" key the PEM-encoded CA certificates trusted
+	// in addition to the system ones for the requests to external APIs, as <namespace>/<name> or <name>.
+	CustomCACertSecretRef string
+	// AuditWebhookURL is the URL the decisions are posted to as EventLists, in batches of AuditWebhookBatchSize events
+	// sent at least every AuditWebhookFlushInterval, which defaults to 10 seconds. The decisions are sent one by one
+	// if the batch size is zero. The decisions aren't forwarded if the URL is empty.
+	AuditWebhookURL           string
+	AuditWebhookBatchSize     int
+	AuditWebhookFlushInterval time.Duration
+	// AuditWebhookSecretRef references the Secret holding in its "hmacKey" key the key signing the payloads posted to
+	// the audit webhook, as <namespace>/<name> or <name>. The payloads aren't signed if it is empty.
+	AuditWebhookSecretRef string
+	// ExternalAPIHeaders holds, by name, the headers added to the requests to external APIs, such as OPA and Jira.
+	ExternalAPIHeaders map[string]ExternalAPIHeader
+	// RateLimitMaxOps is the maximum number of operations a user can perform within RateLimitWindow.
+	// Zero means there is no limit.
+	RateLimitMaxOps int
+	RateLimitWindow time.Duration
+	// MinCordonCooloff is the minimum time between uncordoning a node and cordoning it again. Zero means there is no minimum.
+	MinCordonCooloff time.Duration
+	// NodeOperationVelocityLimit is the maximum number of operations a node can undergo within NodeOperationVelocityWindow.
+	// Zero means there is no limit.
+	NodeOperationVelocityLimit  int
+	NodeOperationVelocityWindow time.Duration
+	// SimulateDrainOnDelete simulates the placement of the pods of a deleted node on the remaining nodes, warning about
+	// the pods which can't be placed. DenyIfDrainSimulationFails denies the deletion instead.
+	SimulateDrainOnDelete      bool
+	DenyIfDrainSimulationFails bool
+	// CheckResourceQuotaOnDelete denies the deletion of a node if the CPU or memory left unrequested on the remaining
+	// nodes would be below MinRemainingCapacityPercent of their allocatable resources. The minimum defaults to 20%.
+	CheckResourceQuotaOnDelete  bool
+	MinRemainingCapacityPercent int
+	// ReasonAnnotationAutoDeleteAfter is the time after which the reason annotation of a cordoned, drained or deleted
+	// node is removed, so that it can't be reused for a later operation. Zero means the annotation is kept. The removals
+	// are scheduled in memory by the replica approving the operation, so they are lost if it stops, and a later approval
+	// on another replica doesn't replace them: only the patch testing the value of the annotation keeps a newer reason.
+	ReasonAnnotationAutoDeleteAfter time.Duration
+	// ValidateStatusSubresource validates the updates of the status subresource of the nodes as StatusPatch operations.
+	ValidateStatusSubresource bool
+	// BulkCordonThreshold is the number of cordons a user can perform within BulkCordonWindow, beyond which they are
+	// a bulk cordon requiring the change request annotation. Zero means there is no bulk cordon detection.
+	BulkCordonThreshold int
+	BulkCordonWindow    time.Duration
+	// RateLimitByUID rate limits the operations by the UID of the user rather than by its username, when the UID is known.
+	RateLimitByUID bool
+	// DrainAllowedReasons and DrainReasonRegexPattern replace the reason rules when validating a drain.
+	// The reason rules of the policy are used if neither is set.
+	DrainAllowedReasons     []string
+	DrainReasonRegexPattern string
+	// AllowedReasonCategories holds the allowed values of the optional reason category annotation.
+	// Any category is allowed if it is empty.
+	AllowedReasonCategories []string
+	// LatencyBuckets configures the buckets of the admission latency histogram. The default buckets are used if it is empty.
+	LatencyBuckets HistogramBucketConfig
+	// AllowedPriorities holds the allowed operation priorities. All the known priorities are allowed if it is empty.
+	AllowedPriorities []OperationPriority
+	// ReasonHistoryLimit is the maximum number of entries of the reason history annotation. Defaults to 10.
+	ReasonHistoryLimit int
+	// ReasonSuggestionMaxDistance is the maximum edit distance between an invalid reason and the allowed reason
+	// suggested in its denial message. Defaults to 3.
+	ReasonSuggestionMaxDistance int
+	// ResponseVerbosity is the verbosity of the denial messages. Defaults to standard.
+	ResponseVerbosity ResponseVerbosity
+	// RiskWeights holds the weight of each operation in the risk score of a user. Operations without a weight aren't scored.
+	RiskWeights map[Operation]int
+	// MaxRiskScorePerWindow is the maximum risk score a user can reach within RiskWindow. Zero means there is no limit.
+	MaxRiskScorePerWindow int
+	RiskWindow            time.Duration
+	// BypassNodes holds the glob patterns of the names of the nodes on which any operation is allowed without validation.
+	BypassNodes []string
+	// WarningOperations holds the operations whose approvals are recorded as Warning events rather than Normal ones.
+	WarningOperations []Operation
+	// TrustedServiceAccountNamespaces holds the namespaces whose service accounts are allowed to perform any operation
+	// without validation. The service accounts of kube-system are always trusted, and those of all namespaces are
+	// trusted if it is empty or contains "*".
+	TrustedServiceAccountNamespaces []string
+	// ServiceAccountPrefix is the prefix of the usernames of the service accounts, for the distributions which don't
+	// use the standard system:serviceaccount: prefix. The standard prefix is used if it is empty.
+	ServiceAccountPrefix string
+	// RequireReasonMinPodCount is the minimum number of running pods of a node from which a reason is required.
+	// Zero means a reason is always required.
+	RequireReasonMinPodCount int
+	// PodSecurityCompatMode enforces the operations of the service accounts according to the Pod Security Admission
+	// mode labels of their namespaces: enforce denies, warn allows with a warning, and audit allows and logs.
+	PodSecurityCompatMode bool
+	// ForbiddenReasonPatterns holds regular expressions matching the reasons which are denied before any other
+	// validation of the reason, in addition to the default patterns matching template placeholders.
+	ForbiddenReasonPatterns []string
+	// DenialMessageTemplates holds, per denial code, the template replacing the default message of the denials.
+	DenialMessageTemplates map[string]*template.Template
+}
+
+// serviceAccountPrefix returns the prefix of the usernames of the service accounts, which defaults to the standard one.
+func (p Policy) serviceAccountPrefix() string {
+	if p.ServiceAccountPrefix == "" {
+		return serviceAccountUser
+	}
+	return p.ServiceAccountPrefix
+}
+
+// NodePolicySelector associates the nodes matching LabelSelector with the policy
+// stored in the ConfigMap referenced by ConfigMapRef.
+type NodePolicySelector struct {
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+	ConfigMapRef  string               `json:"configMapRef"`
+}
+
+// PolicyResolver resolves the policy that should be used to validate an operation on a node.
+type PolicyResolver interface {
+	Resolve(ctx context.Context, node *corev1.Node) (Policy, error)
+}
+
+// ConfigMapPolicyResolver resolves policies from ConfigMaps in a single namespace.
+// The selectors are read in order from the policies ConfigMap, and the first one matching
+// the node's labels is used. If nothing matches, the global ConfigMap is used.
+type ConfigMapPolicyResolver struct {
+	Client    client.Client
+	Namespace string
+}
+
+// Resolve returns the policy of the first selector matching the node, or the global policy if none matches.
+func (r *ConfigMapPolicyResolver) Resolve(ctx context.Context, node *corev1.Node) (Policy, error) {
+	selectors, err := r.getSelectors(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	for _, selector := range selectors {
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid label selector for policy %q: %w", selector.ConfigMapRef, err)
+		}
+		if labelSelector.Matches(labels.Set(node.Labels)) {
+			return r.getPolicy(ctx, selector.ConfigMapRef)
+		}
+	}
+
+	return r.getPolicy(ctx, cmName)
+}
+
+// getSelectors fetches the node policy selectors. A missing selectors ConfigMap means there are no selectors.
+func (r *ConfigMapPolicyResolver) getSelectors(ctx context.Context) ([]NodePolicySelector, error) {
+	configMapPolicies := corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: policiesCMName}, &configMapPolicies); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", r.Namespace, policiesCMName, err)
+	}
+
+	var selectors []NodePolicySelector
+	if err := yaml.Unmarshal([]byte(configMapPolicies.Data[selectorsKey]), &selectors); err != nil {
+		return nil, fmt.Errorf("failed to parse %q key of ConfigMap %s/%s: %w", selectorsKey, r.Namespace, policiesCMName, err)
+	}
+	return selectors, nil
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create
+
+// getPolicy fetches the policy stored in the given ConfigMap. A missing global ConfigMap means the default policy,
+// and the ConfigMap is created with it when the AUTO_CREATE_CONFIG environment variable is true.
+func (r *ConfigMapPolicyResolver) getPolicy(ctx context.Context, name string) (Policy, error) {
+	configMap := corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: name}, &configMap); err != nil {
+		if name == cmName && apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info("Warning: the ConfigMap doesn't exist, the default policy is used", "ConfigMap", r.Namespace+"/"+name)
+			r.createDefaultConfigMap(ctx)
+			return defaultPolicy(), nil
+		}
+		return Policy{}, fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", r.Namespace, name, err)
+	}
+	return policyFromConfigMap(&configMap)
+}
+
+// defaultPolicy returns the policy used when the global ConfigMap doesn't exist: any non-empty reason is allowed,
+// and there are no forbidden users other than the system admin user.
+func defaultPolicy() Policy {
+	return Policy{AllowFreetextReason: true}
+}
+
+// defaultConfigMapData returns the data of a ConfigMap holding the default policy.
+func defaultConfigMapData() map[string]string {
+	return map[string]string{allowFreetextKey: "true"}
+}
+
+// createDefaultConfigMap creates the global ConfigMap with the default policy if the AUTO_CREATE_CONFIG
+// environment variable is true. A failure is logged, since the default policy applies anyway.
+func (r *ConfigMapPolicyResolver) createDefaultConfigMap(ctx context.Context) {
+	if autoCreate, err := strconv.ParseBool(os.Getenv(AutoCreateConfigEnv)); err != nil || !autoCreate {
+		return
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: r.Namespace},
+		Data:       defaultConfigMapData(),
+	}
+	if err := r.Client.Create(ctx, &configMap); err != nil && !apierrors.IsAlreadyExists(err) {
+		log.FromContext(ctx).Error(err, "Failed to create the ConfigMap with the default policy", "ConfigMap", r.Namespace+"/"+cmName)
+		return
+	}
+	log.FromContext(ctx).Info("Created the ConfigMap with the default policy", "ConfigMap", r.Namespace+"/"+cmName)
+}
+
+// policyFromConfigMap parses a policy out of the data of a ConfigMap.
+func policyFromConfigMap(configMap *corev1.ConfigMap) (Policy, error) {
+	allowedReasons, hasAllowedReasons := configMap.Data[allowedReasonsKey]
+	pattern, hasPattern := configMap.Data[reasonRegexPatternKey]
+	_, hasFreetext := configMap.Data[allowFreetextKey]
+	if !hasAllowedReasons && !hasPattern && !hasFreetext {
+		return Policy{}, fmt.Errorf("ConfigMap %s/%s does not contain '%s' key", configMap.Namespace, configMap.Name, allowedReasonsKey)
+	}
+
+	policy := Policy{
+		ReasonRegexPattern:      pattern,
+		TicketValidationURL:     configMap.Data[ticketURLKey],
+		TicketAPITokenSecretRef: configMap.Data[ticketTokenSecretKey],
+		CustomCACertSecretRef:   configMap.Data[customCACertKey],
+		AuditWebhookURL:         configMap.Data[auditWebhookURLKey],
+		AuditWebhookSecretRef:   configMap.Data[auditSecretKey],
+		DrainReasonRegexPattern: configMap.Data[drainPatternKey],
+	}
+	if priorities, ok := configMap.Data[allowedPrioritiesKey]; ok && priorities != "" {
+		for _, priority := range strings.Split(priorities, ",") {
+			policy.AllowedPriorities = append(policy.AllowedPriorities, OperationPriority(strings.TrimSpace(priority)))
+		}
+	}
+	if namespaces, ok := configMap.Data[trustedSANamespacesKey]; ok && namespaces != "" {
+		for _, namespace := range strings.Split(namespaces, ",") {
+			policy.TrustedServiceAccountNamespaces = append(policy.TrustedServiceAccountNamespaces, strings.TrimSpace(namespace))
+		}
+	}
+	policy.ServiceAccountPrefix = strings.TrimSpace(configMap.Data[saPrefixKey])
+	if operations, ok := configMap.Data[warningOperationsKey]; ok && operations != "" {
+		for _, operation := range strings.Split(operations, ",") {
+			policy.WarningOperations = append(policy.WarningOperations, Operation(strings.TrimSpace(operation)))
+		}
+	}
+	if categories, ok := configMap.Data[reasonCategoriesKey]; ok && categories != "" {
+		policy.AllowedReasonCategories = strings.Split(categories, ",")
+	}
+	if drainReasons, ok := configMap.Data[drainReasonsKey]; ok && drainReasons != "" {
+		policy.DrainAllowedReasons = strings.Split(drainReasons, ",")
+	}
+	if hasAllowedReasons {
+		policy.AllowedReasons = strings.Split(allowedReasons, ",")
+	}
+	if forbiddenUsers, ok := configMap.Data[forbiddenUsersKey]; ok && forbiddenUsers != "" {
+		policy.ForbiddenUsers = strings.Split(forbiddenUsers, ",")
+	}
+	if statuses, ok := configMap.Data[ticketStatusesKey]; ok && statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			policy.TicketRequiredStatuses = append(policy.TicketRequiredStatuses, strings.TrimSpace(status))
+		}
+	}
+	if monitoredTaints, ok := configMap.Data[monitoredTaintsKey]; ok && monitoredTaints != "" {
+		policy.MonitoredTaints = strings.Split(monitoredTaints, ",")
+	}
+	if bypassNodes, ok := configMap.Data[bypassNodesKey]; ok {
+		patterns, err := parseBypassNodes(bypassNodes)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", bypassNodesKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.BypassNodes = patterns
+	}
+	if forbiddenReasons, ok := configMap.Data[forbiddenReasonsKey]; ok {
+		patterns, err := parseForbiddenReasonPatterns(forbiddenReasons)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", forbiddenReasonsKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.ForbiddenReasonPatterns = patterns
+	}
+	if denialTemplates, ok := configMap.Data[denialTemplatesKey]; ok {
+		templates, err := parseDenialMessageTemplates(denialTemplates)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", denialTemplatesKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.DenialMessageTemplates = templates
+	}
+	if maintenanceWindows, ok := configMap.Data[maintenanceWindowsKey]; ok {
+		windows, err := parseMaintenanceWindows(maintenanceWindows)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", maintenanceWindowsKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.MaintenanceWindows = windows
+	}
+	if operationAllowlist, ok := configMap.Data[operationAllowlistKey]; ok {
+		allowlist, err := parseOperationAllowlist(operationAllowlist)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", operationAllowlistKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.OperationAllowlist = allowlist
+	}
+	var err error
+	if policy.WarnOnly, err = parseBool(configMap, warnOnlyKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ValidateReasonFormat, err = parseBool(configMap, reasonFormatKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.RequireReasonAttestation, err = parseBool(configMap, reasonAttestationKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.RequireReasonOwnership, err = parseBool(configMap, reasonOwnershipKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.AllowFreetextReason, err = parseBool(configMap, allowFreetextKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.RateLimitByUID, err = parseBool(configMap, rateLimitByUIDKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.SimulateDrainOnDelete, err = parseBool(configMap, simulateDrainKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.DenyIfDrainSimulationFails, err = parseBool(configMap, denyDrainSimulationKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.CheckResourceQuotaOnDelete, err = parseBool(configMap, checkCapacityKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ValidateStatusSubresource, err = parseBool(configMap, validateStatusKey); err != nil {
+		return Policy{}, err
+	}
+	policy.MinRemainingCapacityPercent = defaultMinRemainingCapacityPercent
+	if value, ok := configMap.Data[minCapacityPercentKey]; ok {
+		if policy.MinRemainingCapacityPercent, err = parseNonNegativeInt(configMap, minCapacityPercentKey); err != nil || policy.MinRemainingCapacityPercent > 100 {
+			return Policy{}, fmt.Errorf("invalid %q value %q in ConfigMap %s/%s", minCapacityPercentKey, value, configMap.Namespace, configMap.Name)
+		}
+	}
+	if policy.PodSecurityCompatMode, err = parseBool(configMap, podSecurityCompatKey); err != nil {
+		return Policy{}, err
+	}
+	for key, value := range configMap.Data {
+		if operation, ok := strings.CutSuffix(key, defaultReasonSuffix); ok {
+			if policy.DefaultReasons == nil {
+				policy.DefaultReasons = make(map[Operation]string)
+			}
+			policy.DefaultReasons[Operation(operation)] = value
+		}
+	}
+	if policy.ResponseVerbosity, err = parseResponseVerbosity(configMap.Data[responseVerbosityKey]); err != nil {
+		return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", responseVerbosityKey, configMap.Namespace, configMap.Name, err)
+	}
+	if latencyBuckets, ok := configMap.Data[latencyBucketsKey]; ok {
+		buckets, err := parseHistogramBucketConfig(latencyBuckets)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", latencyBucketsKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.LatencyBuckets = buckets
+	}
+	if gracePeriods, ok := configMap.Data[denialGracePeriodKey]; ok {
+		denialGracePeriods, err := parseDenialGracePeriods(gracePeriods)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", denialGracePeriodKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.DenialGracePeriods = denialGracePeriods
+	}
+	if policy.MaxCordonedNodesPerZone, err = parseNonNegativeInt(configMap, maxCordonedPerZoneKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ReasonMinLength, err = parseNonNegativeInt(configMap, reasonMinLengthKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ReasonMaxLength, err = parseNonNegativeInt(configMap, reasonMaxLengthKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ReasonMaxLength > 0 && policy.ReasonMinLength > policy.ReasonMaxLength {
+		return Policy{}, fmt.Errorf("%q is greater than %q in ConfigMap %s/%s", reasonMinLengthKey, reasonMaxLengthKey, configMap.Namespace, configMap.Name)
+	}
+	if policy.RateLimitMaxOps, err = parseNonNegativeInt(configMap, rateLimitMaxOpsKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.RequireReasonMinPodCount, err = parseNonNegativeInt(configMap, reasonMinPodCountKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ReasonHistoryLimit, err = parseNonNegativeInt(configMap, reasonHistoryLimitKey); err != nil {
+		return Policy{}, err
+	}
+	if policy.ReasonSuggestionMaxDistance, err = parseNonNegativeInt(configMap, reasonSuggestionKey); err != nil {
+		return Policy{}, err
+	}
+	rateLimitWindowSeconds, err := parseNonNegativeInt(configMap, rateLimitWindowKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.RateLimitWindow = time.Duration(rateLimitWindowSeconds) * time.Second
+	cordonCooloffSeconds, err := parseNonNegativeInt(configMap, cordonCooloffKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.MinCordonCooloff = time.Duration(cordonCooloffSeconds) * time.Second
+	if policy.NodeOperationVelocityLimit, err = parseNonNegativeInt(configMap, nodeVelocityLimitKey); err != nil {
+		return Policy{}, err
+	}
+	nodeVelocityWindowSeconds, err := parseNonNegativeInt(configMap, nodeVelocityWindowKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.NodeOperationVelocityWindow = time.Duration(nodeVelocityWindowSeconds) * time.Second
+	if policy.BulkCordonThreshold, err = parseNonNegativeInt(configMap, bulkCordonThresholdKey); err != nil {
+		return Policy{}, err
+	}
+	bulkCordonWindowSeconds, err := parseNonNegativeInt(configMap, bulkCordonWindowKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.BulkCordonWindow = time.Duration(bulkCordonWindowSeconds) * time.Second
+	if policy.AuditWebhookBatchSize, err = parseNonNegativeInt(configMap, auditBatchSizeKey); err != nil {
+		return Policy{}, err
+	}
+	auditFlushIntervalSeconds, err := parseNonNegativeInt(configMap, auditFlushIntervalKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.AuditWebhookFlushInterval = time.Duration(auditFlushIntervalSeconds) * time.Second
+	reasonAutoDeleteSeconds, err := parseNonNegativeInt(configMap, reasonAutoDeleteKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.ReasonAnnotationAutoDeleteAfter = time.Duration(reasonAutoDeleteSeconds) * time.Second
+	if headers, ok := configMap.Data[externalAPIHeadersKey]; ok && headers != "" {
+		if policy.ExternalAPIHeaders, err = parseExternalAPIHeaders(headers); err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", externalAPIHeadersKey, configMap.Namespace, configMap.Name, err)
+		}
+	}
+	if riskWeights, ok := configMap.Data[riskWeightsKey]; ok {
+		weights, err := parseRiskWeights(riskWeights)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid %q value in ConfigMap %s/%s: %w", riskWeightsKey, configMap.Namespace, configMap.Name, err)
+		}
+		policy.RiskWeights = weights
+	}
+	if policy.MaxRiskScorePerWindow, err = parseNonNegativeInt(configMap, maxRiskScoreKey); err != nil {
+		return Policy{}, err
+	}
+	riskWindowSeconds, err := parseNonNegativeInt(configMap, riskWindowKey)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.RiskWindow = time.Duration(riskWindowSeconds) * time.Second
+	policy.ZoneLabel = configMap.Data[zoneLabelKey]
+	policy.EmergencyBypassSecret = configMap.Data[emergencyBypassKey]
+	return policy, nil
+}
+
+// parseBool parses the boolean value of the given ConfigMap key. A missing key is parsed as false.
+func parseBool(configMap *corev1.ConfigMap, key string) (bool, error) {
+	value, ok := configMap.Data[key]
+	if !ok {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %q value %q in ConfigMap %s/%s", key, value, configMap.Namespace, configMap.Name)
+	}
+	return result, nil
+}
+
+// parseNonNegativeInt parses the non-negative integer value of the given ConfigMap key. A missing key is parsed as zero.
+func parseNonNegativeInt(configMap *corev1.ConfigMap, key string) (int, error) {
+	value, ok := configMap.Data[key]
+	if !ok {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid %q value %q in ConfigMap %s/%s", key, value, configMap.Namespace, configMap.Name)
+	}
+	return number, nil
+}
+
+// parseDenialGracePeriods parses comma separated operation=seconds pairs, e.g. "cordon=60,delete=30".
+func parseDenialGracePeriods(value string) (map[Operation]time.Duration, error) {
+	gracePeriods := make(map[Operation]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		operation, seconds, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("expected operation=seconds, got %q", pair)
+		}
+		gracePeriod, err := strconv.Atoi(strings.TrimSpace(seconds))
+		if err != nil || gracePeriod < 0 {
+			return nil, fmt.Errorf("invalid grace period %q for operation %q", seconds, operation)
+		}
+		gracePeriods[Operation(strings.TrimSpace(operation))] = time.Duration(gracePeriod) * time.Second
+	}
+	return gracePeriods, nil
+}
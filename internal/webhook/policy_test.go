@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	masterRoleLabel = "node-role.kubernetes.io/master"
+	workerRoleLabel = "node-role.kubernetes.io/worker"
+)
+
+// fakePolicyResolver returns the master policy for master nodes and the default policy otherwise.
+type fakePolicyResolver struct {
+	master        Policy
+	defaultPolicy Policy
+}
+
+func (f *fakePolicyResolver) Resolve(_ context.Context, node *corev1.Node) (Policy, error) {
+	if _, ok := node.Labels[masterRoleLabel]; ok {
+		return f.master, nil
+	}
+	return f.defaultPolicy, nil
+}
+
+func TestPolicySelection(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	nv := NodeValidator{
+		Decoder: admission.NewDecoder(scheme.Scheme),
+		PolicyResolver: &fakePolicyResolver{
+			master:        Policy{AllowedReasons: []string{"Testing"}, ForbiddenUsers: []string{regularUserExample}},
+			defaultPolicy: Policy{AllowedReasons: []string{"Testing"}},
+		},
+	}
+	annotations := map[string]string{reasonAnnotation: "Testing"}
+
+	t.Run("MasterNodeDeleteBlocked", func(t *testing.T) {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{masterRoleLabel: ""}, Annotations: annotations}}
+		nodeObj, err := json.Marshal(node)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		response := nv.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Name: node.Name,
+			Operation: admissionv1.Delete,
+			UserInfo:  v1.UserInfo{Username: regularUserExample},
+			OldObject: runtime.RawExtension{Raw: nodeObj}}})
+		g.Expect(response.Allowed).Should(BeFalse())
+	})
+
+	t.Run("WorkerNodeCordonAllowed", func(t *testing.T) {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{workerRoleLabel: ""}, Annotations: annotations}}
+		cordonedNode := *node.DeepCopy()
+		cordonedNode.Spec.Unschedulable = true
+
+		response := nv.Handle(ctx, newUpdateRequest(g, regularUserExample, node, cordonedNode))
+		g.Expect(response.Allowed).Should(BeTrue())
+	})
+}
+
+func TestConfigMapPolicyResolver(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+
+	configMaps := []*corev1.ConfigMap{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+			Data:       map[string]string{allowedReasonsKey: "Testing"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "master-policy", Namespace: cmNamespace},
+			Data:       map[string]string{allowedReasonsKey: "Maintenance", forbiddenUsersKey: regularUserExample},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: policiesCMName, Namespace: cmNamespace},
+			Data: map[string]string{selectorsKey: `
+- labelSelector:
+    matchExpressions:
+    - key: node-role.kubernetes.io/master
+      operator: Exists
+  configMapRef: master-policy
+`},
+		},
+	}
+	for _, configMap := range configMaps {
+		g.Expect(fakeClient.Create(ctx, configMap)).Should(Succeed())
+	}
+	resolver := ConfigMapPolicyResolver{Client: fakeClient, Namespace: cmNamespace}
+
+	t.Run("MatchingSelector", func(t *testing.T) {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{masterRoleLabel: ""}}}
+		policy, err := resolver.Resolve(ctx, &node)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Maintenance"}))
+		g.Expect(policy.ForbiddenUsers).Should(Equal([]string{regularUserExample}))
+	})
+
+	t.Run("NoMatchFallback", func(t *testing.T) {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker", Labels: map[string]string{workerRoleLabel: ""}}}
+		policy, err := resolver.Resolve(ctx, &node)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(policy.AllowedReasons).Should(Equal([]string{"Testing"}))
+		g.Expect(policy.ForbiddenUsers).Should(BeEmpty())
+	})
+}
+
+func TestMissingConfigMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoCreate string
+		created    bool
+	}{
+		{name: "DefaultPolicy", autoCreate: "", created: false},
+		{name: "AutoCreateDisabled", autoCreate: "false", created: false},
+		{name: "AutoCreate", autoCreate: "true", created: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			t.Setenv(AutoCreateConfigEnv, test.autoCreate)
+			fakeClient := newFakeClient()
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: "any reason"}))
+			g.Expect(response.Allowed).Should(BeTrue())
+			response = nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, nil))
+			g.Expect(response.Allowed).Should(BeFalse())
+			g.Expect(response.Result.Code).Should(Equal(CodeMissingReason))
+
+			configMap := corev1.ConfigMap{}
+			err := fakeClient.Get(ctx, client.ObjectKey{Namespace: cmNamespace, Name: cmName}, &configMap)
+			if !test.created {
+				g.Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(configMap.Data).Should(Equal(defaultConfigMapData()))
+
+			configMap.Data[allowFreetextKey] = "false"
+			configMap.Data[allowedReasonsKey] = "Testing"
+			g.Expect(fakeClient.Update(ctx, &configMap)).Should(Succeed())
+			response = nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: "any reason"}))
+			g.Expect(response.Allowed).Should(BeFalse())
+		})
+	}
+}
+
+func TestMissingSelectedConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: policiesCMName, Namespace: cmNamespace},
+		Data:       map[string]string{selectorsKey: "- labelSelector: {}\n  configMapRef: missing-policy\n"},
+	})).Should(Succeed())
+	resolver := ConfigMapPolicyResolver{Client: fakeClient, Namespace: cmNamespace}
+
+	_, err := resolver.Resolve(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}})
+	g.Expect(err).Should(HaveOccurred())
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const operationPriorityAnnotation = "node.dana.io/operation-priority"
+
+// OperationPriority is the priority of an operation, used to order operations in multi-operation scenarios.
+type OperationPriority string
+
+const (
+	PriorityEmergency OperationPriority = "emergency"
+	PriorityPlanned   OperationPriority = "planned"
+	PriorityRoutine   OperationPriority = "routine"
+)
+
+// priorityOrder lists the priorities from the highest to the lowest.
+var priorityOrder = []OperationPriority{PriorityEmergency, PriorityPlanned, PriorityRoutine}
+
+// Rank returns the position of the priority in the scheduling order, where lower ranks are scheduled first.
+// Unknown priorities are scheduled last.
+func (p OperationPriority) Rank() int {
+	if rank := slices.Index(priorityOrder, p); rank >= 0 {
+		return rank
+	}
+	return len(priorityOrder)
+}
+
+// getOperationPriority returns the priority of the operation on the node, which defaults to routine.
+func getOperationPriority(node *corev1.Node) OperationPriority {
+	if priority, ok := node.Annotations[operationPriorityAnnotation]; ok {
+		return OperationPriority(priority)
+	}
+	return PriorityRoutine
+}
+
+// validateOperationPriority denies an approved operation if its priority isn't one of the allowed priorities of
+// the policy, which default to all the known priorities. In warn only mode, the denial message is added to
+// the warnings of the given response.
+func validateOperationPriority(operation Operation, nodeName string, user string, priority OperationPriority, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	allowedPriorities := policy.AllowedPriorities
+	if len(allowedPriorities) == 0 {
+		allowedPriorities = priorityOrder
+	}
+	if !response.Allowed || slices.Contains(allowedPriorities, priority) {
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: InvalidPriorityCode,
+		Grounds: "invalid operation priority", Details: []any{"Priority", priority}})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      InvalidPriorityCode,
+		Operation: operation,
+		User:      user,
+		Message:   fmt.Sprintf("Invalid %q annotation %q. Allowed priorities: %v", operationPriorityAnnotation, priority, allowedPriorities),
+	})
+}
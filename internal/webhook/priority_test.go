@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestOperationPriority(t *testing.T) {
+	tests := []struct {
+		name              string
+		allowedPriorities string
+		priority          string
+		allowed           bool
+	}{
+		{name: "DefaultAllowedPriority", priority: "planned", allowed: true},
+		{name: "UnknownPriority", priority: "urgent", allowed: false},
+		{name: "AbsentDefaultsToRoutine", allowedPriorities: "routine", allowed: true},
+		{name: "AbsentRoutineNotAllowed", allowedPriorities: "emergency,planned", allowed: false},
+		{name: "NotInAllowedList", allowedPriorities: "routine, planned", priority: "emergency", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", allowedPrioritiesKey: test.allowedPriorities},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.priority != "" {
+				annotations[operationPriorityAnnotation] = test.priority
+			}
+
+			response := nv.Handle(ctx, newDeleteRequest(g, test.name, regularUserExample, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(InvalidPriorityCode))
+			}
+		})
+	}
+}
+
+func TestOperationPriorityRank(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(PriorityEmergency.Rank()).Should(BeNumerically("<", PriorityPlanned.Rank()))
+	g.Expect(PriorityPlanned.Rank()).Should(BeNumerically("<", PriorityRoutine.Rank()))
+	g.Expect(OperationPriority("urgent").Rank()).Should(BeNumerically(">", PriorityRoutine.Rank()))
+}
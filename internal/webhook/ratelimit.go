@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const rateLimitKeyPrefix = "node-operation-validator:rate-limit:"
+
+// RateLimiterBackend stores the times of the recent operations of each user, so that the operations
+// can be rate limited over a sliding window.
+type RateLimiterBackend interface {
+	// Operations returns the times of the operations of the user within the window ending at now, in ascending order.
+	Operations(ctx context.Context, user string, window time.Duration, now time.Time) ([]time.Time, error)
+	// Record records an operation of the user at now, which is kept for the window.
+	Record(ctx context.Context, user string, window time.Duration, now time.Time) error
+	// Allow returns true if the user performed less than limit operations within the window ending at now, and if so
+	// records an operation at now when record is true. Otherwise, it returns the time after which to retry. The check
+	// and the record are atomic, so that concurrent operations of the user can't exceed the limit.
+	Allow(ctx context.Context, user string, limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool, error)
+}
+
+// checkRateLimit denies the operation if the user already performed the maximum number of operations of the policy
+// within its window, with a hint of when to retry. Otherwise, the operation is recorded unless in dry run mode.
+// The service accounts of the trusted namespaces aren't rate limited. In warn only mode, a rate limited operation is
+// allowed with a warning, without being recorded.
+func (n *NodeValidator) checkRateLimit(ctx context.Context, operation Operation, nodeName string, user string, uid string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool) {
+	if policy.RateLimitMaxOps <= 0 || policy.RateLimitWindow <= 0 || isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		return admission.Response{}, true
+	}
+
+	backend := n.RateLimiterBackend
+	if backend == nil {
+		backend = &n.rateLimits
+	}
+
+	retryAfter, ok, err := backend.Allow(ctx, rateLimitKey(user, uid, policy), policy.RateLimitMaxOps, policy.RateLimitWindow, n.now(), !dryRun)
+	if err != nil {
+		log.Error(err, "Failed to rate limit the operation of the user", "User", user)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to rate limit the operation of %q: %w", user, err)), false
+	}
+	if ok {
+		return admission.Response{}, true
+	}
+	retryAfterSeconds := int32(math.Ceil(retryAfter.Seconds()))
+	logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: RateLimitedCode, Grounds: "rate limit exceeded"})
+	response := denied(policy, DenialDetail{
+		Code:      RateLimitedCode,
+		Operation: operation,
+		User:      user,
+		Message: fmt.Sprintf("%q user performed %d operations within %s. Please use a service account for automation, or retry after %d seconds",
+			user, policy.RateLimitMaxOps, policy.RateLimitWindow, retryAfterSeconds),
+	})
+	if !response.Allowed {
+		response.Result.Details = &metav1.StatusDetails{RetryAfterSeconds: retryAfterSeconds}
+	}
+	return response, false
+}
+
+// rateLimitKey returns the key under which the operations of the user are rate limited: the UID of the user
+// when the policy rate limits by UID and the UID is known, and the username otherwise. The keys are prefixed with
+// their kind, so that a username can't share the operations of a UID.
+func rateLimitKey(user string, uid string, policy Policy) string {
+	if policy.RateLimitByUID && uid != "" {
+		return "uid:" + uid
+	}
+	return "user:" + user
+}
+
+// memoryRateLimiterBackend keeps the recent operations in memory. It is only accurate when the webhook
+// runs with a single replica. Its zero value is ready to use.
+type memoryRateLimiterBackend struct {
+	mu         sync.Mutex
+	operations map[string]userOperations
+}
+
+// userOperations are the recent operations of a user, which are dropped once the window of the last one expires.
+type userOperations struct {
+	times   []time.Time
+	expires time.Time
+}
+
+// Operations returns the times of the operations of the user within the window ending at now, dropping the
+// operations of the user once they are all out of their window.
+func (m *memoryRateLimiterBackend) Operations(_ context.Context, user string, window time.Duration, now time.Time) ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	userOps, ok := m.operations[user]
+	if ok && now.After(userOps.expires) {
+		delete(m.operations, user)
+		return nil, nil
+	}
+	var operations []time.Time
+	for _, operation := range userOps.times {
+		if operation.After(now.Add(-window)) {
+			operations = append(operations, operation)
+		}
+	}
+	return operations, nil
+}
+
+// Record records an operation of the user at now, dropping the operations of the user which are out of the window,
+// and the operations of the other users which are all out of their window.
+func (m *memoryRateLimiterBackend) Record(_ context.Context, user string, window time.Duration, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.record(user, window, now)
+	return nil
+}
+
+// record records an operation of the user at now. The caller holds the lock of the backend.
+func (m *memoryRateLimiterBackend) record(user string, window time.Duration, now time.Time) {
+	if m.operations == nil {
+		m.operations = make(map[string]userOperations)
+	}
+	for key, userOps := range m.operations {
+		if now.After(userOps.expires) {
+			delete(m.operations, key)
+		}
+	}
+	operations := m.operations[user].times
+	for len(operations) > 0 && !operations[0].After(now.Add(-window)) {
+		operations = operations[1:]
+	}
+	m.operations[user] = userOperations{times: append(operations, now), expires: now.Add(window)}
+}
+
+// Allow checks and records an operation of the user under the lock of the backend.
+func (m *memoryRateLimiterBackend) Allow(_ context.Context, user string, limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var operations []time.Time
+	for _, operation := range m.operations[user].times {
+		if operation.After(now.Add(-window)) {
+			operations = append(operations, operation)
+		}
+	}
+	if len(operations) >= limit {
+		return operations[len(operations)-limit].Add(window).Sub(now), false, nil
+	}
+	if record {
+		m.record(user, window, now)
+	}
+	return 0, true, nil
+}
+
+// RedisCommands is the subset of the sorted set commands of a Redis client used by RedisRateLimiterBackend.
+// It is implemented by a thin adapter over the Redis client of choice.
+type RedisCommands interface {
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	ZRemRangeByScore(ctx context.Context, key string, min string, max string) error
+	ZRangeByScore(ctx context.Context, key string, min string, max string) ([]string, error)
+	PExpire(ctx context.Context, key string, ttl time.Duration) error
+	// Eval runs the Lua script atomically, returning its result as the Redis client does, e.g. []any{int64(1), int64(0)}.
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// redisAllowScript checks and records an operation atomically, returning {1, 0} if the operation is allowed, and
+// {0, nanoseconds after which to retry} otherwise. Its arguments are now and the window in nanoseconds, the limit,
+// whether to record the operation, and the member of the operation.
+const redisAllowScript = `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+if count >= limit then
+  local oldest = redis.call("ZRANGE", KEYS[1], count - limit, count - limit, "WITHSCORES")
+  return {0, tonumber(oldest[2]) + window - now}
+end
+if ARGV[4] == "1" then
+  redis.call("ZADD", KEYS[1], now, ARGV[5])
+  redis.call("PEXPIRE", KEYS[1], math.ceil(window / 1000000))
+end
+return {1, 0}
+`
+
+// RedisRateLimiterBackend shares the recent operations between the replicas of the webhook using a Redis
+// compatible server. The operations of each user are kept in a sorted set scored by their time in nanoseconds.
+type RedisRateLimiterBackend struct {
+	Client RedisCommands
+}
+
+// Operations returns the times of the operations of the user within the window ending at now.
+func (r *RedisRateLimiterBackend) Operations(ctx context.Context, user string, window time.Duration, now time.Time) ([]time.Time, error) {
+	members, err := r.Client.ZRangeByScore(ctx, rateLimitKeyPrefix+user, "("+strconv.FormatInt(now.Add(-window).UnixNano(), 10), "+inf")
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]time.Time, 0, len(members))
+	for _, member := range members {
+		nanoseconds, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation time %q: %w", member, err)
+		}
+		operations = append(operations, time.Unix(0, nanoseconds))
+	}
+	return operations, nil
+}
+
+// Record records an operation of the user at now, dropping the operations which are out of the window.
+func (r *RedisRateLimiterBackend) Record(ctx context.Context, user string, window time.Duration, now time.Time) error {
+	key := rateLimitKeyPrefix + user
+	if err := r.Client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10)); err != nil {
+		return err
+	}
+	nanoseconds := now.UnixNano()
+	if err := r.Client.ZAdd(ctx, key, float64(nanoseconds), strconv.FormatInt(nanoseconds, 10)); err != nil {
+		return err
+	}
+	return r.Client.PExpire(ctx, key, window)
+}
+
+// Allow checks and records an operation of the user with a single Lua script, so that the replicas of the webhook
+// can't exceed the limit by checking concurrently.
+func (r *RedisRateLimiterBackend) Allow(ctx context.Context, user string, limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool, error) {
+	recordArg := "0"
+	if record {
+		recordArg = "1"
+	}
+	nanoseconds := strconv.FormatInt(now.UnixNano(), 10)
+	result, err := r.Client.Eval(ctx, redisAllowScript, []string{rateLimitKeyPrefix + user},
+		nanoseconds, strconv.FormatInt(window.Nanoseconds(), 10), strconv.Itoa(limit), recordArg, nanoseconds)
+	if err != nil {
+		return 0, false, err
+	}
+
+	values, ok := result.([]any)
+	if !ok || len(values) != 2 {
+		return 0, false, fmt.Errorf("unexpected result %v of the rate limit script", result)
+	}
+	allowed, allowedOk := values[0].(int64)
+	retryAfter, retryAfterOk := values[1].(int64)
+	if !allowedOk || !retryAfterOk {
+		return 0, false, fmt.Errorf("unexpected result %v of the rate limit script", result)
+	}
+	return time.Duration(retryAfter), allowed == 1, nil
+}
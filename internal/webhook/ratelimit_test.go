@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestRateLimit(t *testing.T) {
+	const maxOps = 3
+
+	tests := []struct {
+		name     string
+		user     string
+		elapsed  time.Duration
+		warnOnly string
+		allowed  bool
+	}{
+		{name: "WithinWindow", user: regularUserExample, elapsed: 10 * time.Second, allowed: false},
+		{name: "WarnOnly", user: regularUserExample, elapsed: 10 * time.Second, warnOnly: "true", allowed: true},
+		{name: "AfterWindowReset", user: regularUserExample, elapsed: 31 * time.Second, allowed: true},
+		{name: "ServiceAccount", user: trustedServiceAccount, elapsed: 10 * time.Second, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing", rateLimitMaxOpsKey: "3", rateLimitWindowKey: "30"}
+			if test.warnOnly != "" {
+				data[warnOnlyKey] = test.warnOnly
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			clock := &fakeClock{now: time.Now()}
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: clock}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+
+			for range maxOps {
+				response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+				g.Expect(response.Allowed).Should(BeTrue())
+			}
+
+			clock.now = clock.now.Add(test.elapsed)
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(RateLimitedCode))
+				g.Expect(response.Result.Details.RetryAfterSeconds).Should(Equal(int32(20)))
+			}
+			if test.warnOnly == "true" {
+				g.Expect(response.Warnings).Should(ContainElement(ContainSubstring("retry after 20 seconds")))
+			}
+		})
+	}
+}
+
+func TestRateLimitByUID(t *testing.T) {
+	tests := []struct {
+		name           string
+		rateLimitByUID string
+		uids           []string
+		uid            string
+		allowed        bool
+	}{
+		{name: "SameSession", rateLimitByUID: "true", uids: []string{"session-1", "session-1"}, uid: "session-1", allowed: false},
+		{name: "NewSession", rateLimitByUID: "true", uids: []string{"session-1", "session-1"}, uid: "session-2", allowed: true},
+		{name: "EmptyUIDFallsBackToUsername", rateLimitByUID: "true", uids: []string{"", ""}, uid: "", allowed: false},
+		{name: "ByUsername", rateLimitByUID: "false", uids: []string{"session-1", "session-1"}, uid: "session-2", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", rateLimitMaxOpsKey: "2", rateLimitWindowKey: "30", rateLimitByUIDKey: test.rateLimitByUID},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: time.Now()}}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+
+			for _, uid := range test.uids {
+				request := newCordonRequest(g, test.name, regularUserExample, annotations)
+				request.UserInfo.UID = uid
+				g.Expect(nv.Handle(ctx, request).Allowed).Should(BeTrue())
+			}
+
+			request := newCordonRequest(g, test.name, regularUserExample, annotations)
+			request.UserInfo.UID = test.uid
+			g.Expect(nv.Handle(ctx, request).Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	g := NewWithT(t)
+	policy := Policy{RateLimitByUID: true}
+
+	// A username looking like a UID doesn't share the operations of the UID.
+	g.Expect(rateLimitKey("uid:session-1", "", policy)).ShouldNot(Equal(rateLimitKey(regularUserExample, "session-1", policy)))
+	g.Expect(rateLimitKey(regularUserExample, "session-1", Policy{})).Should(Equal("user:" + regularUserExample))
+}
+
+func TestMemoryRateLimiterBackendEviction(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	backend := memoryRateLimiterBackend{}
+	now := time.Now()
+
+	g.Expect(backend.Record(ctx, "user:alice", time.Minute, now)).Should(Succeed())
+	g.Expect(backend.Record(ctx, "user:bob", time.Hour, now)).Should(Succeed())
+
+	// The users whose operations are all out of their window are dropped.
+	now = now.Add(2 * time.Minute)
+	g.Expect(backend.Record(ctx, "user:carol", time.Minute, now)).Should(Succeed())
+	g.Expect(backend.operations).Should(HaveLen(2))
+	g.Expect(backend.operations).Should(HaveKey("user:bob"))
+
+	now = now.Add(2 * time.Minute)
+	operations, err := backend.Operations(ctx, "user:carol", time.Minute, now)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(operations).Should(BeEmpty())
+	g.Expect(backend.operations).ShouldNot(HaveKey("user:carol"))
+}
+
+func TestRateLimitConcurrentOperations(t *testing.T) {
+	const maxOps, extraOps = 5, 10
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", rateLimitMaxOpsKey: "5", rateLimitWindowKey: "30"},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: time.Now()}}
+	nv.RateLimiterBackend = slowRateLimiterBackend{RateLimiterBackend: &nv.rateLimits}
+
+	// A burst of concurrent operations of the user can't exceed the limit.
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := range maxOps + extraOps {
+		request := newCordonRequest(g, fmt.Sprintf("node-%d", i), regularUserExample, map[string]string{reasonAnnotation: "Testing"})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if nv.Handle(ctx, request).Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	g.Expect(allowed.Load()).Should(Equal(int32(maxOps)))
+}
+
+// slowRateLimiterBackend delays the responses of the backend, so that the concurrent operations overlap between
+// their check and their record.
+type slowRateLimiterBackend struct {
+	RateLimiterBackend
+}
+
+func (b slowRateLimiterBackend) Operations(ctx context.Context, user string, window time.Duration, now time.Time) ([]time.Time, error) {
+	defer time.Sleep(10 * time.Millisecond)
+	return b.RateLimiterBackend.Operations(ctx, user, window, now)
+}
+
+func (b slowRateLimiterBackend) Allow(ctx context.Context, user string, limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool, error) {
+	defer time.Sleep(10 * time.Millisecond)
+	return b.RateLimiterBackend.Allow(ctx, user, limit, window, now, record)
+}
+
+// fakeRedisCommands returns the given result from Eval, recording its keys and arguments.
+type fakeRedisCommands struct {
+	RedisCommands
+	result any
+	err    error
+	keys   []string
+	args   []any
+}
+
+func (f *fakeRedisCommands) Eval(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+	f.keys, f.args = keys, args
+	return f.result, f.err
+}
+
+func TestRedisRateLimiterBackendAllow(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	now := time.Unix(100, 0)
+
+	redis := &fakeRedisCommands{result: []any{int64(1), int64(0)}}
+	backend := RedisRateLimiterBackend{Client: redis}
+	_, ok, err := backend.Allow(ctx, "user:alice", 3, time.Minute, now, true)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ok).Should(BeTrue())
+	g.Expect(redis.keys).Should(Equal([]string{rateLimitKeyPrefix + "user:alice"}))
+	g.Expect(redis.args).Should(Equal([]any{"100000000000", "60000000000", "3", "1", "100000000000"}))
+
+	redis.result = []any{int64(0), int64(20 * time.Second)}
+	retryAfter, ok, err := backend.Allow(ctx, "user:alice", 3, time.Minute, now, false)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(ok).Should(BeFalse())
+	g.Expect(retryAfter).Should(Equal(20 * time.Second))
+
+	redis.result = "OK"
+	_, _, err = backend.Allow(ctx, "user:alice", 3, time.Minute, now, true)
+	g.Expect(err).Should(HaveOccurred())
+
+	redis.err = errors.New("connection refused")
+	_, _, err = backend.Allow(ctx, "user:alice", 3, time.Minute, now, true)
+	g.Expect(err).Should(HaveOccurred())
+}
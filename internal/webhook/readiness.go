@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConfigMapReadinessChecker checks that the ConfigMap of the webhook is reachable, since every admission request
+// fails if it can't be read. It is both a readiness check of the manager and a readiness handler of the webhook server.
+type ConfigMapReadinessChecker struct {
+	// Client should read from the API server rather than from a cache, so that the check reflects its reachability.
+	Client client.Reader
+}
+
+// Check fetches the ConfigMap of the webhook, and returns an error if it is unreadable. A missing ConfigMap
+// isn't an error, since the default policy is used then.
+func (c *ConfigMapReadinessChecker) Check(req *http.Request) error {
+	configMap := corev1.ConfigMap{}
+	if err := c.Client.Get(req.Context(), client.ObjectKey{Namespace: cmNamespace, Name: cmName}, &configMap); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", cmNamespace, cmName, err)
+	}
+	return nil
+}
+
+// ServeHTTP responds with 200 if the ConfigMap is reachable, and with 503 otherwise.
+func (c *ConfigMapReadinessChecker) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if err := c.Check(req); err != nil {
+		http.Error(resp, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprint(resp, "ok")
+}
+
+// LivenessHandler responds with 200 as long as the webhook HTTP server is serving requests.
+var LivenessHandler = http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+	fmt.Fprint(resp, "ok")
+})
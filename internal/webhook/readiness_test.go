@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestConfigMapReadinessChecker(t *testing.T) {
+	tests := []struct {
+		name         string
+		hasConfigMap bool
+		getError     bool
+		status       int
+	}{
+		{name: "ConfigMapExists", hasConfigMap: true, status: http.StatusOK},
+		{name: "ConfigMapMissing", hasConfigMap: false, status: http.StatusOK},
+		{name: "ConfigMapUnreadable", hasConfigMap: true, getError: true, status: http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			funcs := interceptor.Funcs{}
+			if test.getError {
+				funcs.Get = func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return errors.New("connection refused")
+				}
+			}
+			fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithInterceptorFuncs(funcs).Build()
+			if test.hasConfigMap {
+				g.Expect(fakeClient.Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+					Data:       map[string]string{allowedReasonsKey: "Testing"},
+				})).Should(Succeed())
+			}
+			server := httptest.NewServer(&ConfigMapReadinessChecker{Client: fakeClient})
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			defer resp.Body.Close()
+			g.Expect(resp.StatusCode).Should(Equal(test.status))
+		})
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(LivenessHandler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer resp.Body.Close()
+	g.Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+}
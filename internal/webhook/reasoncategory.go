@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	reasonCategoryAnnotation      = "node.dana.io/reason-category"
+	reasonCategoryAuditAnnotation = "reason-category"
+)
+
+// validateReasonCategory denies an approved operation if its reason category isn't one of the allowed
+// categories of the policy. Any category is allowed if the policy doesn't define categories.
+// In warn only mode, the denial message is added to the warnings of the given response.
+func validateReasonCategory(operation Operation, nodeName string, user string, category string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	if !response.Allowed || len(policy.AllowedReasonCategories) == 0 || ReasonIsAllowed(policy.AllowedReasonCategories, category) {
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: InvalidReasonCategoryCode,
+		Grounds: "invalid reason category", Details: []any{"Category", category}})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      InvalidReasonCategoryCode,
+		Operation: operation,
+		User:      user,
+		Message:   fmt.Sprintf("Invalid reason category %q. Allowed categories: %v", category, policy.AllowedReasonCategories),
+	})
+}
+
+// withReasonCategory adds the reason category to the audit annotations of the response.
+func withReasonCategory(response admission.Response, category string) admission.Response {
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = make(map[string]string)
+	}
+	response.AuditAnnotations[reasonCategoryAuditAnnotation] = category
+	return response
+}
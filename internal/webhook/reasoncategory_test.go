@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestReasonCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories string
+		category   string
+		allowed    bool
+	}{
+		{name: "AllowedCategory", categories: "hardware,software,network", category: "Hardware", allowed: true},
+		{name: "InvalidCategory", categories: "hardware,software,network", category: "cosmic-rays", allowed: false},
+		{name: "NoCategory", categories: "hardware,software,network", allowed: true},
+		{name: "CategoriesUnconfigured", category: "cosmic-rays", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", reasonCategoriesKey: test.categories},
+			})).Should(Succeed())
+			recorder := record.NewFakeRecorder(10)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Recorder: recorder}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+			if test.category != "" {
+				annotations[reasonCategoryAnnotation] = test.category
+			}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(InvalidReasonCategoryCode))
+			}
+			if test.category != "" {
+				g.Expect(response.AuditAnnotations).Should(HaveKeyWithValue(reasonCategoryAuditAnnotation, test.category))
+				g.Expect(recorder.Events).Should(Receive(ContainSubstring(test.category)))
+			} else {
+				g.Expect(response.AuditAnnotations).ShouldNot(HaveKey(reasonCategoryAuditAnnotation))
+			}
+		})
+	}
+}
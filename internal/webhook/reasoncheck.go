@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConfigMapName and ConfigMapNamespace identify the global ConfigMap holding the default policy,
+// for the clients validating reasons before performing operations.
+const (
+	ConfigMapName      = cmName
+	ConfigMapNamespace = cmNamespace
+)
+
+// ParsePolicy parses the policy of a ConfigMap, as the webhook does.
+func ParsePolicy(configMap *corev1.ConfigMap) (Policy, error) {
+	return policyFromConfigMap(configMap)
+}
+
+// CheckReason returns an error describing why the reason isn't valid for the operation according to the policy,
+// or nil if it is. It only checks the reason itself, so the webhook may still deny the operation, e.g. outside of
+// the maintenance windows or because of a node policy.
+func (p Policy) CheckReason(operation Operation, reason string) error {
+	if operation == Drain {
+		p = drainPolicy(p)
+	}
+	if _, message := validateReason(p, reason); message != "" {
+		return errors.New(message)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// reasonCleanupRetryDelay is the maximum time the removal of a reason annotation is postponed by while
+	// an operation on the node is being validated.
+	reasonCleanupRetryDelay = 5 * time.Second
+	// reasonCleanupTimeout is the timeout of the patch removing a reason annotation.
+	reasonCleanupTimeout = 10 * time.Second
+)
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=patch
+
+// scheduleReasonCleanup schedules the removal of the reason annotation of the node once the delay of the policy
+// elapsed, so that a stale reason can't be reused for a later operation. The annotation is only removed if it still
+// holds the reason of the approved operation, and the removal is postponed while an operation on the node is being
+// validated. A later approval on the node replaces the scheduled removal. A validator without a client, such as
+// the one of the self-test, doesn't remove the annotations.
+func (n *NodeValidator) scheduleReasonCleanup(node *corev1.Node, policy Policy) {
+	reason, ok := node.Annotations[ReasonAnnotationKey()]
+	if !ok || policy.ReasonAnnotationAutoDeleteAfter <= 0 || n.Client == nil {
+		return
+	}
+	nodeName := node.Name
+	n.reasonCleanups.schedule(nodeName, policy.ReasonAnnotationAutoDeleteAfter, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reasonCleanupTimeout)
+		defer cancel()
+		n.removeReasonAnnotation(ctx, nodeName, reason)
+	})
+}
+
+// removeReasonAnnotation removes the reason annotation of the node if it still holds the given reason. The patch
+// tests the value of the annotation, so that a reason set for a later operation is kept.
+func (n *NodeValidator) removeReasonAnnotation(ctx context.Context, nodeName string, reason string) {
+	logger := log.Log.WithName("Node Webhook").WithValues("node", nodeName)
+	path := "/metadata/annotations/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(ReasonAnnotationKey())
+	patch, err := json.Marshal([]map[string]string{
+		{"op": "test", "path": path, "value": reason},
+		{"op": "remove", "path": path},
+	})
+	if err != nil {
+		logger.Error(err, "Failed to build the patch removing the reason annotation")
+		return
+	}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+	switch err := n.Client.Patch(ctx, node, client.RawPatch(types.JSONPatchType, patch)); {
+	case err == nil:
+		logger.Info("Removed the reason annotation", "Reason", reason)
+	case apierrors.IsNotFound(err):
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		logger.V(1).Info("The reason annotation changed since the operation, it is kept", "Reason", reason)
+	default:
+		logger.Error(err, "Failed to remove the reason annotation", "Reason", reason)
+	}
+}
+
+// reasonCleanupTracker keeps the scheduled removals of the reason annotations, and the operations being validated,
+// by node in memory. Its zero value is ready to use.
+type reasonCleanupTracker struct {
+	mu    sync.Mutex
+	nodes map[string]*nodeReasonCleanup
+}
+
+// nodeReasonCleanup is the scheduled removal of the reason annotation of a node.
+type nodeReasonCleanup struct {
+	timer *time.Timer
+	// generation identifies the latest scheduled removal, so that a replaced one doesn't run.
+	generation int
+	// validating is the number of operations on the node being validated.
+	validating int
+}
+
+// entry returns the removal of the node, creating it if needed. The lock must be held.
+func (t *reasonCleanupTracker) entry(nodeName string) *nodeReasonCleanup {
+	if t.nodes == nil {
+		t.nodes = make(map[string]*nodeReasonCleanup)
+	}
+	cleanup, ok := t.nodes[nodeName]
+	if !ok {
+		cleanup = &nodeReasonCleanup{}
+		t.nodes[nodeName] = cleanup
+	}
+	return cleanup
+}
+
+// schedule runs the removal of the reason annotation of the node after the delay, replacing the removal
+// scheduled previously if any.
+func (t *reasonCleanupTracker) schedule(nodeName string, delay time.Duration, remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cleanup := t.entry(nodeName)
+	if cleanup.timer != nil {
+		cleanup.timer.Stop()
+	}
+	cleanup.generation++
+	generation := cleanup.generation
+	cleanup.timer = time.AfterFunc(delay, func() { t.run(nodeName, generation, min(delay, reasonCleanupRetryDelay), remove) })
+}
+
+// run runs the removal of the given generation unless it was replaced, postponing it by the retry delay while
+// an operation on the node is being validated.
+func (t *reasonCleanupTracker) run(nodeName string, generation int, retryDelay time.Duration, remove func()) {
+	t.mu.Lock()
+	cleanup, ok := t.nodes[nodeName]
+	if !ok || cleanup.generation != generation {
+		t.mu.Unlock()
+		return
+	}
+	if cleanup.validating > 0 {
+		cleanup.timer = time.AfterFunc(retryDelay, func() { t.run(nodeName, generation, retryDelay, remove) })
+		t.mu.Unlock()
+		return
+	}
+	delete(t.nodes, nodeName)
+	t.mu.Unlock()
+	remove()
+}
+
+// begin marks an operation on the node as being validated, until end is called.
+func (t *reasonCleanupTracker) begin(nodeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(nodeName).validating++
+}
+
+// end marks the validation of an operation on the node as done.
+func (t *reasonCleanupTracker) end(nodeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cleanup, ok := t.nodes[nodeName]
+	if !ok {
+		return
+	}
+	cleanup.validating--
+	if cleanup.validating <= 0 && cleanup.timer == nil {
+		delete(t.nodes, nodeName)
+	}
+}
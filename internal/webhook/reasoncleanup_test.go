@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestReasonCleanup(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	policy := Policy{AllowedReasons: []string{"Testing", "Upgrade"}, ReasonAnnotationAutoDeleteAfter: 50 * time.Millisecond}
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, PolicyResolver: staticPolicyResolver{policy: policy}}
+	reason := func(name string) func() string {
+		return func() string {
+			node := corev1.Node{}
+			g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: name}, &node)).Should(Succeed())
+			return node.Annotations[reasonAnnotation]
+		}
+	}
+
+	// The reason annotation of an approved cordon is removed after the delay.
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: map[string]string{reasonAnnotation: "Testing"}}}
+	g.Expect(fakeClient.Create(ctx, &node)).Should(Succeed())
+	cordonedNode := *node.DeepCopy()
+	cordonedNode.Spec.Unschedulable = true
+	g.Expect(nv.Handle(ctx, newUpdateRequest(g, regularUserExample, node, cordonedNode)).Allowed).Should(BeTrue())
+	g.Expect(reason("node-1")()).Should(Equal("Testing"))
+	g.Eventually(reason("node-1")).Should(BeEmpty())
+
+	// A reason set for a later operation is kept.
+	node = corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Annotations: map[string]string{reasonAnnotation: "Testing"}}}
+	g.Expect(fakeClient.Create(ctx, &node)).Should(Succeed())
+	nv.scheduleReasonCleanup(&node, policy)
+	node.Annotations[reasonAnnotation] = "Upgrade"
+	g.Expect(fakeClient.Update(ctx, &node)).Should(Succeed())
+	g.Consistently(reason("node-2"), 150*time.Millisecond).Should(Equal("Upgrade"))
+
+	// The reasons of the denied operations are kept.
+	node = corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-3", Annotations: map[string]string{reasonAnnotation: "for fun"}}}
+	g.Expect(fakeClient.Create(ctx, &node)).Should(Succeed())
+	cordonedNode = *node.DeepCopy()
+	cordonedNode.Spec.Unschedulable = true
+	g.Expect(nv.Handle(ctx, newUpdateRequest(g, regularUserExample, node, cordonedNode)).Allowed).Should(BeFalse())
+	g.Consistently(reason("node-3"), 150*time.Millisecond).Should(Equal("for fun"))
+
+	// The cordoned nodes whose reason annotation was removed aren't audit violations.
+	_, isViolation := auditNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{Unschedulable: true}}, policy)
+	g.Expect(isViolation).Should(BeFalse())
+
+	// A validator without a client doesn't schedule any removal.
+	nv = NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), PolicyResolver: staticPolicyResolver{policy: policy}}
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-4", regularUserExample, map[string]string{reasonAnnotation: "Testing"})).Allowed).Should(BeTrue())
+	nv.reasonCleanups.mu.Lock()
+	defer nv.reasonCleanups.mu.Unlock()
+	g.Expect(nv.reasonCleanups.nodes).Should(BeEmpty())
+}
+
+func TestReasonCleanupTracker(t *testing.T) {
+	g := NewWithT(t)
+	tracker := reasonCleanupTracker{}
+	var first, second atomic.Int32
+
+	// A later removal replaces the scheduled one.
+	tracker.schedule("node-1", 20*time.Millisecond, func() { first.Add(1) })
+	tracker.schedule("node-1", 20*time.Millisecond, func() { second.Add(1) })
+	g.Eventually(second.Load).Should(Equal(int32(1)))
+	g.Consistently(first.Load, 100*time.Millisecond).Should(BeZero())
+
+	// The removal is postponed while an operation on the node is being validated.
+	tracker.begin("node-2")
+	tracker.schedule("node-2", 20*time.Millisecond, func() { first.Add(1) })
+	g.Consistently(first.Load, 100*time.Millisecond).Should(BeZero())
+	tracker.end("node-2")
+	g.Eventually(first.Load).Should(Equal(int32(1)))
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	g.Expect(tracker.nodes).Should(BeEmpty())
+}
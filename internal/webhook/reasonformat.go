@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const yamlSpecialLeadingCharacters = "*{["
+
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)[^<>]*?(/?)>`)
+
+// reasonFormatIssues returns the copy-paste artifacts found in the reason, such as HTML tags,
+// markdown code blocks, YAML special leading characters and non-printable characters.
+func reasonFormatIssues(reason string) []string {
+	var issues []string
+
+	if hasUnmatchedHTMLTags(reason) {
+		issues = append(issues, "unmatched HTML tags")
+	}
+	if strings.Contains(reason, "```") {
+		issues = append(issues, "markdown code block")
+	}
+	if trimmed := strings.TrimSpace(reason); trimmed != "" && strings.ContainsRune(yamlSpecialLeadingCharacters, rune(trimmed[0])) {
+		issues = append(issues, "YAML special leading character "+trimmed[:1])
+	}
+	if strings.IndexFunc(reason, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		issues = append(issues, "non-printable characters")
+	}
+
+	return issues
+}
+
+// hasUnmatchedHTMLTags checks if the reason has an HTML tag which is opened and not closed, or the other way around.
+func hasUnmatchedHTMLTags(reason string) bool {
+	openTags := make(map[string]int)
+	for _, match := range htmlTagPattern.FindAllStringSubmatch(reason, -1) {
+		isClosing, tag, isSelfClosing := match[1] == "/", strings.ToLower(match[2]), match[3] == "/"
+		switch {
+		case isSelfClosing:
+			continue
+		case isClosing:
+			openTags[tag]--
+		default:
+			openTags[tag]++
+		}
+	}
+
+	for _, count := range openTags {
+		if count != 0 {
+			return true
+		}
+	}
+	return false
+}
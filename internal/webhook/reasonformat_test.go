@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestReasonFormatIssues(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason string
+		issues []string
+	}{
+		{name: "PlainReason", reason: "Replacing a faulty disk", issues: nil},
+		{name: "MatchedHTMLTags", reason: "Replacing <b>disk</b><br/>", issues: nil},
+		{name: "UnmatchedHTMLTag", reason: "Replacing <b>disk", issues: []string{"unmatched HTML tags"}},
+		{name: "MarkdownCodeBlock", reason: "```Replacing disk```", issues: []string{"markdown code block"}},
+		{name: "YAMLLeadingCharacter", reason: "{Replacing disk}", issues: []string{"YAML special leading character {"}},
+		{name: "NonPrintableCharacter", reason: "Replacing\tdisk", issues: []string{"non-printable characters"}},
+		{name: "MultipleIssues", reason: "* <i>Replacing disk\n", issues: []string{"unmatched HTML tags", "YAML special leading character *", "non-printable characters"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(reasonFormatIssues(test.reason)).Should(Equal(test.issues))
+		})
+	}
+}
+
+func TestReasonFormatValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		allowed bool
+	}{
+		{name: "ValidationEnabled", data: map[string]string{reasonFormatKey: "true"}, allowed: false},
+		{name: "ValidationDisabled", data: map[string]string{}, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+
+			test.data[reasonRegexPatternKey] = ".*"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: "```Testing```"}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
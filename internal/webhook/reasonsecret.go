@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	reasonSecretRefAnnotation = "node.dana.io/reason-secret-ref"
+	reasonSecretKey           = "reason"
+)
+
+// resolveReason returns the reason of the operation on the node, and whether it exists. The reason is the value of
+// the reason annotation, or, for sensitive reasons, the value of the "reason" key of the Secret referenced by the
+// reason secret ref annotation as <namespace>/<name>. The reason annotation wins if both annotations are set.
+// A missing Secret means there is no reason.
+func resolveReason(ctx context.Context, node *corev1.Node, c client.Client) (string, bool, error) {
+	reason, doesReasonExist := node.Annotations[ReasonAnnotationKey()]
+	ref, hasSecretRef := node.Annotations[reasonSecretRefAnnotation]
+	if doesReasonExist || !hasSecretRef {
+		if doesReasonExist && hasSecretRef {
+			log.FromContext(ctx).Info("Both reason annotations exist, the reason secret is ignored", "node", node.Name, "SecretRef", ref)
+		}
+		return reason, doesReasonExist, nil
+	}
+
+	if namespace, name, found := strings.Cut(ref, "/"); !found || namespace == "" || name == "" {
+		return "", false, fmt.Errorf("invalid %q annotation %q, expected <namespace>/<name>", reasonSecretRefAnnotation, ref)
+	}
+	value, err := getSecretValue(ctx, c, ref, reasonSecretKey)
+	if apierrors.IsNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(value), true, nil
+}
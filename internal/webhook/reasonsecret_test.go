@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestReasonSecret(t *testing.T) {
+	const secretRef = "incidents/node-reason"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		secretError bool
+		allowed     bool
+		code        int32
+	}{
+		{name: "ValidSecretReason", annotations: map[string]string{reasonSecretRefAnnotation: secretRef}, allowed: true},
+		{name: "MissingSecret", annotations: map[string]string{reasonSecretRefAnnotation: "incidents/missing"}, allowed: false, code: CodeMissingReason},
+		{name: "BothAnnotations", annotations: map[string]string{reasonAnnotation: "for fun", reasonSecretRefAnnotation: secretRef}, allowed: false, code: CodeReasonNotAllowed},
+		{name: "SecretFetchError", annotations: map[string]string{reasonSecretRefAnnotation: secretRef}, secretError: true, allowed: false, code: http.StatusInternalServerError},
+		{name: "InvalidSecretRef", annotations: map[string]string{reasonSecretRefAnnotation: "node-reason"}, allowed: false, code: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			objects := []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+					Data:       map[string]string{allowedReasonsKey: "Testing"},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-reason", Namespace: "incidents"},
+					Data:       map[string][]byte{reasonSecretKey: []byte("Testing")},
+				},
+			}
+			fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*corev1.Secret); ok && test.secretError {
+						return errors.New("connection refused")
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(response.Result.Code).Should(Equal(test.code))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// riskKey is the key under which the operations of the user are recorded in the RateLimiterBackend for the risk score,
+// so that they don't mix with the operations counted by the rate limit.
+func riskKey(user string, operation Operation) string {
+	return "risk:" + string(operation) + ":" + user
+}
+
+// checkRiskScore denies the operation if it would bring the risk score of the user over the maximum of the policy.
+// The risk score is the sum of the weights of the operations the user performed within the risk window. Otherwise,
+// the operation is recorded unless in dry run mode. The service accounts of the trusted namespaces and the operations
+// without a weight aren't scored.
+func (n *NodeValidator) checkRiskScore(ctx context.Context, operation Operation, nodeName string, user string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool) {
+	weight := policy.RiskWeights[operation]
+	if policy.MaxRiskScorePerWindow <= 0 || policy.RiskWindow <= 0 || weight <= 0 || isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		return admission.Response{}, true
+	}
+
+	backend := n.RateLimiterBackend
+	if backend == nil {
+		backend = &n.rateLimits
+	}
+
+	now := n.now()
+	score := 0
+	for scoredOperation, scoredWeight := range policy.RiskWeights {
+		operations, err := backend.Operations(ctx, riskKey(user, scoredOperation), policy.RiskWindow, now)
+		if err != nil {
+			log.Error(err, "Failed to fetch the recent operations of the user", "User", user)
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to fetch the recent operations of %q: %w", user, err)), false
+		}
+		score += scoredWeight * len(operations)
+	}
+	if score+weight > policy.MaxRiskScorePerWindow {
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: RiskScoreExceededCode,
+			Grounds: "risk score exceeded", Details: []any{"RiskScore", score, "Weight", weight}})
+		return deniedWithDetail(DenialDetail{
+			Code:      RiskScoreExceededCode,
+			Operation: operation,
+			User:      user,
+			Message: fmt.Sprintf("%q user has a risk score of %d within %s, and a %s operation weighs %d. The maximum risk score is %d",
+				user, score, policy.RiskWindow, operation, weight, policy.MaxRiskScorePerWindow),
+		}), false
+	}
+
+	if dryRun {
+		return admission.Response{}, true
+	}
+	if err := backend.Record(ctx, riskKey(user, operation), policy.RiskWindow, now); err != nil {
+		log.Error(err, "Failed to record the operation of the user", "User", user)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to record the operation of %q: %w", user, err)), false
+	}
+	return admission.Response{}, true
+}
+
+// parseRiskWeights parses a comma separated list of operation=weight pairs, e.g. "delete=100,cordon=10".
+func parseRiskWeights(value string) (map[Operation]int, error) {
+	weights := make(map[Operation]int)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		operation, weight, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("expected operation=weight, got %q", pair)
+		}
+		riskWeight, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || riskWeight < 0 {
+			return nil, fmt.Errorf("invalid weight %q for operation %q", weight, operation)
+		}
+		weights[Operation(strings.TrimSpace(operation))] = riskWeight
+	}
+	return weights, nil
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestRiskScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      string
+		cordons   int
+		elapsed   time.Duration
+		operation Operation
+		allowed   bool
+	}{
+		{name: "BelowMaxScore", user: regularUserExample, cordons: 1, elapsed: 10 * time.Second, operation: Cordon, allowed: true},
+		{name: "AboveMaxScore", user: regularUserExample, cordons: 2, elapsed: 10 * time.Second, operation: Cordon, allowed: false},
+		{name: "LightOperationWithinMaxScore", user: regularUserExample, cordons: 2, elapsed: 10 * time.Second, operation: Uncordon, allowed: true},
+		{name: "HeavyOperation", user: regularUserExample, cordons: 0, operation: Delete, allowed: false},
+		{name: "AfterWindowReset", user: regularUserExample, cordons: 2, elapsed: 61 * time.Second, operation: Cordon, allowed: true},
+		{name: "ServiceAccount", user: trustedServiceAccount, cordons: 2, elapsed: 10 * time.Second, operation: Cordon, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data: map[string]string{
+					allowedReasonsKey: "Testing",
+					riskWeightsKey:    "delete=100,cordon=10,uncordon=1",
+					riskWindowKey:     "60",
+					maxRiskScoreKey:   "25",
+				},
+			})).Should(Succeed())
+			clock := &fakeClock{now: time.Now()}
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: clock}
+			annotations := map[string]string{reasonAnnotation: "Testing"}
+
+			for range test.cordons {
+				response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+				g.Expect(response.Allowed).Should(BeTrue())
+			}
+
+			clock.now = clock.now.Add(test.elapsed)
+			var response admission.Response
+			switch test.operation {
+			case Cordon:
+				response = nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			case Uncordon:
+				node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: test.name}, Spec: corev1.NodeSpec{Unschedulable: true}}
+				uncordonedNode := *node.DeepCopy()
+				uncordonedNode.Spec.Unschedulable = false
+				response = nv.Handle(ctx, newUpdateRequest(g, test.user, node, uncordonedNode))
+			case Delete:
+				response = nv.Handle(ctx, newDeleteRequest(g, test.name, test.user, annotations))
+			}
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(RiskScoreExceededCode))
+			}
+		})
+	}
+}
+
+func TestParseRiskWeights(t *testing.T) {
+	g := NewWithT(t)
+	weights, err := parseRiskWeights("delete=100, cordon = 10,")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(weights).Should(Equal(map[Operation]int{Delete: 100, Cordon: 10}))
+
+	_, err = parseRiskWeights("delete:100")
+	g.Expect(err).Should(HaveOccurred())
+	_, err = parseRiskWeights("delete=-1")
+	g.Expect(err).Should(HaveOccurred())
+}
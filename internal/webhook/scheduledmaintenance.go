@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+const scheduledMaintenanceAnnotation = "node.dana.io/scheduled-maintenance-id"
+
+// +kubebuilder:rbac:groups=dana.io,resources=scheduledmaintenances,verbs=get;list;watch
+
+// approveScheduledMaintenance approves an operation requiring a reason without a reason annotation if the node
+// references a ScheduledMaintenance by the scheduled maintenance annotation, and the maintenance covers the operation:
+// the current time is within its window, the operation is one of its allowed operations, and the node matches its
+// allowed nodes. It returns false if the operation isn't approved, along with a warning explaining why
+// the referenced maintenance doesn't apply, if any. The approval is recorded with the reason of the maintenance.
+func (n *NodeValidator) approveScheduledMaintenance(ctx context.Context, operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool, string) {
+	name, ok := node.Annotations[scheduledMaintenanceAnnotation]
+	if !ok {
+		return admission.Response{}, false, ""
+	}
+
+	maintenance := v1alpha1.ScheduledMaintenance{}
+	if err := n.Client.Get(ctx, client.ObjectKey{Name: name}, &maintenance); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return admission.Response{}, false, fmt.Sprintf("ScheduledMaintenance %q doesn't exist", name)
+		}
+		log.Error(err, "Failed to fetch the scheduled maintenance", "ScheduledMaintenance", name)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to fetch ScheduledMaintenance %q: %w", name, err)), true, ""
+	}
+	if notApplicable := scheduledMaintenanceMismatch(&maintenance, operation, node.Name, n.now()); notApplicable != "" {
+		return admission.Response{}, false, fmt.Sprintf("ScheduledMaintenance %q doesn't apply since %s", name, notApplicable)
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionAllowed, Reason: maintenance.Spec.Reason,
+		Grounds: "scheduled maintenance", Details: []any{"ScheduledMaintenance", name}})
+	response := admission.Allowed(fmt.Sprintf("%s operation has been approved during scheduled maintenance %q", operation, name))
+	if !dryRun {
+		n.recordDecision(node, operation, user, maintenance.Spec.Reason, policy, response)
+	}
+	return response, true, ""
+}
+
+// scheduledMaintenanceMismatch returns why the maintenance doesn't cover the operation on the node at the given time,
+// or an empty string if it does.
+func scheduledMaintenanceMismatch(maintenance *v1alpha1.ScheduledMaintenance, operation Operation, nodeName string, now time.Time) string {
+	switch {
+	case now.Before(maintenance.Spec.StartTime.Time):
+		return fmt.Sprintf("it starts at %s", maintenance.Spec.StartTime.Format(time.RFC3339))
+	case !now.Before(maintenance.Spec.EndTime.Time):
+		return fmt.Sprintf("it ended at %s", maintenance.Spec.EndTime.Format(time.RFC3339))
+	case !slices.Contains(maintenance.Spec.AllowedOperations, string(operation)):
+		return fmt.Sprintf("it only allows the %v operations", maintenance.Spec.AllowedOperations)
+	case !isBypassNode(nodeName, maintenance.Spec.AllowedNodes):
+		return fmt.Sprintf("node %q doesn't match its allowed nodes %v", nodeName, maintenance.Spec.AllowedNodes)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+)
+
+func TestScheduledMaintenance(t *testing.T) {
+	const maintenanceName = "kernel-upgrade"
+	start := time.Date(2026, 10, 14, 22, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+
+	tests := []struct {
+		name              string
+		nodeName          string
+		maintenanceID     string
+		allowedOperations []string
+		now               time.Time
+		user              string
+		allowed           bool
+		code              int32
+		warning           bool
+	}{
+		{name: "WithinWindow", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: start.Add(time.Hour), user: regularUserExample, allowed: true},
+		{name: "AtStartTime", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: start, user: regularUserExample, allowed: true},
+		{name: "BeforeWindow", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: start.Add(-time.Minute), user: regularUserExample, allowed: false, code: CodeMissingReason, warning: true},
+		{name: "AtEndTime", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: end, user: regularUserExample, allowed: false, code: CodeMissingReason, warning: true},
+		{name: "OperationNotAllowed", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"delete"}, now: start.Add(time.Hour), user: regularUserExample, allowed: false, code: CodeMissingReason, warning: true},
+		{name: "NodeNotAllowed", nodeName: "cpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: start.Add(time.Hour), user: regularUserExample, allowed: false, code: CodeMissingReason, warning: true},
+		{name: "MissingMaintenance", nodeName: "gpu-1", maintenanceID: "missing", allowedOperations: []string{"cordon"}, now: start.Add(time.Hour), user: regularUserExample, allowed: false, code: CodeMissingReason, warning: true},
+		{name: "NoAnnotation", nodeName: "gpu-1", allowedOperations: []string{"cordon"}, now: start.Add(time.Hour), user: regularUserExample, allowed: false, code: CodeMissingReason},
+		{name: "ForbiddenUser", nodeName: "gpu-1", maintenanceID: maintenanceName, allowedOperations: []string{"cordon"}, now: start.Add(time.Hour), user: systemAdminUser, allowed: false, code: CodeForbiddenUser},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := testclient.NewClientBuilder().WithScheme(newScheme()).WithObjects(
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+					Data:       map[string]string{allowedReasonsKey: "Testing"},
+				},
+				&v1alpha1.ScheduledMaintenance{
+					ObjectMeta: metav1.ObjectMeta{Name: maintenanceName},
+					Spec: v1alpha1.ScheduledMaintenanceSpec{
+						StartTime:         metav1.NewTime(start),
+						EndTime:           metav1.NewTime(end),
+						AllowedOperations: test.allowedOperations,
+						AllowedNodes:      []string{"gpu-*"},
+						Reason:            "Kernel upgrade",
+					},
+				},
+			).Build()
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: test.now}}
+
+			var annotations map[string]string
+			if test.maintenanceID != "" {
+				annotations = map[string]string{scheduledMaintenanceAnnotation: test.maintenanceID}
+			}
+			response := nv.Handle(ctx, newCordonRequest(g, test.nodeName, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(response.Result.Code).Should(Equal(test.code))
+			}
+			if test.warning {
+				g.Expect(response.Warnings).Should(ContainElement(ContainSubstring(test.maintenanceID)))
+			} else {
+				g.Expect(response.Warnings).Should(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestScheduledMaintenanceMismatch(t *testing.T) {
+	start := time.Date(2026, 10, 14, 22, 0, 0, 0, time.UTC)
+	maintenance := v1alpha1.ScheduledMaintenance{Spec: v1alpha1.ScheduledMaintenanceSpec{
+		StartTime:         metav1.NewTime(start),
+		EndTime:           metav1.NewTime(start.Add(time.Hour)),
+		AllowedOperations: []string{"drain", "delete"},
+		AllowedNodes:      []string{"worker-1", "gpu-*"},
+	}}
+
+	g := NewWithT(t)
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Drain, "worker-1", start)).Should(BeEmpty())
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Delete, "gpu-7", start.Add(30*time.Minute))).Should(BeEmpty())
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Drain, "worker-1", start.Add(-time.Second))).Should(ContainSubstring("starts"))
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Drain, "worker-1", start.Add(time.Hour))).Should(ContainSubstring("ended"))
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Cordon, "worker-1", start)).Should(ContainSubstring("operations"))
+	g.Expect(scheduledMaintenanceMismatch(&maintenance, Drain, "worker-2", start)).Should(ContainSubstring("worker-2"))
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	selfTestNodeName = "node-operation-validator-self-test"
+	// selfTestUser is the service account of the webhook, as deployed by the default manifests.
+	selfTestUser = serviceAccountUser + cmNamespace + ":node-operation-validator-controller-manager"
+)
+
+// selfTestPolicy is the policy the synthetic requests of the self-test are validated against,
+// so that the self-test doesn't depend on the policies of the cluster.
+var selfTestPolicy = Policy{AllowedReasons: []string{"SelfTest"}}
+
+// staticPolicyResolver resolves the same policy for every node.
+type staticPolicyResolver struct {
+	policy Policy
+}
+
+// Resolve returns the policy of the resolver.
+func (s staticPolicyResolver) Resolve(context.Context, *corev1.Node) (Policy, error) {
+	return s.policy, nil
+}
+
+// selfTestCase is a synthetic request of the self-test along with its expected decision.
+type selfTestCase struct {
+	name      string
+	operation admissionv1.Operation
+	user      string
+	allowed   bool
+}
+
+// selfTestCases are the synthetic requests of the self-test: operations of the webhook service account,
+// which are allowed, and an operation of a forbidden user, which is denied.
+var selfTestCases = []selfTestCase{
+	{name: "ServiceAccountCordon", operation: admissionv1.Update, user: selfTestUser, allowed: true},
+	{name: "ServiceAccountDelete", operation: admissionv1.Delete, user: selfTestUser, allowed: true},
+	{name: "ForbiddenUserCordon", operation: admissionv1.Update, user: systemAdminUser, allowed: false},
+}
+
+// SelfTest validates synthetic cordon and delete requests on a fake node, and returns an error if any decision
+// isn't the expected one. It is meant to run at startup, to catch regressions of the admission logic before
+// serving traffic. It has no side effects and doesn't call the API server.
+func (n *NodeValidator) SelfTest(ctx context.Context) error {
+	validator := &NodeValidator{Decoder: n.Decoder, PolicyResolver: staticPolicyResolver{policy: selfTestPolicy}}
+
+	var errs []error
+	for _, test := range selfTestCases {
+		req, err := newSelfTestRequest(test)
+		if err != nil {
+			return err
+		}
+		allowed, reason, err := validator.DryRunHandle(ctx, req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", test.name, err))
+			continue
+		}
+		if allowed != test.allowed {
+			errs = append(errs, fmt.Errorf("%s: expected allowed to be %t, got %t: %s", test.name, test.allowed, allowed, reason))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newSelfTestRequest returns the synthetic request of the self-test case: a cordon for an update, or a delete.
+func newSelfTestRequest(test selfTestCase) (admission.Request, error) {
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: selfTestNodeName}}
+	oldNodeObj, err := json.Marshal(node)
+	if err != nil {
+		return admission.Request{}, err
+	}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Name:      selfTestNodeName,
+		Operation: test.operation,
+		UserInfo:  authenticationv1.UserInfo{Username: test.user},
+		Kind:      metav1.GroupVersionKind{Kind: "Node", Version: "v1"},
+		OldObject: runtime.RawExtension{Raw: oldNodeObj},
+	}}
+	if test.operation == admissionv1.Update {
+		node.Spec.Unschedulable = true
+		nodeObj, err := json.Marshal(node)
+		if err != nil {
+			return admission.Request{}, err
+		}
+		req.Object = runtime.RawExtension{Raw: nodeObj}
+	}
+	return req, nil
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestSelfTest(t *testing.T) {
+	g := NewWithT(t)
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme)}
+	g.Expect(nv.SelfTest(context.Background())).Should(Succeed())
+}
+
+func TestSelfTestFailure(t *testing.T) {
+	g := NewWithT(t)
+	cases := selfTestCases
+	t.Cleanup(func() { selfTestCases = cases })
+	selfTestCases = []selfTestCase{
+		{name: "RegularUserCordonWithoutReason", operation: admissionv1.Update, user: regularUserExample, allowed: true},
+	}
+
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme)}
+	err := nv.SelfTest(context.Background())
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).Should(ContainSubstring("RegularUserCordonWithoutReason"))
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestIsServiceAccountInTrustedNamespace(t *testing.T) {
+	tests := []struct {
+		name              string
+		user              string
+		trustedNamespaces []string
+		trusted           bool
+	}{
+		{name: "KubeSystem", user: serviceAccountUser + "kube-system:node-controller", trustedNamespaces: []string{"monitoring"}, trusted: true},
+		{name: "TrustedNamespace", user: serviceAccountUser + "monitoring:agent", trustedNamespaces: []string{"monitoring"}, trusted: true},
+		{name: "UntrustedNamespace", user: serviceAccountUser + "default:default", trustedNamespaces: []string{"monitoring"}, trusted: false},
+		{name: "Wildcard", user: serviceAccountUser + "default:default", trustedNamespaces: []string{"*"}, trusted: true},
+		{name: "Default", user: serviceAccountUser + "default:default", trustedNamespaces: nil, trusted: true},
+		{name: "RegularUser", user: regularUserExample, trustedNamespaces: []string{"*"}, trusted: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isServiceAccountInTrustedNamespace(test.user, serviceAccountUser, test.trustedNamespaces)).Should(Equal(test.trusted))
+		})
+	}
+}
+
+func TestTrustedServiceAccountNamespaces(t *testing.T) {
+	tests := []struct {
+		name              string
+		trustedNamespaces string
+		user              string
+		reason            string
+		allowed           bool
+		code              string
+	}{
+		{name: "KubeSystemWithoutReason", trustedNamespaces: "monitoring", user: serviceAccountUser + "kube-system:node-controller", allowed: true},
+		{name: "UntrustedWithoutReason", trustedNamespaces: "monitoring", user: serviceAccountUser + "default:default", allowed: false, code: MissingReasonCode},
+		{name: "UntrustedWithValidReason", trustedNamespaces: "monitoring", user: serviceAccountUser + "default:default", reason: "Testing", allowed: true},
+		{name: "WildcardWithoutReason", trustedNamespaces: "*", user: serviceAccountUser + "default:default", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", trustedSANamespacesKey: test.trustedNamespaces},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			annotations := map[string]string{}
+			if test.reason != "" {
+				annotations[reasonAnnotation] = test.reason
+			}
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(test.code))
+			}
+		})
+	}
+}
+
+func TestServiceAccountPrefix(t *testing.T) {
+	const customPrefix = "k3s:serviceaccount:"
+
+	tests := []struct {
+		name    string
+		prefix  string
+		user    string
+		allowed bool
+	}{
+		{name: "CustomPrefix", prefix: customPrefix, user: customPrefix + "monitoring:agent", allowed: true},
+		{name: "StandardPrefixWithCustomPrefix", prefix: customPrefix, user: serviceAccountUser + "monitoring:agent", allowed: false},
+		{name: "StandardPrefix", user: serviceAccountUser + "monitoring:agent", allowed: true},
+		{name: "CustomPrefixWithoutKey", user: customPrefix + "monitoring:agent", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing", trustedSANamespacesKey: "monitoring"}
+			if test.prefix != "" {
+				data[saPrefixKey] = test.prefix
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, test.user, nil))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
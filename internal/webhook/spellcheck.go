@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/go-logr/logr"
+)
+
+// reasonDictionary holds common words of node operation reasons, against which the allowed reasons are spell-checked.
+var reasonDictionary = []string{
+	"access", "backup", "capacity", "certificate", "configuration", "cordon", "debugging", "decommission",
+	"dependency", "disk", "drain", "emergency", "error", "failure", "firmware", "hardware", "incident",
+	"investigation", "invalid", "kernel", "maintenance", "memory", "migration", "network", "outage", "patch",
+	"performance", "power", "provisioning", "reboot", "recovery", "repair", "replacement", "rollback", "rollout",
+	"scheduled", "security", "storage", "testing", "troubleshooting", "unauthorized", "update", "upgrade",
+}
+
+// defaultReasonSuggestionMaxDistance is the default maximum edit distance between a denied reason
+// and the allowed reason suggested instead.
+const defaultReasonSuggestionMaxDistance = 3
+
+// warnedMisspellings holds the allowed reasons already warned about, since the policies are resolved on every request.
+var warnedMisspellings sync.Map
+
+// warnMisspelledReasons logs a warning, once per reason, for the allowed reasons which look like misspellings,
+// since a misspelled allowed reason rejects the correctly spelled reasons.
+func warnMisspelledReasons(allowedReasons []string, log logr.Logger) {
+	for _, reason := range allowedReasons {
+		suggestions := spellingSuggestions(reason)
+		if len(suggestions) == 0 {
+			continue
+		}
+		if _, warned := warnedMisspellings.LoadOrStore(reason, true); !warned {
+			log.Info("Allowed reason looks misspelled", "Reason", reason, "Suggestions", suggestions)
+		}
+	}
+}
+
+// spellingSuggestions returns the dictionary words close to the words of the reason which aren't in the dictionary.
+// A word is close to a dictionary word if it is within an edit distance of 1, or 2 for words of at least 8 letters.
+func spellingSuggestions(reason string) []string {
+	var suggestions []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(reason), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len(word) < 4 || slices.Contains(reasonDictionary, word) {
+			continue
+		}
+		maxDistance := 1
+		if len(word) >= 8 {
+			maxDistance = 2
+		}
+		for _, dictionaryWord := range reasonDictionary {
+			if editDistance(word, dictionaryWord) <= maxDistance {
+				suggestions = append(suggestions, dictionaryWord)
+			}
+		}
+	}
+	return suggestions
+}
+
+// suggestClosestReason returns the allowed reason closest to the reason, ignoring case, or an empty string if
+// the closest one is further than maxDistance edits away. The first of the closest allowed reasons is returned.
+// A maxDistance of zero means the default maximum distance.
+func suggestClosestReason(reason string, allowedReasons []string, maxDistance int) string {
+	if maxDistance <= 0 {
+		maxDistance = defaultReasonSuggestionMaxDistance
+	}
+
+	suggestion, suggestionDistance := "", maxDistance+1
+	for _, allowedReason := range allowedReasons {
+		if distance := editDistance(strings.ToLower(reason), strings.ToLower(allowedReason)); distance < suggestionDistance {
+			suggestion, suggestionDistance = allowedReason, distance
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the optimal string alignment distance between a and b: the number of insertions,
+// deletions, substitutions and transpositions of adjacent letters turning a into b.
+func editDistance(a string, b string) int {
+	distances := make([][]int, len(a)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(b)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			distances[i][j] = min(distances[i-1][j]+1, distances[i][j-1]+1, distances[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				distances[i][j] = min(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+	return distances[len(a)][len(b)]
+}
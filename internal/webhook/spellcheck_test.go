@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+)
+
+func TestSpellingSuggestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		reason      string
+		suggestions []string
+	}{
+		{name: "MissingLetter", reason: "maintenace", suggestions: []string{"maintenance"}},
+		{name: "SwappedLetters", reason: "Kernel upgarde", suggestions: []string{"upgrade"}},
+		{name: "CorrectSpelling", reason: "Hardware failure", suggestions: nil},
+		{name: "UnknownWord", reason: "Kubernetes", suggestions: nil},
+		{name: "ShortWord", reason: "dsk", suggestions: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(spellingSuggestions(test.reason)).Should(Equal(test.suggestions))
+		})
+	}
+}
+
+func TestWarnMisspelledReasons(t *testing.T) {
+	g := NewWithT(t)
+	var warnings []string
+	logger := funcr.New(func(_, args string) { warnings = append(warnings, args) }, funcr.Options{})
+
+	warnMisspelledReasons([]string{"Testing", "Decomission"}, logger)
+	warnMisspelledReasons([]string{"Decomission"}, logger)
+	g.Expect(warnings).Should(HaveLen(1))
+	g.Expect(warnings[0]).Should(ContainSubstring("Decomission"))
+	g.Expect(warnings[0]).Should(ContainSubstring("decommission"))
+}
+
+func TestEditDistance(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(editDistance("maintenance", "maintenance")).Should(Equal(0))
+	g.Expect(editDistance("maintenace", "maintenance")).Should(Equal(1))
+	g.Expect(editDistance("", "disk")).Should(Equal(4))
+	g.Expect(editDistance("upgarde", "upgrade")).Should(Equal(1))
+	g.Expect(editDistance("disk", "desk")).Should(Equal(1))
+}
+
+func TestSuggestClosestReason(t *testing.T) {
+	allowedReasons := []string{"maintenance", "Upgrade", "Decommission"}
+
+	tests := []struct {
+		name        string
+		reason      string
+		maxDistance int
+		suggestion  string
+	}{
+		{name: "Typo", reason: "maintenace", suggestion: "maintenance"},
+		{name: "SwappedLetters", reason: "upgarde", suggestion: "Upgrade"},
+		{name: "CaseInsensitive", reason: "UPGRADES", suggestion: "Upgrade"},
+		{name: "NoCloseMatch", reason: "for fun", suggestion: ""},
+		{name: "AtThreshold", reason: "Decom-ision!", suggestion: "Decommission"},
+		{name: "BeyondThreshold", reason: "Decom-ision!!", suggestion: ""},
+		{name: "CustomThreshold", reason: "maintenace", maxDistance: 1, suggestion: "maintenance"},
+		{name: "BeyondCustomThreshold", reason: "maintnace", maxDistance: 1, suggestion: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(suggestClosestReason(test.reason, allowedReasons, test.maxDistance)).Should(Equal(test.suggestion))
+		})
+	}
+}
+
+func TestInvalidReasonSuggestion(t *testing.T) {
+	g := NewWithT(t)
+	policy := Policy{AllowedReasons: []string{"Maintenance", "Testing"}}
+
+	g.Expect(invalidReasonMessage(policy, "maintenace")).Should(HaveSuffix(`Did you mean: 'Maintenance'?`))
+	g.Expect(invalidReasonMessage(policy, "for fun")).ShouldNot(ContainSubstring("Did you mean"))
+
+	policy.ReasonSuggestionMaxDistance = 1
+	g.Expect(invalidReasonMessage(policy, "maintnace")).ShouldNot(ContainSubstring("Did you mean"))
+}
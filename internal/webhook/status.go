@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// EnableStatusPageEnv enables the status page when it is true.
+	EnableStatusPageEnv = "ENABLE_STATUS_PAGE"
+	// decisionStatsWindow is the period over which the decisions are summarized on the status page.
+	decisionStatsWindow = 24 * time.Hour
+	topDeniedUsersLimit = 5
+)
+
+//go:embed status.html.tmpl
+var statusPageTemplateText string
+
+var statusPageTemplate = template.Must(template.New("status").Parse(statusPageTemplateText))
+
+// StatusPageEnabled returns true if the ENABLE_STATUS_PAGE environment variable is true.
+func StatusPageEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(EnableStatusPageEnv))
+	return err == nil && enabled
+}
+
+// decisionStats keeps the decisions of the last 24 hours in memory, for the status page.
+// Its zero value is ready to use.
+type decisionStats struct {
+	mu        sync.Mutex
+	decisions []decisionStat
+}
+
+// decisionStat is a decision on an operation by a user.
+type decisionStat struct {
+	at     time.Time
+	user   string
+	denied bool
+}
+
+// UserDenials is the number of denied operations of a user.
+type UserDenials struct {
+	User    string
+	Denials int
+}
+
+// DecisionSummary summarizes the decisions of the last 24 hours.
+type DecisionSummary struct {
+	Total          int
+	Denied         int
+	DenialRate     float64
+	TopDeniedUsers []UserDenials
+}
+
+// record stores a decision, dropping the decisions older than the stats window.
+func (s *decisionStats) record(user string, denied bool, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	s.decisions = append(s.decisions, decisionStat{at: now, user: user, denied: denied})
+}
+
+// summary returns the summary of the decisions within the stats window.
+func (s *decisionStats) summary(now time.Time) DecisionSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(now)
+	summary := DecisionSummary{Total: len(s.decisions)}
+	deniedByUser := map[string]int{}
+	for _, decision := range s.decisions {
+		if decision.denied {
+			summary.Denied++
+			deniedByUser[decision.user]++
+		}
+	}
+	if summary.Total > 0 {
+		summary.DenialRate = float64(summary.Denied) / float64(summary.Total)
+	}
+	for user, denials := range deniedByUser {
+		summary.TopDeniedUsers = append(summary.TopDeniedUsers, UserDenials{User: user, Denials: denials})
+	}
+	slices.SortFunc(summary.TopDeniedUsers, func(a, b UserDenials) int {
+		if a.Denials != b.Denials {
+			return b.Denials - a.Denials
+		}
+		return strings.Compare(a.User, b.User)
+	})
+	if len(summary.TopDeniedUsers) > topDeniedUsersLimit {
+		summary.TopDeniedUsers = summary.TopDeniedUsers[:topDeniedUsersLimit]
+	}
+	return summary
+}
+
+// prune drops the decisions older than the stats window. The decisions are stored in chronological order.
+func (s *decisionStats) prune(now time.Time) {
+	cutoff := now.Add(-decisionStatsWindow)
+	i := 0
+	for i < len(s.decisions) && !s.decisions[i].at.After(cutoff) {
+		i++
+	}
+	s.decisions = s.decisions[i:]
+}
+
+// CircuitBreakerState is the state of a named circuit breaker.
+type CircuitBreakerState struct {
+	Name  string
+	State string
+}
+
+// statusPage holds the data rendered on the status page.
+type statusPage struct {
+	Version          string
+	Uptime           time.Duration
+	Decisions        DecisionSummary
+	DenialPercent    float64
+	ConfigMapVersion string
+	CircuitBreakers  []CircuitBreakerState
+}
+
+// StatusHandler serves an HTML page on GET /status showing the live status of the webhook: its version, its uptime,
+// the decisions of the last 24 hours, the resource version of the global ConfigMap, and the states of the circuit
+// breakers. It is only served when ENABLE_STATUS_PAGE is true, on its own address.
+type StatusHandler struct {
+	// Validator is the validator whose decisions are shown.
+	Validator *NodeValidator
+	// Client fetches the global ConfigMap.
+	Client client.Client
+	// Version is the version of the webhook.
+	Version string
+	// StartTime is the time the webhook started at, from which the uptime is computed.
+	StartTime time.Time
+	// CircuitBreakers are the circuit breakers whose states are shown, by name.
+	CircuitBreakers map[string]*CircuitBreakerClient
+}
+
+// ServeHTTP renders the status page.
+func (h *StatusHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(resp, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	logger := log.FromContext(req.Context()).WithName("Status")
+	now := h.Validator.now()
+	page := statusPage{
+		Version:          h.Version,
+		Uptime:           now.Sub(h.StartTime).Truncate(time.Second),
+		Decisions:        h.Validator.decisions.summary(now),
+		ConfigMapVersion: "not found",
+	}
+	page.DenialPercent = page.Decisions.DenialRate * 100
+
+	configMap := corev1.ConfigMap{}
+	if err := h.Client.Get(req.Context(), client.ObjectKey{Namespace: cmNamespace, Name: cmName}, &configMap); err == nil {
+		page.ConfigMapVersion = configMap.ResourceVersion
+	} else if !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to fetch the ConfigMap")
+		page.ConfigMapVersion = fmt.Sprintf("unknown: %s", err)
+	}
+	for name, circuitBreaker := range h.CircuitBreakers {
+		page.CircuitBreakers = append(page.CircuitBreakers, CircuitBreakerState{Name: name, State: circuitBreaker.currentState().String()})
+	}
+	slices.SortFunc(page.CircuitBreakers, func(a, b CircuitBreakerState) int { return strings.Compare(a.Name, b.Name) })
+
+	resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPageTemplate.Execute(resp, page); err != nil {
+		logger.Error(err, "Failed to render the status page")
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDecisionStats(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	stats := decisionStats{}
+
+	stats.record("expired", true, now.Add(-25*time.Hour))
+	for i := range 6 {
+		user := fmt.Sprintf("user-%d", i)
+		for range i + 1 {
+			stats.record(user, true, now.Add(-time.Hour))
+		}
+	}
+	stats.record("allowed", false, now)
+	stats.record("allowed", false, now)
+
+	summary := stats.summary(now)
+	g.Expect(summary.Total).Should(Equal(23))
+	g.Expect(summary.Denied).Should(Equal(21))
+	g.Expect(summary.DenialRate).Should(BeNumerically("~", 21.0/23.0))
+	g.Expect(summary.TopDeniedUsers).Should(Equal([]UserDenials{
+		{User: "user-5", Denials: 6}, {User: "user-4", Denials: 5}, {User: "user-3", Denials: 4}, {User: "user-2", Denials: 3}, {User: "user-1", Denials: 2},
+	}))
+
+	summary = stats.summary(now.Add(24 * time.Hour))
+	g.Expect(summary.Total).Should(Equal(0))
+	g.Expect(summary.DenialRate).Should(BeZero())
+}
+
+func TestStatusHandler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	configMap := corev1.ConfigMap{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: cmNamespace, Name: cmName}, &configMap)).Should(Succeed())
+	nv := &NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: now}}
+
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-1", "<script>alert(1)</script>", nil)).Allowed).Should(BeFalse())
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-2", regularUserExample, map[string]string{reasonAnnotation: "Testing"})).Allowed).Should(BeTrue())
+
+	handler := &StatusHandler{
+		Validator:       nv,
+		Client:          fakeClient,
+		Version:         "v1.2.3",
+		StartTime:       now.Add(-90 * time.Minute),
+		CircuitBreakers: map[string]*CircuitBreakerClient{"kubernetes-api": {Client: fakeClient}},
+	}
+
+	t.Run("Page", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+		g.Expect(recorder.Code).Should(Equal(http.StatusOK))
+		g.Expect(recorder.Header().Get("Content-Type")).Should(HavePrefix("text/html"))
+
+		body := recorder.Body.String()
+		g.Expect(body).Should(ContainSubstring("<td>v1.2.3</td>"))
+		g.Expect(body).Should(ContainSubstring("<td>1h30m0s</td>"))
+		g.Expect(body).Should(ContainSubstring(fmt.Sprintf("<td>%s</td>", configMap.ResourceVersion)))
+		g.Expect(body).Should(ContainSubstring("<tr><th>Decisions</th><td>2</td></tr>"))
+		g.Expect(body).Should(ContainSubstring("<tr><th>Denials</th><td>1</td></tr>"))
+		g.Expect(body).Should(ContainSubstring("50.0%"))
+		g.Expect(body).Should(ContainSubstring("<tr><td>kubernetes-api</td><td>closed</td></tr>"))
+	})
+
+	t.Run("EscapesUsers", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		body := recorder.Body.String()
+		g.Expect(body).ShouldNot(ContainSubstring("<script>"))
+		g.Expect(body).Should(ContainSubstring("&lt;script&gt;alert(1)&lt;/script&gt;"))
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/status", nil))
+		g.Expect(recorder.Code).Should(Equal(http.StatusMethodNotAllowed))
+	})
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	statusSubResource = "status"
+	nodeUserPrefix    = "system:node:"
+	// controllerManagerUser updates the conditions of the nodes whose kubelet stopped posting their status.
+	controllerManagerUser = "system:kube-controller-manager"
+)
+
+// handleStatusPatch validates the updates of the status subresource of a node, which bypass the validation of the
+// node resource, as StatusPatch operations requiring a reason when the policy enables it. The kubelet of the node and
+// the controller manager, which continuously update the status, are exempt.
+func (n *NodeValidator) handleStatusPatch(ctx context.Context, req admission.Request, log logr.Logger, dryRun bool) admission.Response {
+	node := corev1.Node{}
+	oldNode := corev1.Node{}
+	if err := n.Decoder.DecodeRaw(req.OldObject, &oldNode); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
+	}
+	if err := n.Decoder.DecodeRaw(req.Object, &node); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
+	}
+
+	user := req.UserInfo.Username
+	if user == nodeUserPrefix+node.Name || user == controllerManagerUser || apiequality.Semantic.DeepEqual(oldNode.Status, node.Status) {
+		return admission.Allowed("Node status was updated")
+	}
+	policy, err := n.resolvePolicy(ctx, &node, log, dryRun)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve policy: %w", err))
+	}
+	if !policy.ValidateStatusSubresource {
+		return admission.Allowed("Node status was updated")
+	}
+
+	groups := n.resolveGroups(ctx, user, req.UserInfo.Groups, log)
+	response := n.validateWithEnforcementMode(ctx, StatusPatch, &node, user, req.UserInfo.UID, groups, policy, log, true, dryRun)
+	return withResponseVerbosity(withDenialMessageTemplate(response, &node, policy), &node, policy)
+}
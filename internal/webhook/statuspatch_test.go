@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestStatusPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		user        string
+		annotations map[string]string
+		unchanged   bool
+		allowed     bool
+		code        string
+	}{
+		{name: "Disabled", enabled: false, user: regularUserExample, allowed: true},
+		{name: "MissingReason", enabled: true, user: regularUserExample, allowed: false, code: MissingReasonCode},
+		{name: "InvalidReason", enabled: true, user: regularUserExample, annotations: map[string]string{reasonAnnotation: "for fun"}, allowed: false, code: InvalidReasonCode},
+		{name: "ValidReason", enabled: true, user: regularUserExample, annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: true},
+		{name: "Kubelet", enabled: true, user: "system:node:node-1", allowed: true},
+		{name: "OtherKubelet", enabled: true, user: "system:node:node-2", allowed: false, code: MissingReasonCode},
+		{name: "ControllerManager", enabled: true, user: controllerManagerUser, allowed: true},
+		{name: "TrustedServiceAccount", enabled: true, user: "system:serviceaccount:kube-system:cloud-node-manager", allowed: true},
+		{name: "UnchangedStatus", enabled: true, user: regularUserExample, unchanged: true, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing"}
+			if test.enabled {
+				data[validateStatusKey] = "true"
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			oldNode := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: test.annotations}}
+			node := *oldNode.DeepCopy()
+			if !test.unchanged {
+				node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}}
+			}
+			request := newUpdateRequest(g, test.user, oldNode, node)
+			request.SubResource = statusSubResource
+
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.code != "" {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(test.code))
+				g.Expect(detail.Operation).Should(Equal(StatusPatch))
+			}
+		})
+	}
+}
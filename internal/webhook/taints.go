@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// monitoredTaintChanges returns whether a taint with a monitored key was added or removed between the old and new taints.
+func monitoredTaintChanges(oldTaints []corev1.Taint, newTaints []corev1.Taint, monitoredTaints []string) (added bool, removed bool) {
+	for i := range newTaints {
+		if slices.Contains(monitoredTaints, newTaints[i].Key) && !hasTaint(oldTaints, &newTaints[i]) {
+			added = true
+		}
+	}
+	for i := range oldTaints {
+		if slices.Contains(monitoredTaints, oldTaints[i].Key) && !hasTaint(newTaints, &oldTaints[i]) {
+			removed = true
+		}
+	}
+	return added, removed
+}
+
+// hasTaint checks if the taints contain a taint with the same key and effect as the given taint.
+func hasTaint(taints []corev1.Taint, taint *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const monitoredTaintExample = "example.com/maintenance"
+
+func TestTaintChanges(t *testing.T) {
+	monitoredTaint := corev1.Taint{Key: monitoredTaintExample, Effect: corev1.TaintEffectNoSchedule}
+	unmonitoredTaint := corev1.Taint{Key: "example.com/other", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name      string
+		oldTaints []corev1.Taint
+		newTaints []corev1.Taint
+		reason    string
+		allowed   bool
+	}{
+		{name: "AddMonitoredTaintWithoutReason", newTaints: []corev1.Taint{monitoredTaint}, allowed: false},
+		{name: "AddMonitoredTaintWithReason", newTaints: []corev1.Taint{monitoredTaint}, reason: "Testing", allowed: true},
+		{name: "RemoveMonitoredTaintWithoutReason", oldTaints: []corev1.Taint{monitoredTaint}, allowed: true},
+		{name: "AddUnmonitoredTaint", newTaints: []corev1.Taint{unmonitoredTaint}, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing", monitoredTaintsKey: monitoredTaintExample},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			annotations := map[string]string{}
+			if test.reason != "" {
+				annotations[reasonAnnotation] = test.reason
+			}
+			oldNode := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: test.name, Annotations: annotations},
+				Spec:       corev1.NodeSpec{Taints: test.oldTaints},
+			}
+			node := *oldNode.DeepCopy()
+			node.Spec.Taints = test.newTaints
+
+			response := nv.Handle(ctx, newUpdateRequest(g, regularUserExample, oldNode, node))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
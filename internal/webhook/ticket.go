@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	ticketAPITokenSecretKey = "token"
+	ticketRequestTimeout    = 10 * time.Second
+)
+
+// ticketKeyPattern matches Jira ticket keys, e.g. "OPS-123".
+var ticketKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// defaultHTTPClient is used for the requests to external services when the validator has no HTTP client.
+var defaultHTTPClient = &http.Client{Timeout: ticketRequestTimeout}
+
+// jiraIssue is the part of a Jira issue returned by the Jira API which is used to validate tickets.
+type jiraIssue struct {
+	Fields struct {
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// validateTicket denies an approved operation unless its reason references a Jira ticket which exists
+// and, if the policy requires it, is in one of the required statuses. In warn only mode, the denial
+// message is added to the warnings of the given response. In dry run mode, the Jira API isn't called: the
+// response only warns that the ticket would be validated.
+func (n *NodeValidator) validateTicket(ctx context.Context, operation Operation, nodeName string, user string, reason string, policy Policy, log logr.Logger, dryRun bool, response admission.Response) admission.Response {
+	ticket := ticketKeyPattern.FindString(reason)
+	if ticket == "" {
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: MissingTicketCode, Reason: reason, Grounds: "reason doesn't reference a ticket"})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      MissingTicketCode,
+			Operation: operation,
+			User:      user,
+			Reason:    reason,
+			Message:   fmt.Sprintf("The %q annotation must reference a Jira ticket, e.g. OPS-123", ReasonAnnotationKey()),
+		})
+	}
+
+	if dryRun {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("Jira ticket %q would be validated", ticket))
+		return response
+	}
+
+	status, found, err := n.getTicketStatus(ctx, policy, ticket)
+	if err != nil {
+		log.Error(err, "Failed to fetch the ticket", "Ticket", ticket)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to fetch ticket %q: %w", ticket, err))
+	}
+	if !found {
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: MissingTicketCode, Reason: reason,
+			Grounds: "ticket doesn't exist", Details: []any{"Ticket", ticket}})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      MissingTicketCode,
+			Operation: operation,
+			User:      user,
+			Reason:    reason,
+			Message:   fmt.Sprintf("Jira ticket %q doesn't exist", ticket),
+		})
+	}
+	if len(policy.TicketRequiredStatuses) > 0 && !slices.ContainsFunc(policy.TicketRequiredStatuses, func(required string) bool {
+		return strings.EqualFold(required, status)
+	}) {
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: InvalidTicketStatusCode, Reason: reason,
+			Grounds: "ticket status isn't allowed", Details: []any{"Ticket", ticket, "Status", status}})
+		return denyApproved(policy, response, DenialDetail{
+			Code:      InvalidTicketStatusCode,
+			Operation: operation,
+			User:      user,
+			Reason:    reason,
+			Message:   fmt.Sprintf("Jira ticket %q is in status %q. Required statuses: %v", ticket, status, policy.TicketRequiredStatuses),
+		})
+	}
+	return response
+}
+
+// getTicketStatus fetches the status of the ticket from the Jira API, authenticating with the token
+// of the secret referenced by the policy if any. It returns false if the ticket doesn't exist.
+func (n *NodeValidator) getTicketStatus(ctx context.Context, policy Policy, ticket string) (string, bool, error) {
+	issueURL, err := url.JoinPath(policy.TicketValidationURL, "rest/api/2/issue", ticket)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid ticket validation URL %q: %w", policy.TicketValidationURL, err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, issueURL+"?fields=status", nil)
+	if err != nil {
+		return "", false, err
+	}
+	headers, err := n.externalAPIHeaders(ctx, policy)
+	if err != nil {
+		return "", false, err
+	}
+	setHeaders(request, headers)
+	request.Header.Set("Accept", "application/json")
+	if policy.TicketAPITokenSecretRef != "" {
+		token, err := getSecretValue(ctx, n.Client, policy.TicketAPITokenSecretRef, ticketAPITokenSecretKey)
+		if err != nil {
+			return "", false, err
+		}
+		request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	httpClient, err := n.externalHTTPClient(ctx, policy)
+	if err != nil {
+		return "", false, err
+	}
+	httpResponse, err := httpClient.Do(request)
+	if err != nil {
+		return "", false, err
+	}
+	defer httpResponse.Body.Close()
+
+	switch httpResponse.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("unexpected status %q from %s", httpResponse.Status, issueURL)
+	}
+
+	issue := jiraIssue{}
+	if err := json.NewDecoder(httpResponse.Body).Decode(&issue); err != nil {
+		return "", false, fmt.Errorf("failed to decode the response of %s: %w", issueURL, err)
+	}
+	return issue.Fields.Status.Name, true, nil
+}
+
+// httpClient returns the HTTP client of the validator, or the default one if it has none.
+func (n *NodeValidator) httpClient() *http.Client {
+	if n.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return n.HTTPClient
+}
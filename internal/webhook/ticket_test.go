@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const jiraTokenExample = "jira-token"
+
+// newJiraServer returns a fake Jira API serving the statuses of the given tickets to authorized requests.
+func newJiraServer(statuses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+jiraTokenExample {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		status, ok := statuses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"OPS-1","fields":{"status":{"name":"` + status + `"}}}`))
+	}))
+}
+
+func TestTicketValidation(t *testing.T) {
+	server := newJiraServer(map[string]string{
+		"/rest/api/2/issue/OPS-1": "In Progress",
+		"/rest/api/2/issue/OPS-2": "Done",
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		reason      string
+		tokenSecret string
+		allowed     bool
+		errored     bool
+		code        string
+	}{
+		{name: "TicketInProgress", reason: "Maintenance OPS-1", tokenSecret: "jira-token", allowed: true},
+		{name: "TicketDone", reason: "Maintenance OPS-2", tokenSecret: "jira-token", allowed: false, code: InvalidTicketStatusCode},
+		{name: "TicketNotFound", reason: "Maintenance OPS-3", tokenSecret: "jira-token", allowed: false, code: MissingTicketCode},
+		{name: "NoTicket", reason: "Maintenance", tokenSecret: "jira-token", allowed: false, code: MissingTicketCode},
+		{name: "Unauthorized", reason: "Maintenance OPS-1", allowed: false, errored: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{reasonRegexPatternKey: "^Maintenance", ticketURLKey: server.URL, ticketStatusesKey: "In Progress, Approved"}
+			if test.tokenSecret != "" {
+				data[ticketTokenSecretKey] = test.tokenSecret
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			g.Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "jira-token", Namespace: cmNamespace},
+				Data:       map[string][]byte{ticketAPITokenSecretKey: []byte(jiraTokenExample)},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, HTTPClient: server.Client()}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: test.reason}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.errored {
+				g.Expect(response.Result.Code).Should(Equal(int32(http.StatusInternalServerError)))
+			}
+			if test.code != "" {
+				g.Expect(denialDetail(g, response).Code).Should(Equal(test.code))
+			}
+		})
+	}
+}
+
+func TestTicketValidationDryRun(t *testing.T) {
+	g := NewWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s in dry run", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{reasonRegexPatternKey: "^Maintenance", ticketURLKey: server.URL},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, HTTPClient: server.Client()}
+
+	allowed, _, err := nv.DryRunHandle(ctx, newCordonRequest(g, "dry-run", regularUserExample, map[string]string{reasonAnnotation: "Maintenance OPS-1"}))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(allowed).Should(BeTrue())
+
+	// The reasons which don't reference a ticket are still denied.
+	allowed, _, err = nv.DryRunHandle(ctx, newCordonRequest(g, "dry-run", regularUserExample, map[string]string{reasonAnnotation: "Maintenance"}))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(allowed).Should(BeFalse())
+}
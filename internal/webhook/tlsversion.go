@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+)
+
+// MinTLSVersionEnv sets the minimum TLS version of the webhook server, TLS12 or TLS13. The chart sets it from the
+// minTLSVersion key of the ConfigMap, so the webhook must be restarted for a change to apply.
+const MinTLSVersionEnv = "minTLSVersion"
+
+// minTLSVersions maps the values of the minTLSVersion environment variable to the TLS versions.
+var minTLSVersions = map[string]uint16{
+	"TLS12": tls.VersionTLS12,
+	"TLS13": tls.VersionTLS13,
+}
+
+// MinTLSVersion returns the minimum TLS version set by the minTLSVersion environment variable, defaulting to TLS 1.2.
+func MinTLSVersion() (uint16, error) {
+	value, ok := os.LookupEnv(MinTLSVersionEnv)
+	if !ok || value == "" {
+		return tls.VersionTLS12, nil
+	}
+	version, ok := minTLSVersions[value]
+	if !ok {
+		return 0, fmt.Errorf("invalid %s environment variable %q, expected TLS12 or TLS13", MinTLSVersionEnv, value)
+	}
+	return version, nil
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestMinTLSVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		version uint16
+		valid   bool
+	}{
+		{name: "Default", value: "", version: tls.VersionTLS12, valid: true},
+		{name: "TLS12", value: "TLS12", version: tls.VersionTLS12, valid: true},
+		{name: "TLS13", value: "TLS13", version: tls.VersionTLS13, valid: true},
+		{name: "TLS11", value: "TLS11", valid: false},
+		{name: "Invalid", value: "1.3", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv(MinTLSVersionEnv, test.value)
+			version, err := MinTLSVersion()
+			if !test.valid {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(version).Should(Equal(test.version))
+		})
+	}
+}
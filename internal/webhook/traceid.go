@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// TraceIDGenerator generates the correlation ID of an admission request, logged with every log line of the request
+// so that they can be correlated, e.g. with the logs of other systems using a custom format.
+type TraceIDGenerator interface {
+	Generate(req admission.Request) string
+}
+
+// UIDTraceIDGenerator uses the UID of the admission request as its correlation ID.
+type UIDTraceIDGenerator struct{}
+
+// Generate returns the UID of the request.
+func (UIDTraceIDGenerator) Generate(req admission.Request) string {
+	return string(req.UID)
+}
+
+// WithTraceIDGenerator sets the generator of the correlation IDs of the requests.
+// It must be called before the validator handles requests.
+func (n *NodeValidator) WithTraceIDGenerator(g TraceIDGenerator) *NodeValidator {
+	n.TraceIDGenerator = g
+	return n
+}
+
+// traceID returns the correlation ID of the request.
+func (n *NodeValidator) traceID(req admission.Request) string {
+	if n.TraceIDGenerator == nil {
+		return UIDTraceIDGenerator{}.Generate(req)
+	}
+	return n.TraceIDGenerator.Generate(req)
+}
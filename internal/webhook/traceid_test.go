@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// counterTraceIDGenerator generates deterministic correlation IDs of the form <env>-<counter>.
+type counterTraceIDGenerator struct {
+	env     string
+	counter int
+}
+
+func (c *counterTraceIDGenerator) Generate(admission.Request) string {
+	c.counter++
+	return fmt.Sprintf("%s-%d", c.env, c.counter)
+}
+
+func TestTraceIDGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator TraceIDGenerator
+		traceIDs  []string
+	}{
+		{name: "Default", traceIDs: []string{`"traceID"="uid-1"`, `"traceID"="uid-2"`}},
+		{name: "Custom", generator: &counterTraceIDGenerator{env: "prod"}, traceIDs: []string{`"traceID"="prod-1"`, `"traceID"="prod-2"`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(context.Background(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			nv := &NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+			if test.generator != nil {
+				nv = nv.WithTraceIDGenerator(test.generator)
+			}
+
+			for i, traceID := range test.traceIDs {
+				var lines []string
+				ctx := log.IntoContext(context.Background(), recordingLogger(&lines, 0))
+				request := newCordonRequest(g, "node-1", regularUserExample, nil)
+				request.UID = types.UID(fmt.Sprintf("uid-%d", i+1))
+				g.Expect(nv.Handle(ctx, request).Allowed).Should(BeFalse())
+				g.Expect(lines).ShouldNot(BeEmpty())
+				for _, line := range lines {
+					g.Expect(line).Should(ContainSubstring(traceID))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// customCACertSecretKey is the key of the PEM-encoded CA certificates in the Secret referenced by the policy.
+const customCACertSecretKey = "ca.crt"
+
+// WithCustomCACert makes the validator trust the PEM-encoded CA certificates, in addition to the system ones,
+// for the HTTPS requests to external services such as Jira and OPA, e.g. for internal endpoints with a corporate CA.
+// Invalid certificates are logged and ignored. It must be called before the validator handles requests.
+func (n *NodeValidator) WithCustomCACert(caPEM []byte) *NodeValidator {
+	client, err := clientTrustingCACert(n.httpClient(), caPEM)
+	if err != nil {
+		log.Log.WithName("Node Webhook").Error(err, "Invalid custom CA certificate, it isn't trusted")
+		return n
+	}
+	n.HTTPClient = client
+	return n
+}
+
+// externalHTTPClient returns the HTTP client for the requests to external services under the policy, which trusts
+// the CA certificates of the Secret referenced by the policy if any.
+func (n *NodeValidator) externalHTTPClient(ctx context.Context, policy Policy) (*http.Client, error) {
+	if policy.CustomCACertSecretRef == "" {
+		return n.httpClient(), nil
+	}
+	caPEM, err := getSecretValue(ctx, n.Client, policy.CustomCACertSecretRef, customCACertSecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return n.customCAClients.get(n.httpClient(), caPEM)
+}
+
+// customCAClient caches the HTTP client trusting the CA certificates of the Secret referenced by the policies,
+// so that the connections are reused across requests until the certificates change.
+type customCAClient struct {
+	mu     sync.Mutex
+	base   *http.Client
+	caPEM  []byte
+	client *http.Client
+}
+
+// get returns the client trusting the CA certificates in addition to those of the base client.
+func (c *customCAClient) get(base *http.Client, caPEM []byte) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil && c.base == base && bytes.Equal(c.caPEM, caPEM) {
+		return c.client, nil
+	}
+	client, err := clientTrustingCACert(base, caPEM)
+	if err != nil {
+		return nil, err
+	}
+	c.base, c.caPEM, c.client = base, caPEM, client
+	return client, nil
+}
+
+// clientTrustingCACert returns a copy of the client trusting the PEM-encoded CA certificates in addition to the
+// certificates trusted by the client, which are the system ones by default.
+func clientTrustingCACert(base *http.Client, caPEM []byte) (*http.Client, error) {
+	var transport *http.Transport
+	if baseTransport, ok := base.Transport.(*http.Transport); ok {
+		transport = baseTransport.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	pool := transport.TLSClientConfig.RootCAs
+	if pool == nil {
+		var err error
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	} else {
+		pool = pool.Clone()
+	}
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	transport.TLSClientConfig.RootCAs = pool
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newInternalTLSServer returns an HTTPS server, whose certificate is signed by an unknown CA, serving Jira issues
+// and OPA decisions, and the PEM encoding of its certificate.
+func newInternalTLSServer() (*httptest.Server, []byte) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"OPS-1","fields":{"status":{"name":"Open"}},"result":{"allow":true}}`))
+	}))
+	return server, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func TestCustomCACert(t *testing.T) {
+	server, caPEM := newInternalTLSServer()
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		option []byte
+		secret []byte
+		valid  bool
+	}{
+		{name: "SystemCertificates", valid: false},
+		{name: "Option", option: caPEM, valid: true},
+		{name: "InvalidOption", option: []byte("not a certificate"), valid: false},
+		{name: "Secret", secret: caPEM, valid: true},
+		{name: "InvalidSecret", secret: []byte("not a certificate"), valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			nv := &NodeValidator{Client: newFakeClient(), PolicyEvaluator: &OPARESTEvaluator{URL: server.URL}}
+			if test.option != nil {
+				nv = nv.WithCustomCACert(test.option)
+			}
+			policy := Policy{TicketValidationURL: server.URL}
+			if test.secret != nil {
+				g.Expect(nv.Client.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "corporate-ca", Namespace: cmNamespace},
+					Data:       map[string][]byte{customCACertSecretKey: test.secret},
+				})).Should(Succeed())
+				policy.CustomCACertSecretRef = cmNamespace + "/corporate-ca"
+			}
+
+			_, _, ticketErr := nv.getTicketStatus(ctx, policy, "OPS-1")
+			response := nv.evaluateOperation(ctx, EvaluationRequest{Node: "node-1", Operation: Cordon, Policy: policy}, nv.logger(ctx), false)
+			if !test.valid {
+				g.Expect(ticketErr).Should(HaveOccurred())
+				g.Expect(response.Allowed).Should(BeFalse())
+				return
+			}
+			g.Expect(ticketErr).ShouldNot(HaveOccurred())
+			g.Expect(response.Allowed).Should(BeTrue())
+		})
+	}
+}
+
+func TestCustomCAClientCache(t *testing.T) {
+	g := NewWithT(t)
+	server, caPEM := newInternalTLSServer()
+	defer server.Close()
+	cache := customCAClient{}
+
+	client, err := cache.get(defaultHTTPClient, caPEM)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(client).ShouldNot(BeIdenticalTo(defaultHTTPClient))
+	g.Expect(client.Timeout).Should(Equal(defaultHTTPClient.Timeout))
+	cached, err := cache.get(defaultHTTPClient, caPEM)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(cached).Should(BeIdenticalTo(client))
+
+	// The CA certificate is renewed.
+	renewedPEM := newTLSSecret(g, "renewed").Data[corev1.TLSCertKey]
+	renewed, err := cache.get(defaultHTTPClient, renewedPEM)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(renewed).ShouldNot(BeIdenticalTo(client))
+}
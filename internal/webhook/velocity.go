@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// checkNodeVelocity denies the operation if the node already underwent the maximum number of operations of the policy
+// within its velocity window, since a node cordoned, uncordoned and cordoned again within seconds is a sign of
+// a runaway script. Otherwise, the operation is recorded unless in dry run mode. Service accounts aren't exempt.
+func (n *NodeValidator) checkNodeVelocity(operation Operation, nodeName string, user string, policy Policy, log logr.Logger, dryRun bool) (admission.Response, bool) {
+	if policy.NodeOperationVelocityLimit <= 0 || policy.NodeOperationVelocityWindow <= 0 {
+		return admission.Response{}, true
+	}
+
+	retryAfter, ok := n.nodeVelocity.allow(nodeName, policy.NodeOperationVelocityLimit, policy.NodeOperationVelocityWindow, n.now(), !dryRun)
+	if ok {
+		return admission.Response{}, true
+	}
+	retryAfterSeconds := int32(math.Ceil(retryAfter.Seconds()))
+	logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: NodeVelocityExceededCode,
+		Grounds: "node operation velocity exceeded"})
+	response := denied(policy, DenialDetail{
+		Code:      NodeVelocityExceededCode,
+		Operation: operation,
+		User:      user,
+		Message: fmt.Sprintf("Node %q underwent %d operations within %s, which looks like a runaway script. Please retry after %d seconds",
+			nodeName, policy.NodeOperationVelocityLimit, policy.NodeOperationVelocityWindow, retryAfterSeconds),
+	})
+	if !response.Allowed {
+		response.Result.Details = &metav1.StatusDetails{RetryAfterSeconds: retryAfterSeconds}
+	}
+	return response, false
+}
+
+// nodeVelocityTracker keeps the times of the recent operations of each node in memory, so it is only accurate when
+// the webhook runs with a single replica. The nodes without an operation within the window are dropped periodically.
+// Its zero value is ready to use.
+type nodeVelocityTracker struct {
+	nodes sync.Map // node name -> *nodeOperations
+
+	pruneMu   sync.Mutex
+	lastPrune time.Time
+}
+
+// nodeOperations is a ring buffer of the times of the latest operations of a node, holding up to the limit of the policy.
+type nodeOperations struct {
+	mu    sync.Mutex
+	times []time.Time
+	next  int
+	// window is the velocity window of the latest check of the node, after which the node is dropped without operations.
+	window time.Duration
+	// removed is set once the entry is dropped from the tracker, so that it isn't recorded into anymore.
+	removed bool
+}
+
+// allow returns true if the node underwent less than limit operations within the window ending at now, and if so
+// records an operation at now when record is true. Otherwise, it returns the time after which to retry.
+func (t *nodeVelocityTracker) allow(nodeName string, limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool) {
+	t.prune(window, now)
+
+	for {
+		value, _ := t.nodes.LoadOrStore(nodeName, &nodeOperations{})
+		operations := value.(*nodeOperations)
+		operations.mu.Lock()
+		if operations.removed {
+			operations.mu.Unlock()
+			continue
+		}
+		retryAfter, ok := operations.allow(limit, window, now, record)
+		operations.mu.Unlock()
+		return retryAfter, ok
+	}
+}
+
+// allow checks and records an operation of the node. The caller holds the lock of the operations.
+func (o *nodeOperations) allow(limit int, window time.Duration, now time.Time, record bool) (time.Duration, bool) {
+	if len(o.times) != limit {
+		o.resize(limit)
+	}
+	o.window = window
+
+	// The next slot holds the oldest of the latest limit operations, or the zero time if there are fewer.
+	if oldest := o.times[o.next]; oldest.After(now.Add(-window)) {
+		return oldest.Add(window).Sub(now), false
+	}
+	if record {
+		o.times[o.next] = now
+		o.next = (o.next + 1) % limit
+	}
+	return 0, true
+}
+
+// resize changes the capacity of the ring buffer when the limit of the policy changes, keeping the latest operations.
+func (o *nodeOperations) resize(limit int) {
+	times := make([]time.Time, limit)
+	for i := range min(len(o.times), limit) {
+		// Copy the latest operations backwards, so that the oldest kept one ends up in the next slot.
+		times[limit-1-i] = o.times[(o.next-1-i+len(o.times))%len(o.times)]
+	}
+	o.times = times
+	o.next = 0
+}
+
+// latest returns the time of the latest operation of the node. The caller holds the lock of the operations.
+func (o *nodeOperations) latest() time.Time {
+	if len(o.times) == 0 {
+		return time.Time{}
+	}
+	return o.times[(o.next-1+len(o.times))%len(o.times)]
+}
+
+// prune drops the nodes without an operation within their own window, at most once per the given window,
+// to bound the memory used by the tracker.
+func (t *nodeVelocityTracker) prune(window time.Duration, now time.Time) {
+	t.pruneMu.Lock()
+	if now.Sub(t.lastPrune) < window {
+		t.pruneMu.Unlock()
+		return
+	}
+	t.lastPrune = now
+	t.pruneMu.Unlock()
+
+	t.nodes.Range(func(key, value any) bool {
+		operations := value.(*nodeOperations)
+		operations.mu.Lock()
+		defer operations.mu.Unlock()
+		if !operations.latest().After(now.Add(-operations.window)) {
+			operations.removed = true
+			t.nodes.CompareAndDelete(key, operations)
+		}
+		return true
+	})
+}
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestNodeVelocity(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", nodeVelocityLimitKey: "3", nodeVelocityWindowKey: "60"},
+	})).Should(Succeed())
+	clock := &fakeClock{now: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)}
+	nv := &NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: clock}
+
+	cordoned := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: map[string]string{reasonAnnotation: "Testing"}}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	uncordoned := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	cordon := newUpdateRequest(g, regularUserExample, uncordoned, cordoned)
+	uncordon := newUpdateRequest(g, regularUserExample, cordoned, uncordoned)
+
+	for _, request := range []admission.Request{cordon, uncordon, cordon} {
+		g.Expect(nv.Handle(ctx, request).Allowed).Should(BeTrue())
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+
+	response := nv.Handle(ctx, uncordon)
+	detail := denialDetail(g, response)
+	g.Expect(detail.Code).Should(Equal(NodeVelocityExceededCode))
+	g.Expect(detail.Message).Should(ContainSubstring("retry after 30 seconds"))
+	g.Expect(response.Result.Details.RetryAfterSeconds).Should(Equal(int32(30)))
+	g.Expect(nv.Handle(ctx, newCordonRequest(g, "node-2", regularUserExample, map[string]string{reasonAnnotation: "Testing"})).Allowed).Should(BeTrue())
+
+	clock.now = clock.now.Add(31 * time.Second)
+	g.Expect(nv.Handle(ctx, uncordon).Allowed).Should(BeTrue())
+}
+
+func TestNodeVelocityTracker(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	t.Run("DryRunNotRecorded", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := nodeVelocityTracker{}
+		for range 5 {
+			_, ok := tracker.allow("node-1", 1, window, now, false)
+			g.Expect(ok).Should(BeTrue())
+		}
+	})
+
+	t.Run("LimitChanged", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := nodeVelocityTracker{}
+		for i := range 3 {
+			_, ok := tracker.allow("node-1", 3, window, now.Add(time.Duration(i)*time.Second), true)
+			g.Expect(ok).Should(BeTrue())
+		}
+
+		retryAfter, ok := tracker.allow("node-1", 2, window, now.Add(3*time.Second), true)
+		g.Expect(ok).Should(BeFalse())
+		g.Expect(retryAfter).Should(Equal(window - 2*time.Second))
+		_, ok = tracker.allow("node-1", 5, window, now.Add(3*time.Second), true)
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("StaleNodesDropped", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := nodeVelocityTracker{}
+		for i := range 10 {
+			tracker.allow(fmt.Sprintf("node-%d", i), 3, window, now, true)
+		}
+
+		tracker.allow("active", 3, window, now.Add(2*window), true)
+		nodes := 0
+		tracker.nodes.Range(func(any, any) bool {
+			nodes++
+			return true
+		})
+		g.Expect(nodes).Should(Equal(1))
+	})
+}
+
+func TestNodeVelocityTrackerConcurrency(t *testing.T) {
+	g := NewWithT(t)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	tracker := nodeVelocityTracker{}
+	var allowed atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The operations on the other nodes prune the tracker concurrently.
+			tracker.allow(fmt.Sprintf("node-%d", i), 5, time.Second, now.Add(time.Duration(i)*time.Second), true)
+			if _, ok := tracker.allow("shared", 5, time.Hour, now, true); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	g.Expect(allowed.Load()).Should(Equal(int32(5)))
+}
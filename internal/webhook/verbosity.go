@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ResponseVerbosity is the verbosity of the denial messages returned by the webhook.
+type ResponseVerbosity string
+
+const (
+	// CompactVerbosity returns one-line messages made of the operation and the denial code, for machine consumers.
+	CompactVerbosity ResponseVerbosity = "compact"
+	// StandardVerbosity returns the denial messages as they are.
+	StandardVerbosity ResponseVerbosity = "standard"
+	// VerboseVerbosity adds an explanation of the policy, a hint on annotating the node and the allowed reasons, for humans.
+	VerboseVerbosity ResponseVerbosity = "verbose"
+)
+
+// parseResponseVerbosity parses a response verbosity, which defaults to standard.
+func parseResponseVerbosity(value string) (ResponseVerbosity, error) {
+	switch verbosity := ResponseVerbosity(value); verbosity {
+	case "":
+		return StandardVerbosity, nil
+	case CompactVerbosity, StandardVerbosity, VerboseVerbosity:
+		return verbosity, nil
+	default:
+		return "", fmt.Errorf("unknown verbosity %q, expected one of %q, %q or %q", value, CompactVerbosity, StandardVerbosity, VerboseVerbosity)
+	}
+}
+
+// withResponseVerbosity rewrites the message of a denial on the node according to the verbosity of the policy.
+// Other responses are returned as they are.
+func withResponseVerbosity(response admission.Response, node *corev1.Node, policy Policy) admission.Response {
+	if policy.ResponseVerbosity == "" || policy.ResponseVerbosity == StandardVerbosity ||
+		!isDenied(response) {
+		return response
+	}
+
+	detail := DenialDetail{}
+	if err := json.Unmarshal([]byte(response.Result.Message), &detail); err != nil {
+		return response
+	}
+	switch policy.ResponseVerbosity {
+	case CompactVerbosity:
+		detail.AllowedReasons, detail.Pattern = nil, ""
+		detail.Message = fmt.Sprintf("%s denied: %s", detail.Operation, detail.Code)
+
+	case VerboseVerbosity:
+		detail.Message = verboseDenialMessage(detail, node, policy)
+	}
+
+	denial := deniedWithDetail(detail)
+	response.Result.Reason, response.Result.Message = denial.Result.Reason, denial.Result.Message
+	return response
+}
+
+// verboseDenialMessage returns the message of the denial followed by an explanation of the reason policy,
+// the command annotating the node with a reason, and the allowed reasons.
+func verboseDenialMessage(detail DenialDetail, node *corev1.Node, policy Policy) string {
+	lines := []string{
+		detail.Message,
+		fmt.Sprintf("Policy: cordoning, draining, tainting and deleting a node require the %q annotation to hold the reason of the operation, "+
+			"while uncordoning and untainting a node require it to be removed. Forbidden users can't perform any of these operations.", ReasonAnnotationKey()),
+		fmt.Sprintf("Hint: kubectl annotate node %s %s=\"<reason>\" --overwrite", node.Name, ReasonAnnotationKey()),
+	}
+
+	switch {
+	case policy.AllowFreetextReason:
+		lines = append(lines, "Allowed reasons: any non-empty reason")
+	case len(policy.AllowedReasons) > 0 && policy.ReasonRegexPattern != "":
+		lines = append(lines, fmt.Sprintf("Allowed reasons: %s, or reasons matching %q", strings.Join(policy.AllowedReasons, ", "), policy.ReasonRegexPattern))
+	case len(policy.AllowedReasons) > 0:
+		lines = append(lines, fmt.Sprintf("Allowed reasons: %s", strings.Join(policy.AllowedReasons, ", ")))
+	case policy.ReasonRegexPattern != "":
+		lines = append(lines, fmt.Sprintf("Allowed reasons: reasons matching %q", policy.ReasonRegexPattern))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestResponseVerbosity(t *testing.T) {
+	tests := []struct {
+		name        string
+		verbosity   string
+		annotations map[string]string
+		message     string
+		contains    []string
+	}{
+		{name: "Compact", verbosity: "compact", annotations: map[string]string{}, message: "cordon denied: MissingReason"},
+		{name: "CompactInvalidReason", verbosity: "compact", annotations: map[string]string{reasonAnnotation: "for fun"}, message: "cordon denied: InvalidReason"},
+		{name: "Standard", verbosity: "standard", annotations: map[string]string{}, message: `You must add "node.dana.io/reason" annotation`},
+		{name: "Default", verbosity: "", annotations: map[string]string{}, message: `You must add "node.dana.io/reason" annotation`},
+		{name: "Verbose", verbosity: "verbose", annotations: map[string]string{}, contains: []string{
+			`You must add "node.dana.io/reason" annotation`,
+			"Policy: ",
+			`kubectl annotate node Verbose node.dana.io/reason="<reason>" --overwrite`,
+			"Allowed reasons: Testing, Maintenance",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			data := map[string]string{allowedReasonsKey: "Testing,Maintenance"}
+			if test.verbosity != "" {
+				data[responseVerbosityKey] = test.verbosity
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(BeFalse())
+			detail := DenialDetail{}
+			g.Expect(json.Unmarshal([]byte(response.Result.Message), &detail)).Should(Succeed())
+			g.Expect(string(response.Result.Reason)).Should(Equal(detail.Message))
+			if test.message != "" {
+				g.Expect(detail.Message).Should(Equal(test.message))
+			}
+			for _, substring := range test.contains {
+				g.Expect(detail.Message).Should(ContainSubstring(substring))
+			}
+		})
+	}
+}
+
+func TestParseResponseVerbosity(t *testing.T) {
+	g := NewWithT(t)
+	verbosity, err := parseResponseVerbosity("")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(verbosity).Should(Equal(StandardVerbosity))
+
+	verbosity, err = parseResponseVerbosity("verbose")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(verbosity).Should(Equal(VerboseVerbosity))
+
+	_, err = parseResponseVerbosity("chatty")
+	g.Expect(err).Should(HaveOccurred())
+}
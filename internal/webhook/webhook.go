@@ -2,16 +2,25 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -20,126 +29,703 @@ import (
 type NodeValidator struct {
 	Decoder admission.Decoder
 	Client  client.Client
+	// APIReader reads from the API server rather than from a cache, for ValidateConfig to work before the cache
+	// is started. Defaults to Client.
+	APIReader client.Reader
+	// PolicyResolver resolves the policy applying to a node. Defaults to a CRDPolicyResolver
+	// falling back to a ConfigMapPolicyResolver.
+	PolicyResolver PolicyResolver
+	// PolicyClient lists the NodeOperationPolicy objects for the default PolicyResolver.
+	// Defaults to a KubernetesPolicyClient using Client.
+	PolicyClient PolicyClient
+	// Clock provides the current time. Defaults to the system clock.
+	Clock Clock
+	// Recorder records the decisions as events on the nodes. No events are recorded if it is nil.
+	Recorder record.EventRecorder
+	// PolicyEvaluator decides on the operations once they passed the other checks. Defaults to a DefaultPolicyEvaluator.
+	PolicyEvaluator PolicyEvaluator
+	// HTTPClient is used for the requests to external services, such as Jira. Defaults to a client with a timeout.
+	HTTPClient *http.Client
+	// RateLimiterBackend stores the recent operations of the users. Defaults to an in-memory backend,
+	// which is only accurate when the webhook runs with a single replica.
+	RateLimiterBackend RateLimiterBackend
+	// GroupResolver resolves the groups of the users whose requests have no groups. Defaults to a
+	// NoopGroupResolver, using the groups of the requests only.
+	GroupResolver UserGroupResolver
+	// GroupResolverCacheTTL is the time the resolved groups are cached for. Defaults to 5 minutes.
+	GroupResolverCacheTTL time.Duration
+	// TraceIDGenerator generates the correlation IDs logged with the logs of the requests. Defaults to a UIDTraceIDGenerator.
+	TraceIDGenerator TraceIDGenerator
+	// DryRun allows the operations which would be denied, recording the denials as events prefixed with "DryRun:".
+	DryRun bool
+
+	denials          denialTracker
+	decisions        decisionStats
+	usedBypassTokens bypassTokenTracker
+	rateLimits       memoryRateLimiterBackend
+	nodeVelocity     nodeVelocityTracker
+	groupCache       groupCache
+	bulkCordons      bulkCordonTracker
+	customCAClients  customCAClient
+	auditBatches     auditBatcher
+	reasonCleanups   reasonCleanupTracker
+	// appliedPolicySettings are the process-wide settings of the last resolved policy.
+	appliedPolicySettings policySettings
+	// additionalLoggers receive the logs of the validator along with the logger of the request context.
+	additionalLoggers []logr.Logger
+}
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// now returns the current time according to the clock of the validator.
+func (n *NodeValidator) now() time.Time {
+	if n.Clock == nil {
+		return time.Now()
+	}
+	return n.Clock.Now()
 }
 
 // Operation represents the type of operation being performed
 type Operation string
 
 const (
-	reasonAnnotation             = "node.dana.io/reason"
-	serviceAccountUser           = "system:serviceaccount:"
-	systemAdminUser              = "system:admin"
-	ForbiddenUsersEnv            = "forbiddenUsers"
-	Create             Operation = "create"
-	Delete             Operation = "delete"
-	Cordon             Operation = "cordon"
-	Uncordon           Operation = "uncordon"
-	cmName                       = "node-operation-validator-config"
-	cmNamespace                  = "node-operation-validator-system"
+	reasonAnnotation                  = "node.dana.io/reason"
+	serviceAccountUser                = "system:serviceaccount:"
+	systemAdminUser                   = "system:admin"
+	ForbiddenUsersEnv                 = "forbiddenUsers"
+	DryRunEnv                         = "DRY_RUN"
+	AutoCreateConfigEnv               = "AUTO_CREATE_CONFIG"
+	ReasonAnnotationKeyEnv            = "REASON_ANNOTATION_KEY"
+	DisableEventEmissionEnv           = "DISABLE_EVENT_EMISSION"
+	ConfigSourceEnv                   = "CONFIG_SOURCE"
+	Create                  Operation = "create"
+	Delete                  Operation = "delete"
+	Cordon                  Operation = "cordon"
+	Uncordon                Operation = "uncordon"
+	TaintAdd                Operation = "taint"
+	TaintRemove             Operation = "untaint"
+	Drain                   Operation = "drain"
+	StatusPatch             Operation = "statuspatch"
+	cmName                            = "node-operation-validator-config"
+	cmNamespace                       = "node-operation-validator-system"
 )
 
-// +kubebuilder:webhook:path=/validate-v1-node,mutating=false,failurePolicy=ignore,sideEffects=None,groups=core,resources=nodes,verbs=delete;create;update,versions=v1,name=nodeoperation.dana.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-v1-node,mutating=false,failurePolicy=ignore,sideEffects=None,groups=core,resources=nodes;nodes/status,verbs=delete;create;update,versions=v1,name=nodeoperation.dana.io,admissionReviewVersions=v1
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
 
+// Handle validates the request. A server-side dry run request has no side effects: no events are recorded,
+// and neither the denials, the emergency bypass tokens nor the rate limited operations are tracked.
 func (n *NodeValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	start := time.Now()
+	ctx = log.IntoContext(ctx, n.logger(ctx).WithValues("traceID", n.traceID(req)))
+	response := n.handle(ctx, req, isDryRunRequest(req))
+	if n.DryRun && isDenied(response) {
+		log.FromContext(ctx).WithName("Node Webhook").Info("Denial allowed in dry run mode", "node", req.Name, "User", req.UserInfo.Username)
+		response = dryRunResponse(decisionMessage(response))
+	}
+	admissionLatency.observe(time.Since(start), response.Allowed)
+	return response
+}
+
+// isDryRunRequest returns true if the request is a server-side dry run, e.g. of kubectl --dry-run=server,
+// whose changes aren't persisted.
+func isDryRunRequest(req admission.Request) bool {
+	return req.DryRun != nil && *req.DryRun
+}
+
+// DryRunHandle returns the decision Handle would make on the request, without any side effects:
+// no events are recorded, neither the denials, the emergency bypass tokens nor the rate limited operations are tracked,
+// and no external API such as Jira is called.
+// An error is returned instead of a decision if the request couldn't be validated.
+func (n *NodeValidator) DryRunHandle(ctx context.Context, req admission.Request) (allowed bool, reason string, err error) {
+	response := n.handle(log.IntoContext(ctx, n.logger(ctx).WithValues("traceID", n.traceID(req))), req, true)
+	if !response.Allowed && !isDenied(response) {
+		return false, "", errors.New(response.Result.Message)
+	}
+	return response.Allowed, decisionMessage(response), nil
+}
+
+// handle validates the request. In dry run mode, the validation has no side effects.
+func (n *NodeValidator) handle(ctx context.Context, req admission.Request, dryRun bool) admission.Response {
+	injectChaosLatency(ctx)
 	logger := log.FromContext(ctx).WithName("Node Webhook").WithValues("node", req.Name)
 
 	node := corev1.Node{}
 	oldNode := corev1.Node{}
 	user := req.UserInfo.Username
-
-	forbiddenUsers := strings.Split(os.Getenv(ForbiddenUsersEnv), ",")
-	forbiddenUsers = append(forbiddenUsers, systemAdminUser)
-
-	allowedReasons, err := n.getAllowedReasons(ctx, cmNamespace, logger)
-	if err != nil {
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to fetch allowed reasons: %w", err))
-	}
+	uid := req.UserInfo.UID
+	groups := req.UserInfo.Groups
+	ctx = withRequestUser(ctx, user)
 
 	switch req.Operation {
 	case admissionv1.Delete:
 		if err := n.Decoder.DecodeRaw(req.OldObject, &node); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
 		}
-		reasonMessage, doesReasonExist := node.Annotations[reasonAnnotation]
-		return userOnlyOperation(Delete, user, forbiddenUsers, reasonMessage, logger, true, doesReasonExist, allowedReasons)
+		policy, err := n.resolvePolicy(ctx, &node, logger, dryRun)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve policy: %w", err))
+		}
+		groups = n.resolveGroups(ctx, user, groups, logger)
+		return withResponseVerbosity(withDenialMessageTemplate(n.validateWithEnforcementMode(ctx, Delete, &node, user, uid, groups, policy, logger, true, dryRun), &node, policy), &node, policy)
 
 	case admissionv1.Create:
 		if err := n.Decoder.DecodeRaw(req.Object, &node); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
 		}
-		_, doesReasonExist := node.Annotations[reasonAnnotation]
-		return validateNoReason(doesReasonExist, logger, Create, user)
+		_, doesReasonExist := node.Annotations[ReasonAnnotationKey()]
+		return validateNoReason(doesReasonExist, logger, Create, node.Name, user)
 
 	// The default case handles the update requests.
 	default:
-		if err := n.Decoder.DecodeRaw(req.OldObject, &node); err != nil {
+		if req.SubResource == statusSubResource {
+			return n.handleStatusPatch(ctx, req, logger, dryRun)
+		}
+		if err := n.Decoder.DecodeRaw(req.OldObject, &oldNode); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
 		}
 		if err := n.Decoder.DecodeRaw(req.Object, &node); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode node %q", req.Name))
 		}
-		reasonMessage, doesReasonExist := node.Annotations[reasonAnnotation]
 
-		switch {
-		case !oldNode.Spec.Unschedulable && node.Spec.Unschedulable:
-			return userOnlyOperation(Cordon, user, forbiddenUsers, reasonMessage, logger, true, doesReasonExist, allowedReasons)
+		if oldNode.Spec.Unschedulable == node.Spec.Unschedulable && apiequality.Semantic.DeepEqual(oldNode.Spec.Taints, node.Spec.Taints) {
+			return admission.Allowed("Node was updated")
+		}
 
-		case oldNode.Spec.Unschedulable && !node.Spec.Unschedulable:
-			return userOnlyOperation(Uncordon, user, forbiddenUsers, reasonMessage, logger, false, doesReasonExist, allowedReasons)
+		policy, err := n.resolvePolicy(ctx, &node, logger, dryRun)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve policy: %w", err))
+		}
 
-		default:
+		operation, isReasonRequired := updateOperation(&oldNode, &node, policy)
+		if operation == "" {
 			return admission.Allowed("Node was updated")
 		}
+		if operation == Drain {
+			policy = drainPolicy(policy)
+		}
+		groups = n.resolveGroups(ctx, user, groups, logger)
+		return withResponseVerbosity(withDenialMessageTemplate(n.validateWithEnforcementMode(ctx, operation, &node, user, uid, groups, policy, logger, isReasonRequired, dryRun), &node, policy), &node, policy)
+	}
+}
+
+// updateOperation returns the operation performed by the update of a node, and whether it requires a reason.
+// The operation is empty if the update isn't an operation.
+func updateOperation(oldNode *corev1.Node, node *corev1.Node, policy Policy) (Operation, bool) {
+	switch {
+	case !oldNode.Spec.Unschedulable && node.Spec.Unschedulable:
+		if isDrainOperation(oldNode, node) {
+			return Drain, true
+		}
+		return Cordon, true
+
+	case oldNode.Spec.Unschedulable && !node.Spec.Unschedulable:
+		return Uncordon, false
+	}
+
+	added, removed := monitoredTaintChanges(oldNode.Spec.Taints, node.Spec.Taints, policy.MonitoredTaints)
+	switch {
+	case added:
+		return TaintAdd, true
+
+	case removed:
+		return TaintRemove, false
+
+	default:
+		return "", false
+	}
+}
+
+// NewPolicyResolver returns the default resolver of the policy, reading the NodeOperationPolicies and the policy
+// ConfigMaps with the client: a CRDPolicyResolver falling back to a ConfigMapPolicyResolver.
+func NewPolicyResolver(c client.Client) PolicyResolver {
+	return policyResolver(nil, nil, c)
+}
+
+// policyResolver returns the given resolver, or the default one if it is nil: a CRDPolicyResolver
+// falling back to a ConfigMapPolicyResolver.
+func policyResolver(resolver PolicyResolver, policyClient PolicyClient, c client.Client) PolicyResolver {
+	if resolver != nil {
+		return resolver
+	}
+	if policyClient == nil {
+		policyClient = &KubernetesPolicyClient{Client: c}
+	}
+	return &CRDPolicyResolver{
+		PolicyClient: policyClient,
+		Fallback:     &ConfigMapPolicyResolver{Client: c, Namespace: cmNamespace},
+	}
+}
+
+// validateOperation validates a user operation on a node against the policy,
+// and records the decision as an event on the node unless in dry run mode.
+func (n *NodeValidator) validateOperation(ctx context.Context, operation Operation, node *corev1.Node, user string, uid string, groups []string, policy Policy, log logr.Logger, isReasonRequired bool, dryRun bool) admission.Response {
+	if !dryRun && policy.ReasonAnnotationAutoDeleteAfter > 0 {
+		n.reasonCleanups.begin(node.Name)
+		defer n.reasonCleanups.end(node.Name)
+	}
+	if response, ok := n.bypassNode(operation, node, user, policy, log, dryRun); ok {
+		return response
+	}
+	if response, ok := n.emergencyBypass(ctx, operation, node, user, groups, policy, log, dryRun); ok {
+		return response
+	}
+	if response, ok := n.checkRateLimit(ctx, operation, node.Name, user, uid, policy, log, dryRun); !ok {
+		if !dryRun {
+			n.recordDecision(node, operation, user, "", policy, response)
+		}
+		return response
+	}
+	if response, ok := n.checkNodeVelocity(operation, node.Name, user, policy, log, dryRun); !ok {
+		if !dryRun {
+			n.recordDecision(node, operation, user, "", policy, response)
+		}
+		return response
+	}
+	if response, ok := n.checkRiskScore(ctx, operation, node.Name, user, policy, log, dryRun); !ok {
+		if !dryRun {
+			n.recordDecision(node, operation, user, "", policy, response)
+		}
+		return response
+	}
+
+	reasonMessage, doesReasonExist, err := resolveReason(ctx, node, n.Client)
+	if err != nil {
+		log.Error(err, "Failed to resolve the reason")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to resolve the reason: %w", err))
+	}
+	if response, ok := n.checkOperationWindow(operation, node, user, groups, policy, log); !ok {
+		if !dryRun {
+			n.recordDecision(node, operation, user, reasonMessage, policy, response)
+		}
+		return response
+	}
+	if response, ok := n.checkCordonCooloff(operation, node, user, policy, log); !ok {
+		if !dryRun {
+			n.recordDecision(node, operation, user, reasonMessage, policy, response)
+		}
+		return response
+	}
+	if isReasonRequired && !isForbidden(user, groups, policy) {
+		if response, ok := n.skipReasonForPodCount(ctx, operation, node, user, policy, log); ok {
+			if !dryRun {
+				n.recordDecision(node, operation, user, "", policy, response)
+			}
+			return response
+		}
+	}
+	var maintenanceWarning string
+	if isReasonRequired && !doesReasonExist && !isForbidden(user, groups, policy) {
+		var response admission.Response
+		var ok bool
+		if response, ok, maintenanceWarning = n.approveScheduledMaintenance(ctx, operation, node, user, policy, log, dryRun); ok {
+			return response
+		}
+	}
+	defaultReason, hasDefaultReason := policy.DefaultReasons[operation]
+	useDefaultReason := !doesReasonExist && isReasonRequired && policy.AllowFreetextReason && hasDefaultReason
+	if useDefaultReason {
+		reasonMessage, doesReasonExist = defaultReason, true
+	}
+
+	response := n.handleUserOperation(ctx, operation, node.Name, user, groups, policy, reasonMessage, log, isReasonRequired, doesReasonExist, dryRun)
+	category, hasCategory := node.Annotations[reasonCategoryAnnotation]
+	if isReasonRequired && hasCategory {
+		response = validateReasonCategory(operation, node.Name, user, category, policy, log, response)
+	}
+	if isReasonRequired && !useDefaultReason && policy.RequireReasonOwnership && !isServiceAccount(user, policy.serviceAccountPrefix()) {
+		response = validateReasonOwnership(operation, node, user, policy, log, response)
+	}
+	if isReasonRequired {
+		response = validateOperationPriority(operation, node.Name, user, getOperationPriority(node), policy, log, response)
+	}
+	response = n.validateApproval(ctx, operation, node, user, reasonMessage, policy, log, isReasonRequired, dryRun, response)
+	bulkCordon := false
+	if operation == Cordon {
+		response, bulkCordon = n.validateBulkCordon(node, user, policy, log, dryRun, response)
+	}
+	if isReasonRequired && hasCategory {
+		response = withReasonCategory(response, category)
+	}
+	if useDefaultReason && response.Allowed {
+		log.Info("Default reason used", "Operation", operation, "User", user, "Reason", reasonMessage)
+		response.Warnings = append(response.Warnings, fmt.Sprintf("The %q annotation is missing, so the default reason %q was used", ReasonAnnotationKey(), reasonMessage))
+	}
+	if maintenanceWarning != "" {
+		response.Warnings = append(response.Warnings, maintenanceWarning)
+	}
+	switch {
+	case dryRun:
+	case bulkCordon && response.Allowed:
+		n.recordBulkCordonApproval(node, user, reasonMessage, policy, response)
+	default:
+		n.recordDecision(node, operation, user, reasonMessage, policy, response)
+	}
+	if !dryRun && response.Allowed && (operation == Cordon || operation == Drain || operation == Delete) {
+		n.scheduleReasonCleanup(node, policy)
+	}
+	return response
+}
+
+// resolvePolicy returns the policy that applies to the given node. The forbidden users
+// fall back to the environment variable when the policy doesn't define any, and
+// the system admin user is always forbidden. An invalid reason pattern is an error.
+// Unless in dry run mode, the process-wide settings of the policy are applied.
+func (n *NodeValidator) resolvePolicy(ctx context.Context, node *corev1.Node, logger logr.Logger, dryRun bool) (Policy, error) {
+	policy, err := policyResolver(n.PolicyResolver, n.PolicyClient, n.Client).Resolve(ctx, node)
+	if err != nil {
+		logger.Error(err, "Failed to resolve policy")
+		return Policy{}, err
+	}
+	for _, pattern := range []string{policy.ReasonRegexPattern, policy.DrainReasonRegexPattern} {
+		if _, err := compilePattern(pattern); err != nil {
+			logger.Error(err, "Invalid reason pattern", "Pattern", pattern)
+			return Policy{}, fmt.Errorf("invalid reason pattern %q: %w", pattern, err)
+		}
+	}
+	if !dryRun && n.appliedPolicySettings.changed(policy) {
+		warnMisspelledReasons(policy.AllowedReasons, logger)
+		if err := admissionLatency.configure(policy.LatencyBuckets); err != nil {
+			logger.Error(err, "Failed to configure the admission latency buckets")
+		}
+	}
+
+	policy.ForbiddenUsers = effectiveForbiddenUsers(policy.ForbiddenUsers)
+	return policy, nil
+}
+
+// policySettings are the settings of the last applied policy which apply to the whole process, such as the buckets
+// of the admission latency histogram, so that they are only applied again when the resolved policy changes them.
+// Its zero value is ready to use.
+type policySettings struct {
+	mu             sync.Mutex
+	applied        bool
+	allowedReasons []string
+	latencyBuckets HistogramBucketConfig
+}
+
+// changed records the settings of the policy, and returns true if they differ from the last recorded ones.
+func (s *policySettings) changed(policy Policy) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.applied && slices.Equal(s.allowedReasons, policy.AllowedReasons) && slices.Equal(s.latencyBuckets, policy.LatencyBuckets) {
+		return false
+	}
+	s.applied, s.allowedReasons, s.latencyBuckets = true, policy.AllowedReasons, policy.LatencyBuckets
+	return true
+}
+
+// ReasonAnnotationKey returns the key of the reason annotation, which the REASON_ANNOTATION_KEY environment variable
+// overrides for organizations already using another annotation, e.g. ops.company.io/change-ticket.
+func ReasonAnnotationKey() string {
+	if key := os.Getenv(ReasonAnnotationKeyEnv); key != "" {
+		return key
+	}
+	return reasonAnnotation
+}
+
+// effectiveForbiddenUsers returns the forbidden users of the environment variable and of the policy,
+// along with the system admin user, which is always forbidden.
+func effectiveForbiddenUsers(policyForbiddenUsers []string) []string {
+	return append(getForbiddenUsers(os.Getenv(ForbiddenUsersEnv), strings.Join(policyForbiddenUsers, ",")), systemAdminUser)
+}
+
+// handleUserOperation validates a user operation on a node with the policy evaluator of the validator. Operations
+// requiring a reason are denied outside of the maintenance windows of the policy. An operation which was denied because its reason is missing is approved when
+// re-submitted with the same reason within the denial grace period of the operation. The other denials aren't recorded, so that they
+// can't be bypassed by re-submitting the operation.
+// In dry run mode, the denials are neither consumed nor recorded.
+func (n *NodeValidator) handleUserOperation(ctx context.Context, operation Operation, nodeName string, user string, groups []string, policy Policy, reasonMessage string, log logr.Logger, isReasonRequired bool, doesReasonExist bool, dryRun bool) admission.Response {
+	if isReasonRequired && len(policy.MaintenanceWindows) > 0 && !isForbidden(user, groups, policy) && !isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		if now := n.now(); !isWithinMaintenanceWindow(policy.MaintenanceWindows, now) {
+			window, start := nextMaintenanceWindow(policy.MaintenanceWindows, now)
+			logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: OutsideMaintenanceWindowCode, Reason: reasonMessage, Grounds: "outside of maintenance windows"})
+			return denied(policy, DenialDetail{
+				Code:      OutsideMaintenanceWindowCode,
+				Operation: operation,
+				User:      user,
+				Message:   fmt.Sprintf("%s operation is only allowed during maintenance windows. The next window %q starts at %s", operation, window, start.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	evaluation := EvaluationRequest{Node: nodeName, User: user, Groups: groups, Operation: operation, Reason: reasonMessage,
+		ReasonRequired: isReasonRequired, ReasonExists: doesReasonExist, Policy: policy}
+	gracePeriod := policy.DenialGracePeriods[operation]
+	if gracePeriod <= 0 || isForbidden(user, groups, policy) {
+		return n.evaluateOperation(ctx, evaluation, log, dryRun)
+	}
+
+	key := denialKey{user: user, node: nodeName, operation: operation}
+	if (dryRun && n.denials.matches(key, reasonMessage, n.now())) || (!dryRun && n.denials.consume(key, reasonMessage, n.now())) {
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionAllowed, Reason: reasonMessage, Grounds: "re-submitted within the denial grace period"})
+		return admission.Allowed(fmt.Sprintf("%s operation has been approved within the denial grace period", operation))
 	}
+
+	response := n.evaluateOperation(ctx, evaluation, log, dryRun)
+	if !dryRun && isMissingReasonDenial(response) {
+		n.denials.record(key, reasonMessage, gracePeriod, n.now())
+	}
+	return response
 }
 
 // userOnlyOperation checks whether a given user is allowed to perform a specific operation on a node.
 // It returns an admission response indicating whether the operation is allowed or denied.
-func userOnlyOperation(operation Operation, user string, forbiddenUsers []string, reasonMessage string, log logr.Logger, isReasonRequired bool, doesReasonExist bool, allowedReasons []string) admission.Response {
-	switch {
-	case isForbiddenUser(user, forbiddenUsers):
-		log.Info(fmt.Sprintf("%s node denied", operation), "DenialReason", "forbidden user", "User", user)
-		return admission.Denied(fmt.Sprintf("%q user is not allowed to %s a node. Please log in with a LDAP privileged user. You must also add %q annotation", user, operation, reasonAnnotation))
+// When the policy is in warn-only mode, denials are turned into allowed responses carrying a warning.
+func userOnlyOperation(operation Operation, nodeName string, user string, groups []string, policy Policy, reasonMessage string, log logr.Logger, isReasonRequired bool, doesReasonExist bool) admission.Response {
+	response := checkUserOperation(operation, nodeName, user, groups, policy, reasonMessage, log, isReasonRequired, doesReasonExist)
+	return warnOnlyDenial(operation, nodeName, user, policy, reasonMessage, log, response)
+}
+
+// warnOnlyDenial turns a denial into an allowed response carrying a warning when the policy is in warn-only mode.
+// The other responses are returned as is.
+func warnOnlyDenial(operation Operation, nodeName string, user string, policy Policy, reasonMessage string, log logr.Logger, response admission.Response) admission.Response {
+	if !policy.WarnOnly || response.Allowed {
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionAllowed, Reason: reasonMessage, Grounds: "warn only mode"})
+	return warnOnlyResponse(string(response.Result.Reason))
+}
+
+// validateApproval runs the checks which depend on the state of the cluster on an approved operation.
+// A denied response is returned as is.
+func (n *NodeValidator) validateApproval(ctx context.Context, operation Operation, node *corev1.Node, user string, reason string, policy Policy, log logr.Logger, isReasonRequired bool, dryRun bool, response admission.Response) admission.Response {
+	if !response.Allowed {
+		return response
+	}
+
+	if isReasonRequired && policy.RequireReasonAttestation && !isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		if response = n.validateReasonAttestation(ctx, node, user, policy, log, response); !response.Allowed {
+			return response
+		}
+	}
+	if isReasonRequired && policy.TicketValidationURL != "" && !isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) {
+		if response = n.validateTicket(ctx, operation, node.Name, user, reason, policy, log, dryRun, response); !response.Allowed {
+			return response
+		}
+	}
+	if operation == Cordon || operation == Drain {
+		response = n.validateZoneCordonLimit(ctx, operation, node, user, policy, log, response)
+	}
+	if (operation == Cordon || operation == Drain) && response.Allowed {
+		response = n.validateCordonBudgets(ctx, operation, node, user, policy, log, response)
+	}
+	if operation == Delete && policy.SimulateDrainOnDelete {
+		response = n.validateDrainSimulation(ctx, node, user, policy, log, dryRun, response)
+	}
+	if operation == Delete && policy.CheckResourceQuotaOnDelete {
+		response = n.validateRemainingCapacity(ctx, node, user, policy, log, response)
+	}
+	return response
+}
+
+// denyApproved turns an approved response into a denial with the given detail. In warn only mode,
+// the message of the detail is added to the warnings of the approved response instead.
+func denyApproved(policy Policy, response admission.Response, detail DenialDetail) admission.Response {
+	if policy.WarnOnly {
+		response.Warnings = append(response.Warnings, detail.Message)
+		return response
+	}
+	return deniedWithDetail(detail)
+}
+
+// denied returns a denial response with the given detail, or an allowed response carrying
+// the message of the detail as a warning when the policy is in warn-only mode.
+func denied(policy Policy, detail DenialDetail) admission.Response {
+	if policy.WarnOnly {
+		return warnOnlyResponse(detail.Message)
+	}
+	return deniedWithDetail(detail)
+}
+
+// warnOnlyResponse returns an allowed admission response carrying the denial message as a warning.
+func warnOnlyResponse(denialMessage string) admission.Response {
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: true,
+			Result: &metav1.Status{
+				Code:    http.StatusOK,
+				Message: "Operation approved in warn only mode",
+			},
+			Warnings: []string{denialMessage},
+		},
+	}
+}
+
+// dryRunResponse returns an allowed admission response carrying the denial message as a warning.
+func dryRunResponse(denialMessage string) admission.Response {
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: true,
+			Result: &metav1.Status{
+				Code:    http.StatusOK,
+				Message: "Operation approved in dry run mode",
+			},
+			Warnings: []string{denialMessage},
+		},
+	}
+}
+
+// checkUserOperation validates the user and the reason of an operation against the policy.
+func checkUserOperation(operation Operation, nodeName string, user string, groups []string, policy Policy, reasonMessage string, log logr.Logger, isReasonRequired bool, doesReasonExist bool) admission.Response {
+	if response, isDenied := checkUserAccess(operation, nodeName, user, groups, policy, reasonMessage, log); isDenied {
+		return response
+	}
 
-	case isServiceAccount(user):
-		log.Info(fmt.Sprintf("%s node approved", operation), "User", user, "ApprovalReason", "Service account is allowed to do any operation")
+	switch {
+	case isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces):
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionAllowed, Reason: reasonMessage, Grounds: "service account is allowed to do any operation"})
 		return admission.Allowed(fmt.Sprintf("Service account %q is allowed to do everything", user))
 
 	default:
 		if isReasonRequired {
 			if doesReasonExist {
-				if reasonIsAllowed(allowedReasons, reasonMessage) {
-					log.Info(fmt.Sprintf("%s node approved", operation), "User", user, "Reason", reasonMessage)
-					return admission.Allowed(fmt.Sprintf("%s operation has been approved", operation))
+				if code, message := validateReason(policy, reasonMessage); code != "" {
+					logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: code, Reason: reasonMessage, Grounds: "invalid reason"})
+					return deniedWithDetail(DenialDetail{
+						Code:           code,
+						Operation:      operation,
+						User:           user,
+						Reason:         reasonMessage,
+						AllowedReasons: policy.AllowedReasons,
+						Pattern:        policy.ReasonRegexPattern,
+						Message:        message,
+					})
 				}
-				log.Info(fmt.Sprintf("%s node denied", operation), "DenialReason", "invalid reason", "User", user, "Reason", reasonMessage)
-				return admission.Denied(fmt.Sprintf("Invalid reason %q. Allowed reasons: %v", reasonMessage, allowedReasons))
+				logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionAllowed, Reason: reasonMessage, Grounds: "valid reason"})
+				return admission.Allowed(fmt.Sprintf("%s operation has been approved", operation))
 			} else {
-				log.Info(fmt.Sprintf("%s node denied", operation), "DenialReason", "reason annotation doesn't exist", "User", user)
-				return admission.Denied(fmt.Sprintf("You must add %q annotation", reasonAnnotation))
+				logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: MissingReasonCode, Grounds: "reason annotation doesn't exist"})
+				return deniedWithDetail(DenialDetail{
+					Code:           MissingReasonCode,
+					Operation:      operation,
+					User:           user,
+					AllowedReasons: policy.AllowedReasons,
+					Pattern:        policy.ReasonRegexPattern,
+					Message:        fmt.Sprintf("You must add %q annotation", ReasonAnnotationKey()),
+				})
 			}
 		} else {
-			return validateNoReason(doesReasonExist, log, operation, user)
+			return validateNoReason(doesReasonExist, log, operation, nodeName, user)
+		}
+	}
+}
+
+// checkUserAccess denies the operations of the forbidden users, and the operations which aren't in the allowlist of the
+// user unless the user is a service account of a trusted namespace. It returns false if the operation isn't denied.
+func checkUserAccess(operation Operation, nodeName string, user string, groups []string, policy Policy, reasonMessage string, log logr.Logger) (admission.Response, bool) {
+	switch {
+	case isForbidden(user, groups, policy):
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: ForbiddenUserCode, Reason: reasonMessage, Grounds: "forbidden user"})
+		return deniedWithDetail(DenialDetail{
+			Code:      ForbiddenUserCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("%q user is not allowed to %s a node. Please log in with a LDAP privileged user. You must also add %q annotation", user, operation, ReasonAnnotationKey()),
+		}), true
+
+	case !isServiceAccountInTrustedNamespace(user, policy.serviceAccountPrefix(), policy.TrustedServiceAccountNamespaces) &&
+		!isOperationAllowedForUser(policy.OperationAllowlist, user, groups, operation):
+		allowedOperations, _ := getAllowedOperationsForUser(policy.OperationAllowlist, user, groups)
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: OperationNotAllowedCode, Reason: reasonMessage, Grounds: "operation not in allowlist"})
+		return deniedWithDetail(DenialDetail{
+			Code:      OperationNotAllowedCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("%q user is only allowed to perform the following operations on nodes: %v", user, allowedOperations),
+		}), true
+	}
+	return admission.Response{}, false
+}
+
+// validateReason checks the reason against the policy. If the reason isn't valid, it returns
+// the denial code and message. Otherwise, it returns empty strings.
+func validateReason(policy Policy, reason string) (string, string) {
+	if pattern, ok := forbiddenReasonPattern(policy.ForbiddenReasonPatterns, reason); ok {
+		return PlaceholderReasonCode, fmt.Sprintf("The %q annotation %q looks like a template placeholder, since it matches %q", ReasonAnnotationKey(), reason, pattern)
+	}
+	if !isReasonFreetext(policy, reason) && !ReasonIsAllowed(policy.AllowedReasons, reason) && !ReasonMatchesPattern(policy.ReasonRegexPattern, reason) {
+		return InvalidReasonCode, invalidReasonMessage(policy, reason)
+	}
+	if !reasonMeetsLengthRequirements(reason, policy.ReasonMinLength, policy.ReasonMaxLength) {
+		return InvalidReasonLengthCode, fmt.Sprintf("The %q annotation must be %s long", ReasonAnnotationKey(), reasonLengthRange(policy.ReasonMinLength, policy.ReasonMaxLength))
+	}
+	if policy.ValidateReasonFormat {
+		if issues := reasonFormatIssues(reason); len(issues) > 0 {
+			return InvalidReasonFormatCode, fmt.Sprintf("The %q annotation has formatting issues: %s", ReasonAnnotationKey(), strings.Join(issues, ", "))
 		}
 	}
+	return "", ""
+}
+
+// invalidReasonMessage returns the denial message for a reason which is not allowed by the policy,
+// suggesting the closest allowed reason if there is one.
+func invalidReasonMessage(policy Policy, reasonMessage string) string {
+	message := fmt.Sprintf("Invalid reason %q. Allowed reasons: %v", reasonMessage, policy.AllowedReasons)
+	if policy.ReasonRegexPattern != "" {
+		message = fmt.Sprintf("Invalid reason %q. Allowed reasons: %v, or reasons matching %q", reasonMessage, policy.AllowedReasons, policy.ReasonRegexPattern)
+	}
+	if suggestion := suggestClosestReason(reasonMessage, policy.AllowedReasons, policy.ReasonSuggestionMaxDistance); suggestion != "" {
+		message += fmt.Sprintf(". Did you mean: '%s'?", suggestion)
+	}
+	return message
 }
 
 // validateNoReason checks if reason annotation exists when doing an operation.
 // If the reason exists, it denies the request. If it doesn't - the operation is approved and logged.
-func validateNoReason(doesReasonExist bool, log logr.Logger, operation Operation, user string) admission.Response {
+func validateNoReason(doesReasonExist bool, log logr.Logger, operation Operation, nodeName string, user string) admission.Response {
 	if doesReasonExist {
-		log.Info(fmt.Sprintf("%s node denied", operation), "DenialReason", "reason annotation exists", "User", user)
-		return admission.Denied(fmt.Sprintf("Don't forget to remove the %q annotation from the node", reasonAnnotation))
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionDenied, DenialCode: UnexpectedReasonCode, Grounds: "reason annotation exists"})
+		return deniedWithDetail(DenialDetail{
+			Code:      UnexpectedReasonCode,
+			Operation: operation,
+			User:      user,
+			Message:   fmt.Sprintf("Don't forget to remove the %q annotation from the node", ReasonAnnotationKey()),
+		})
 	} else {
-		log.Info(fmt.Sprintf("%s node approved", operation), "User", user)
+		logDecision(log, decisionLog{Node: nodeName, User: user, Operation: operation, Decision: decisionAllowed, Grounds: "no reason required"})
 		return admission.Allowed("Operation approved")
 	}
 }
 
-// isServiceAccount returns true if the given user is a service account.
-func isServiceAccount(user string) bool {
-	return strings.HasPrefix(user, serviceAccountUser)
+// isServiceAccount returns true if the given user is a service account, whose username has the given prefix.
+func isServiceAccount(user string, prefix string) bool {
+	return strings.HasPrefix(user, prefix)
+}
+
+// isServiceAccountInTrustedNamespace returns true if the given user is a service account, formatted as
+// <prefix><namespace>:<name>, of a trusted namespace. The service accounts of kube-system are always
+// trusted, and those of all namespaces are trusted if the trusted namespaces are empty or contain "*".
+func isServiceAccountInTrustedNamespace(user string, prefix string, trustedNamespaces []string) bool {
+	namespaceAndName, ok := strings.CutPrefix(user, prefix)
+	if !ok {
+		return false
+	}
+	namespace, _, _ := strings.Cut(namespaceAndName, ":")
+	return namespace == metav1.NamespaceSystem || len(trustedNamespaces) == 0 ||
+		slices.Contains(trustedNamespaces, "*") || slices.Contains(trustedNamespaces, namespace)
+}
+
+// getForbiddenUsers returns the union of the comma separated forbidden users of the environment variable
+// and of the policy, without duplicates and empty values.
+func getForbiddenUsers(envValue string, cmValue string) []string {
+	var forbiddenUsers []string
+	for _, user := range strings.Split(envValue+","+cmValue, ",") {
+		user = strings.TrimSpace(user)
+		if user != "" && !slices.Contains(forbiddenUsers, user) {
+			forbiddenUsers = append(forbiddenUsers, user)
+		}
+	}
+	return forbiddenUsers
 }
 
 // isForbiddenUser checks if the given user is in the list of forbidden users.
@@ -152,24 +738,23 @@ func isForbiddenUser(userToCheck string, forbiddenUsers []string) bool {
 	return false
 }
 
-// getAllowedReasons fetches the allowed reasons from the ConfigMap.
-func (n *NodeValidator) getAllowedReasons(ctx context.Context, namespace string, logger logr.Logger) ([]string, error) {
-	configMapReasons := corev1.ConfigMap{}
-	if err := n.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: cmName}, &configMapReasons); err != nil {
-		logger.Error(err, "Failed to fetch ConfigMap", "Namespace", namespace, "Name", cmName)
-		return nil, fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", namespace, cmName, err)
+// isForbiddenGroup checks if any of the given groups is in the list of forbidden groups.
+func isForbiddenGroup(groups []string, forbiddenGroups []string) bool {
+	for _, group := range groups {
+		if slices.Contains(forbiddenGroups, group) {
+			return true
+		}
 	}
+	return false
+}
 
-	allowedReasons, ok := configMapReasons.Data["allowedReasons"]
-	if !ok {
-		return nil, fmt.Errorf("ConfigMap %s/%s does not contain 'allowedReasons' key", namespace, cmName)
-	}
-	reasons := strings.Split(allowedReasons, ",")
-	return reasons, nil
+// isForbidden checks if the given user, or any of its groups, is forbidden by the policy.
+func isForbidden(user string, groups []string, policy Policy) bool {
+	return isForbiddenUser(user, policy.ForbiddenUsers) || isForbiddenGroup(groups, policy.ForbiddenGroups)
 }
 
-// reasonIsAllowed checks if the reason message exists in the allowed reasons list.
-func reasonIsAllowed(allowedReasons []string, reason string) bool {
+// ReasonIsAllowed checks if the reason message exists in the allowed reasons list.
+func ReasonIsAllowed(allowedReasons []string, reason string) bool {
 	for _, allowedReason := range allowedReasons {
 		if strings.EqualFold(allowedReason, reason) {
 			return true
@@ -177,3 +762,57 @@ func reasonIsAllowed(allowedReasons []string, reason string) bool {
 	}
 	return false
 }
+
+// ReasonMatchesPattern checks if the reason message matches the reason regex pattern.
+// An empty pattern doesn't match any reason.
+func ReasonMatchesPattern(pattern string, reason string) bool {
+	if pattern == "" {
+		return false
+	}
+	compiledPattern, err := compilePattern(pattern)
+	return err == nil && compiledPattern.MatchString(reason)
+}
+
+// compiledPatterns caches the compiled reason patterns by their expression, so that a pattern
+// is only compiled again when the policy defining it changes.
+var compiledPatterns sync.Map
+
+// compilePattern returns the compiled reason pattern. An empty pattern compiles to nil.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if compiledPattern, ok := compiledPatterns.Load(pattern); ok {
+		return compiledPattern.(*regexp.Regexp), nil
+	}
+	compiledPattern, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledPatterns.Store(pattern, compiledPattern)
+	return compiledPattern, nil
+}
+
+// reasonMeetsLengthRequirements checks if the length of the reason is within the given range.
+// A non-positive min or max means the corresponding bound isn't enforced.
+func reasonMeetsLengthRequirements(reason string, min, max int) bool {
+	length := utf8.RuneCountInString(reason)
+	return (min <= 0 || length >= min) && (max <= 0 || length <= max)
+}
+
+// reasonLengthRange describes the allowed length range of a reason.
+func reasonLengthRange(min, max int) string {
+	switch {
+	case min > 0 && max > 0:
+		return fmt.Sprintf("between %d and %d characters", min, max)
+	case min > 0:
+		return fmt.Sprintf("at least %d characters", min)
+	default:
+		return fmt.Sprintf("at most %d characters", max)
+	}
+}
+
+// isReasonFreetext checks if the policy allows any reason, and the reason isn't empty.
+func isReasonFreetext(policy Policy, reason string) bool {
+	return policy.AllowFreetextReason && strings.TrimSpace(reason) != ""
+}
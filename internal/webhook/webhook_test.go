@@ -3,10 +3,13 @@ package webhook
 import (
 	"context"
 	"encoding/json"
-	"os"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/authentication/v1"
@@ -14,19 +17,32 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	testclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 const (
-	regularUserExample = "user"
+	regularUserExample      = "user"
+	envForbiddenUserExample = "env-forbidden-user"
+	cmForbiddenUserExample  = "cm-forbidden-user"
 )
 
+// fakeClock is a Clock returning a settable time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
 func newScheme() *runtime.Scheme {
 	s := runtime.NewScheme()
 	_ = corev1.AddToScheme(s)
 	_ = scheme.AddToScheme(s)
+	_ = v1alpha1.AddToScheme(s)
 	return s
 }
 
@@ -53,10 +69,14 @@ func TestNodeWebhook(t *testing.T) {
 		{name: "CordonAsUserWithoutReason", operation: "cordon", user: regularUserExample, reason: "", allowed: false},
 		{name: "CordonAsUserWithReason", operation: "cordon", user: regularUserExample, reason: "Testing", allowed: true},
 		{name: "CordonAsServiceAccountWithoutReason", operation: "cordon", user: serviceAccountUser + "openshift-machine-config-operator:machine-config-daemon", reason: "", allowed: true},
+		{name: "CordonAsNodeUserWithoutReason", operation: "cordon", user: "system:node:worker-1", reason: "", allowed: false},
+		{name: "DeleteAsNodeUserWithoutReason", operation: admissionv1.Delete, user: "system:node:worker-1", reason: "", allowed: false},
 		{name: "UncordonAsKubeadminWithoutReason", operation: "uncordon", user: systemAdminUser, reason: "", allowed: false},
 		{name: "UncordonAsUserWithReason", operation: "uncordon", user: regularUserExample, reason: "Testing", allowed: false},
 		{name: "UncordonAsUserWithoutReason", operation: "uncordon", user: regularUserExample, reason: "", allowed: true},
 		{name: "UncordonAsServiceAccountWithReason", operation: "uncordon", user: serviceAccountUser + "openshift-machine-config-operator:machine-config-daemon", reason: "testing", allowed: true},
+		{name: "CordonAsEnvForbiddenUserWithReason", operation: "cordon", user: envForbiddenUserExample, reason: "Testing", allowed: false},
+		{name: "CordonAsConfigMapForbiddenUserWithReason", operation: "cordon", user: cmForbiddenUserExample, reason: "Testing", allowed: false},
 	}
 	fakeClient := newFakeClient()
 
@@ -72,6 +92,7 @@ func TestNodeWebhook(t *testing.T) {
 				"Invalid configuration",
 				"Dependency error",
 			}, ","),
+			forbiddenUsersKey: cmForbiddenUserExample,
 		},
 	}
 	err := fakeClient.Create(context.Background(), mockConfigMap)
@@ -84,10 +105,7 @@ func TestNodeWebhook(t *testing.T) {
 	decoder := admission.NewDecoder(scheme.Scheme)
 	nv := NodeValidator{Decoder: decoder, Client: fakeClient}
 
-	err = os.Setenv(ForbiddenUsersEnv, systemAdminUser)
-	if err != nil {
-		print(err)
-	}
+	t.Setenv(ForbiddenUsersEnv, envForbiddenUserExample)
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			annotations := make(map[string]string)
@@ -180,3 +198,348 @@ func TestNodeWebhook(t *testing.T) {
 		})
 	}
 }
+
+// newCordonRequest returns an update request cordoning a node with the given annotations.
+func newCordonRequest(g *WithT, name string, user string, annotations map[string]string) admission.Request {
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	cordonedNode := *node.DeepCopy()
+	cordonedNode.Spec.Unschedulable = true
+	return newUpdateRequest(g, user, node, cordonedNode)
+}
+
+// newDeleteRequest returns a request deleting a node with the given annotations.
+func newDeleteRequest(g *WithT, name string, user string, annotations map[string]string) admission.Request {
+	nodeObj, err := json.Marshal(corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}})
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Name: name,
+		Operation: admissionv1.Delete,
+		UserInfo:  v1.UserInfo{Username: user},
+		Kind:      metav1.GroupVersionKind{Kind: "Node", Group: "core", Version: "v1"},
+		OldObject: runtime.RawExtension{Raw: nodeObj}}}
+}
+
+// newUpdateRequest returns a request updating oldNode to node.
+func newUpdateRequest(g *WithT, user string, oldNode corev1.Node, node corev1.Node) admission.Request {
+	oldNodeObj, err := json.Marshal(oldNode)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	nodeObj, err := json.Marshal(node)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Name: node.Name,
+		Operation: admissionv1.Update,
+		UserInfo:  v1.UserInfo{Username: user},
+		Kind:      metav1.GroupVersionKind{Kind: "Node", Group: "core", Version: "v1"},
+		OldObject: runtime.RawExtension{Raw: oldNodeObj},
+		Object:    runtime.RawExtension{Raw: nodeObj}}}
+}
+
+func TestWarnOnly(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		allowed      bool
+		warningCount int
+	}{
+		{name: "WarnOnlyEnabled", data: map[string]string{allowedReasonsKey: "Testing", warnOnlyKey: "true"}, allowed: true, warningCount: 1},
+		{name: "WarnOnlyAbsent", data: map[string]string{allowedReasonsKey: "Testing"}, allowed: false, warningCount: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, nil))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			g.Expect(response.Warnings).Should(HaveLen(test.warningCount))
+			if test.warningCount > 0 {
+				g.Expect(response.Warnings[0]).Should(ContainSubstring(reasonAnnotation))
+			}
+		})
+	}
+}
+
+func TestReasonLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		reason  string
+		allowed bool
+	}{
+		{name: "TooShort", data: map[string]string{reasonMinLengthKey: "5", reasonMaxLengthKey: "10"}, reason: "x", allowed: false},
+		{name: "TooLong", data: map[string]string{reasonMinLengthKey: "5", reasonMaxLengthKey: "10"}, reason: strings.Repeat("x", 11), allowed: false},
+		{name: "ExactMinimum", data: map[string]string{reasonMinLengthKey: "5", reasonMaxLengthKey: "10"}, reason: strings.Repeat("x", 5), allowed: true},
+		{name: "ExactMaximum", data: map[string]string{reasonMinLengthKey: "5", reasonMaxLengthKey: "10"}, reason: strings.Repeat("x", 10), allowed: true},
+		{name: "Unconfigured", data: map[string]string{}, reason: "x", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+
+			test.data[reasonRegexPatternKey] = ".*"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: test.reason}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+		})
+	}
+}
+
+func TestDefaultReason(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]string
+		annotations  map[string]string
+		allowed      bool
+		warningCount int
+	}{
+		{name: "DefaultReasonUsed", data: map[string]string{allowFreetextKey: "true", "delete" + defaultReasonSuffix: "automated operation"}, allowed: true, warningCount: 1},
+		{name: "AnnotationPreferred", data: map[string]string{allowFreetextKey: "true", "delete" + defaultReasonSuffix: "automated operation"}, annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: true, warningCount: 0},
+		{name: "FreetextDisabled", data: map[string]string{allowedReasonsKey: "Testing", "delete" + defaultReasonSuffix: "automated operation"}, allowed: false, warningCount: 0},
+		{name: "NoDefaultForOperation", data: map[string]string{allowFreetextKey: "true", "cordon" + defaultReasonSuffix: "automated operation"}, allowed: false, warningCount: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			recorder := record.NewFakeRecorder(10)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Recorder: recorder}
+
+			response := nv.Handle(ctx, newDeleteRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			g.Expect(response.Warnings).Should(HaveLen(test.warningCount))
+			if test.warningCount > 0 {
+				g.Expect(recorder.Events).Should(Receive(ContainSubstring("automated operation")))
+			}
+		})
+	}
+}
+
+func TestReasonAnnotationKeyEnv(t *testing.T) {
+	const customKey = "ops.company.io/change-ticket"
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		allowed     bool
+		message     string
+	}{
+		{name: "CustomKey", annotations: map[string]string{customKey: "Testing"}, allowed: true},
+		{name: "CustomKeyInvalidReason", annotations: map[string]string{customKey: "for fun"}, allowed: false},
+		{name: "DefaultKey", annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: false, message: fmt.Sprintf("You must add %q annotation", customKey)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(ReasonAnnotationKeyEnv, customKey)
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, test.annotations))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.message != "" {
+				g.Expect(denialDetail(g, response).Message).Should(Equal(test.message))
+			}
+		})
+	}
+}
+
+func TestDryRunHandle(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", denialGracePeriodKey: "cordon=60"},
+	})).Should(Succeed())
+	recorder := record.NewFakeRecorder(10)
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Clock: &fakeClock{now: time.Now()}, Recorder: recorder}
+
+	allowed, reason, err := nv.DryRunHandle(ctx, newCordonRequest(g, "dry-run", regularUserExample, nil))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(allowed).Should(BeFalse())
+	g.Expect(reason).Should(ContainSubstring(reasonAnnotation))
+	g.Expect(recorder.Events).ShouldNot(Receive())
+
+	// The dry run neither records nor consumes the denial.
+	response := nv.Handle(ctx, newCordonRequest(g, "dry-run", regularUserExample, nil))
+	g.Expect(response.Allowed).Should(BeFalse())
+	g.Expect(recorder.Events).Should(Receive())
+	for range 2 {
+		allowed, _, err = nv.DryRunHandle(ctx, newCordonRequest(g, "dry-run", regularUserExample, map[string]string{}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(allowed).Should(BeTrue())
+	}
+	g.Expect(recorder.Events).ShouldNot(Receive())
+	response = nv.Handle(ctx, newCordonRequest(g, "dry-run", regularUserExample, map[string]string{}))
+	g.Expect(response.Allowed).Should(BeTrue())
+
+	_, _, err = nv.DryRunHandle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Name: "dry-run", Operation: admissionv1.Delete}})
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestDryRunMode(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing"},
+	})).Should(Succeed())
+	recorder := record.NewFakeRecorder(10)
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Recorder: recorder, DryRun: true}
+
+	response := nv.Handle(ctx, newCordonRequest(g, "dry-run-mode", regularUserExample, map[string]string{reasonAnnotation: "for fun"}))
+	g.Expect(response.Allowed).Should(BeTrue())
+	g.Expect(response.Warnings).Should(ConsistOf(ContainSubstring("Invalid reason")))
+	g.Expect(recorder.Events).Should(Receive(And(
+		HavePrefix(corev1.EventTypeWarning+" "+dryRunEventPrefix+operationDeniedEvent),
+		ContainSubstring("Invalid reason"),
+	)))
+
+	response = nv.Handle(ctx, newCordonRequest(g, "dry-run-mode", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+	g.Expect(response.Allowed).Should(BeTrue())
+	g.Expect(response.Warnings).Should(BeEmpty())
+	g.Expect(recorder.Events).Should(Receive(HavePrefix(corev1.EventTypeNormal + " " + operationApprovedEvent)))
+}
+
+func TestDryRunRequest(t *testing.T) {
+	dryRun, notDryRun := true, false
+	tests := []struct {
+		name        string
+		dryRun      *bool
+		annotations map[string]string
+		allowed     bool
+		event       bool
+	}{
+		{name: "DryRunAllowed", dryRun: &dryRun, annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: true},
+		{name: "DryRunDenied", dryRun: &dryRun, annotations: map[string]string{reasonAnnotation: "for fun"}, allowed: false},
+		{name: "NotDryRun", dryRun: &notDryRun, annotations: map[string]string{reasonAnnotation: "Testing"}, allowed: true, event: true},
+		{name: "DryRunUnset", annotations: map[string]string{reasonAnnotation: "for fun"}, allowed: false, event: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       map[string]string{allowedReasonsKey: "Testing"},
+			})).Should(Succeed())
+			recorder := record.NewFakeRecorder(10)
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient, Recorder: recorder}
+
+			request := newCordonRequest(g, test.name, regularUserExample, test.annotations)
+			request.DryRun = test.dryRun
+			response := nv.Handle(ctx, request)
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.event {
+				g.Expect(recorder.Events).Should(Receive())
+			} else {
+				g.Expect(recorder.Events).ShouldNot(Receive())
+			}
+		})
+	}
+}
+
+func TestReasonPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		reason  string
+		allowed bool
+		errored bool
+	}{
+		{name: "InvalidPattern", data: map[string]string{reasonRegexPatternKey: "^JIRA-[0-9+$"}, reason: "JIRA-1", allowed: false, errored: true},
+		{name: "InvalidDrainPattern", data: map[string]string{reasonRegexPatternKey: "^JIRA-[0-9]+$", drainPatternKey: "(unclosed"}, reason: "JIRA-1", allowed: false, errored: true},
+		{name: "MatchingReason", data: map[string]string{reasonRegexPatternKey: "^JIRA-[0-9]+$"}, reason: "JIRA-1", allowed: true},
+		{name: "NonMatchingReason", data: map[string]string{reasonRegexPatternKey: "^JIRA-[0-9]+$"}, reason: "JIRA-one", allowed: false},
+		{name: "EmptyPattern", data: map[string]string{allowedReasonsKey: "Testing", reasonRegexPatternKey: ""}, reason: "Testing", allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+			response := nv.Handle(ctx, newCordonRequest(g, test.name, regularUserExample, map[string]string{reasonAnnotation: test.reason}))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if test.errored {
+				g.Expect(response.Result.Code).Should(Equal(int32(http.StatusInternalServerError)))
+			}
+		})
+	}
+}
+
+func TestGetForbiddenUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		cmValue  string
+		expected []string
+	}{
+		{name: "Union", envValue: "first,second", cmValue: "third", expected: []string{"first", "second", "third"}},
+		{name: "Duplicates", envValue: "first,second", cmValue: "second, first", expected: []string{"first", "second"}},
+		{name: "EmptyEnv", envValue: "", cmValue: "first", expected: []string{"first"}},
+		{name: "EmptyConfigMap", envValue: "first", cmValue: "", expected: []string{"first"}},
+		{name: "Empty", envValue: "", cmValue: "", expected: nil},
+		{name: "EmptyValues", envValue: ",first,,", cmValue: " ,", expected: []string{"first"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getForbiddenUsers(test.envValue, test.cmValue)).Should(Equal(test.expected))
+		})
+	}
+}
+
+func TestConfigMapForbiddenUsersWithoutEnv(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	t.Setenv(ForbiddenUsersEnv, "")
+	fakeClient := newFakeClient()
+	g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+		Data:       map[string]string{allowedReasonsKey: "Testing", forbiddenUsersKey: cmForbiddenUserExample},
+	})).Should(Succeed())
+	nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+
+	response := nv.Handle(ctx, newCordonRequest(g, "ForbiddenUser", cmForbiddenUserExample, map[string]string{reasonAnnotation: "Testing"}))
+	g.Expect(response.Allowed).Should(BeFalse())
+	response = nv.Handle(ctx, newCordonRequest(g, "RegularUser", regularUserExample, map[string]string{reasonAnnotation: "Testing"}))
+	g.Expect(response.Allowed).Should(BeTrue())
+}
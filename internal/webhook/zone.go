@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const defaultZoneLabel = "topology.kubernetes.io/zone"
+
+// validateZoneCordonLimit denies a cordon or a drain which would bring the number of cordoned nodes in the zone of
+// the node over the maximum of the policy. In warn only mode, the denial message is added to the warnings of the given
+// response. Otherwise, the given response is returned as is.
+func (n *NodeValidator) validateZoneCordonLimit(ctx context.Context, operation Operation, node *corev1.Node, user string, policy Policy, log logr.Logger, response admission.Response) admission.Response {
+	if policy.MaxCordonedNodesPerZone <= 0 {
+		return response
+	}
+
+	zoneLabel := policy.ZoneLabel
+	if zoneLabel == "" {
+		zoneLabel = defaultZoneLabel
+	}
+	zone, ok := node.Labels[zoneLabel]
+	if !ok {
+		return response
+	}
+
+	cordonedNodes, err := n.countCordonedNodes(ctx, zoneLabel, zone, node.Name)
+	if err != nil {
+		log.Error(err, "Failed to count cordoned nodes", "Zone", zone)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to count cordoned nodes in zone %q: %w", zone, err))
+	}
+	if cordonedNodes < policy.MaxCordonedNodesPerZone {
+		return response
+	}
+
+	logDecision(log, decisionLog{Node: node.Name, User: user, Operation: operation, Decision: decisionDenied, DenialCode: ZoneCordonLimitCode,
+		Grounds: "zone cordon limit reached", Details: []any{"Zone", zone, "CordonedNodes", cordonedNodes}})
+	return denyApproved(policy, response, DenialDetail{
+		Code:      ZoneCordonLimitCode,
+		Operation: operation,
+		User:      user,
+		Message:   fmt.Sprintf("%d nodes are already cordoned in zone %q, which is the maximum allowed", cordonedNodes, zone),
+	})
+}
+
+// countCordonedNodes returns the number of cordoned nodes in the given zone, excluding the node with the given name.
+func (n *NodeValidator) countCordonedNodes(ctx context.Context, zoneLabel string, zone string, nodeName string) (int, error) {
+	nodes := corev1.NodeList{}
+	if err := n.Client.List(ctx, &nodes, client.MatchingLabels{zoneLabel: zone}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, zoneNode := range nodes.Items {
+		if zoneNode.Name != nodeName && zoneNode.Spec.Unschedulable {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestZoneCordonLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          map[string]string
+		cordonedNodes int
+		zoneLabel     string
+		drain         bool
+		allowed       bool
+	}{
+		{name: "BelowLimit", data: map[string]string{maxCordonedPerZoneKey: "2"}, cordonedNodes: 1, zoneLabel: defaultZoneLabel, allowed: true},
+		{name: "LimitReached", data: map[string]string{maxCordonedPerZoneKey: "2"}, cordonedNodes: 2, zoneLabel: defaultZoneLabel, allowed: false},
+		{name: "NoLimit", data: map[string]string{}, cordonedNodes: 5, zoneLabel: defaultZoneLabel, allowed: true},
+		{name: "DrainLimitReached", data: map[string]string{maxCordonedPerZoneKey: "2"}, cordonedNodes: 2, zoneLabel: defaultZoneLabel, drain: true, allowed: false},
+		{name: "CustomZoneLabel", data: map[string]string{maxCordonedPerZoneKey: "1", zoneLabelKey: "example.com/zone"}, cordonedNodes: 1, zoneLabel: "example.com/zone", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			fakeClient := newFakeClient()
+
+			test.data[allowedReasonsKey] = "Testing"
+			g.Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: cmName, Namespace: cmNamespace},
+				Data:       test.data,
+			})).Should(Succeed())
+
+			for i := 0; i < test.cordonedNodes; i++ {
+				g.Expect(fakeClient.Create(ctx, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("cordoned-%d", i), Labels: map[string]string{test.zoneLabel: "zone-a"}},
+					Spec:       corev1.NodeSpec{Unschedulable: true},
+				})).Should(Succeed())
+			}
+			g.Expect(fakeClient.Create(ctx, &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-zone", Labels: map[string]string{test.zoneLabel: "zone-b"}},
+				Spec:       corev1.NodeSpec{Unschedulable: true},
+			})).Should(Succeed())
+
+			nv := NodeValidator{Decoder: admission.NewDecoder(scheme.Scheme), Client: fakeClient}
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:        test.name,
+				Labels:      map[string]string{test.zoneLabel: "zone-a"},
+				Annotations: map[string]string{reasonAnnotation: "Testing"},
+			}}
+			cordonedNode := *node.DeepCopy()
+			cordonedNode.Spec.Unschedulable = true
+			operation := Cordon
+			if test.drain {
+				cordonedNode.Annotations[drainRequestedAnnotation] = "true"
+				operation = Drain
+			}
+
+			response := nv.Handle(ctx, newUpdateRequest(g, regularUserExample, node, cordonedNode))
+			g.Expect(response.Allowed).Should(Equal(test.allowed))
+			if !test.allowed {
+				detail := denialDetail(g, response)
+				g.Expect(detail.Code).Should(Equal(ZoneCordonLimitCode))
+				g.Expect(detail.Operation).Should(Equal(operation))
+			}
+		})
+	}
+}
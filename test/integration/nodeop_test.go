@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/dana-team/node-operation-validator/internal/nodeop"
+)
+
+// newOperator returns an operator of the kubectl node-op plugin talking to the test environment and reading the input.
+func newOperator(g *WithT, input string) *nodeop.Operator {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return &nodeop.Operator{Client: clientset, In: strings.NewReader(input), Out: &bytes.Buffer{}, AnnotationKey: reasonAnnotation}
+}
+
+func TestNodeOpCordonAndUncordon(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "node-op-cordon")
+	operator := newOperator(g, "Testing\n")
+
+	g.Expect(operator.Cordon(ctx, node.Name, nodeop.Options{})).Should(Succeed())
+	g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node)).Should(Succeed())
+	g.Expect(node.Spec.Unschedulable).Should(BeTrue())
+	g.Expect(node.Annotations).Should(HaveKeyWithValue(reasonAnnotation, "Testing"))
+
+	g.Expect(operator.Uncordon(ctx, node.Name)).Should(Succeed())
+	g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node)).Should(Succeed())
+	g.Expect(node.Spec.Unschedulable).Should(BeFalse())
+	g.Expect(node.Annotations).ShouldNot(HaveKey(reasonAnnotation))
+}
+
+func TestNodeOpInvalidReason(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "node-op-invalid")
+
+	g.Expect(newOperator(g, "").Cordon(ctx, node.Name, nodeop.Options{Reason: "for fun"})).Should(MatchError(ContainSubstring("invalid reason")))
+	g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node)).Should(Succeed())
+	g.Expect(node.Spec.Unschedulable).Should(BeFalse())
+	g.Expect(node.Annotations).ShouldNot(HaveKey(reasonAnnotation))
+}
+
+func TestNodeOpDelete(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "node-op-delete")
+
+	g.Expect(newOperator(g, "").Delete(ctx, node.Name, nodeop.Options{Reason: "Testing"})).Should(Succeed())
+	g.Eventually(func() error {
+		return k8sClient.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{})
+	}, cacheSyncTimeout).Should(MatchError(ContainSubstring("not found")))
+}
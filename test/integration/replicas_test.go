@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nodewebhook "github.com/dana-team/node-operation-validator/internal/webhook"
+)
+
+// pausableWatches holds back the events of the watches of a client while paused, like the informers of a replica
+// lagging behind the API server.
+type pausableWatches struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+// Pause holds back the events received from now on.
+func (p *pausableWatches) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed == nil {
+		p.resumed = make(chan struct{})
+	}
+}
+
+// Resume delivers the events held back, and the later ones.
+func (p *pausableWatches) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed != nil {
+		close(p.resumed)
+		p.resumed = nil
+	}
+}
+
+func (p *pausableWatches) wait() {
+	p.mu.Lock()
+	resumed := p.resumed
+	p.mu.Unlock()
+	if resumed != nil {
+		<-resumed
+	}
+}
+
+// wrap returns a round tripper whose watch responses are held back while paused.
+func (p *pausableWatches) wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := rt.RoundTrip(req)
+		if err == nil && req.URL.Query().Get("watch") == "true" {
+			resp.Body = &pausableBody{ReadCloser: resp.Body, watches: p}
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// pausableBody is the body of a watch response, whose data read while paused is returned once resumed.
+type pausableBody struct {
+	io.ReadCloser
+	watches *pausableWatches
+}
+
+func (b *pausableBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.watches.wait()
+	return n, err
+}
+
+// newCachedClient returns a client reading from its own informer cache, like the client of the manager of a replica.
+func newCachedClient(ctx context.Context, g *WithT, config *rest.Config) client.Client {
+	informerCache, err := cache.New(config, cache.Options{Scheme: k8sClient.Scheme()})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	go func() {
+		_ = informerCache.Start(ctx)
+	}()
+	g.Expect(informerCache.WaitForCacheSync(ctx)).Should(BeTrue())
+
+	c, err := client.New(config, client.Options{Scheme: k8sClient.Scheme(), Cache: &client.CacheOptions{Reader: informerCache}})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	return c
+}
+
+// TestReplicasWithStaleCache runs two replicas of the validator reading the ConfigMap through their own informer
+// cache, one of which hasn't observed the update of the ConfigMap yet. The caches don't expire: the stale replica
+// applies the previous policy until its informer receives the update, and then converges.
+func TestReplicasWithStaleCache(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "stale-replica")
+
+	watches := &pausableWatches{}
+	defer watches.Resume()
+	staleConfig := rest.CopyConfig(restConfig)
+	staleConfig.Wrap(watches.wrap)
+	decoder := admission.NewDecoder(k8sClient.Scheme())
+	freshReplica := &nodewebhook.NodeValidator{Decoder: decoder, Client: newCachedClient(ctx, g, restConfig)}
+	staleReplica := &nodewebhook.NodeValidator{Decoder: decoder, Client: newCachedClient(ctx, g, staleConfig)}
+
+	// Both replicas read the ConfigMap once, so that their informers watch it.
+	request := newCordonRequest(g, node, "Upgrade")
+	for _, replica := range []*nodewebhook.NodeValidator{freshReplica, staleReplica} {
+		response := replica.Handle(ctx, request)
+		g.Expect(response.Allowed).Should(BeFalse())
+		g.Expect(response.Result.Message).Should(ContainSubstring("InvalidReason"))
+	}
+
+	watches.Pause()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing,Upgrade"})
+	g.Eventually(func() bool { return freshReplica.Handle(ctx, request).Allowed }, cacheSyncTimeout, 100*time.Millisecond).Should(BeTrue())
+	g.Consistently(func() string { return staleReplica.Handle(ctx, request).Result.Message }, time.Second, 100*time.Millisecond).
+		Should(ContainSubstring("InvalidReason"))
+
+	watches.Resume()
+	g.Eventually(func() bool { return staleReplica.Handle(ctx, request).Allowed }, cacheSyncTimeout, 100*time.Millisecond).Should(BeTrue())
+}
+
+// newCordonRequest returns an admission request cordoning the node with the reason.
+func newCordonRequest(g *WithT, node *corev1.Node, reason string) admission.Request {
+	cordonedNode := node.DeepCopy()
+	cordonedNode.Annotations = map[string]string{reasonAnnotation: reason}
+	cordonedNode.Spec.Unschedulable = true
+	oldObject, err := json.Marshal(node)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	object, err := json.Marshal(cordonedNode)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Name:      node.Name,
+		Operation: admissionv1.Update,
+		UserInfo:  authenticationv1.UserInfo{Username: "regular-user"},
+		Kind:      metav1.GroupVersionKind{Kind: "Node", Group: "", Version: "v1"},
+		OldObject: runtime.RawExtension{Raw: oldObject},
+		Object:    runtime.RawExtension{Raw: object},
+	}}
+}
@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/dana-team/node-operation-validator/api/v1alpha1"
+	nodewebhook "github.com/dana-team/node-operation-validator/internal/webhook"
+)
+
+const (
+	configMapName      = "node-operation-validator-config"
+	configMapNamespace = "node-operation-validator-system"
+)
+
+var (
+	// k8sClient talks to the API server of the test environment, so its requests go through the webhooks.
+	// It is nil when the test environment isn't available.
+	k8sClient client.Client
+	// restConfig is the configuration of the clients of the test environment.
+	restConfig *rest.Config
+)
+
+// TestMain boots an API server with the webhooks of config/webhook pointing at a webhook server serving
+// NodeValidator and NodeMutator, as cmd/main.go does. The test environment requires the binaries of
+// KUBEBUILDER_ASSETS, which `make test` downloads; without them, the tests are skipped.
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS isn't set, skipping the integration tests")
+		os.Exit(m.Run())
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "config", "webhook")},
+		},
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		fmt.Println("failed to start the test environment:", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	code, err := run(ctx, m, testEnv, cfg)
+	cancel()
+	if stopErr := testEnv.Stop(); stopErr != nil {
+		fmt.Println("failed to stop the test environment:", stopErr)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// run starts the manager serving the webhooks, waits for the webhook server to accept connections, and runs the tests.
+func run(ctx context.Context, m *testing.M, testEnv *envtest.Environment, cfg *rest.Config) (int, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return 0, err
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return 0, err
+	}
+
+	webhookOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookOptions.LocalServingHost,
+			Port:    webhookOptions.LocalServingPort,
+			CertDir: webhookOptions.LocalServingCertDir,
+		}),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create the manager: %w", err)
+	}
+
+	decoder := admission.NewDecoder(scheme)
+	validator := &nodewebhook.NodeValidator{
+		Decoder:   decoder,
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Recorder:  mgr.GetEventRecorderFor("node-operation-validator"),
+	}
+	hookServer := mgr.GetWebhookServer()
+	hookServer.Register("/validate-v1-node", &webhook.Admission{Handler: validator})
+	hookServer.Register("/mutate-v1-node", &webhook.Admission{Handler: &nodewebhook.NodeMutator{
+		Decoder:   decoder,
+		Client:    mgr.GetClient(),
+		Validator: validator,
+	}})
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- mgr.Start(ctx)
+	}()
+	if err := waitForWebhookServer(webhookOptions, errs); err != nil {
+		return 0, err
+	}
+
+	restConfig = cfg
+	if k8sClient, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+		return 0, fmt.Errorf("failed to create the client: %w", err)
+	}
+	if err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: configMapNamespace}}); err != nil {
+		return 0, fmt.Errorf("failed to create namespace %q: %w", configMapNamespace, err)
+	}
+	return m.Run(), nil
+}
+
+// waitForWebhookServer waits until the webhook server accepts TLS connections, or fails if the manager stops.
+func waitForWebhookServer(options *envtest.WebhookInstallOptions, errs <-chan error) error {
+	address := net.JoinHostPort(options.LocalServingHost, fmt.Sprint(options.LocalServingPort))
+	dialer := &net.Dialer{Timeout: time.Second}
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-errs:
+			return fmt.Errorf("the manager stopped: %w", err)
+		default:
+		}
+		conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec // a local test server
+		if err == nil {
+			return conn.Close()
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("the webhook server didn't start listening on %s", address)
+}
+
+// requireTestEnv skips the test if the test environment isn't available.
+func requireTestEnv(t *testing.T) {
+	t.Helper()
+	if k8sClient == nil {
+		t.Skip("the test environment requires KUBEBUILDER_ASSETS")
+	}
+}
@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	reasonAnnotation = "node.dana.io/reason"
+	// cacheSyncTimeout bounds the time the webhook takes to observe a ConfigMap change through the cache of the manager.
+	cacheSyncTimeout = 10 * time.Second
+)
+
+func TestCordonAllowed(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "cordon-allowed")
+
+	node.Annotations = map[string]string{reasonAnnotation: "Testing"}
+	node.Spec.Unschedulable = true
+	g.Expect(k8sClient.Update(ctx, node)).Should(Succeed())
+}
+
+func TestDeleteDeniedWithoutReason(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "delete-denied")
+
+	err := k8sClient.Delete(ctx, node)
+	g.Expect(err).Should(HaveOccurred())
+	g.Expect(err.Error()).Should(ContainSubstring("MissingReason"))
+	g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(node), &corev1.Node{})).Should(Succeed())
+}
+
+func TestConfigMapUpdate(t *testing.T) {
+	requireTestEnv(t)
+	g := NewWithT(t)
+	ctx := context.Background()
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing"})
+	node := createNode(ctx, t, g, "configmap-update")
+
+	cordon := func() error {
+		current := &corev1.Node{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(node), current); err != nil {
+			return err
+		}
+		current.Annotations = map[string]string{reasonAnnotation: "Upgrade"}
+		current.Spec.Unschedulable = true
+		return k8sClient.Update(ctx, current)
+	}
+	g.Eventually(cordon, cacheSyncTimeout, 100*time.Millisecond).Should(MatchError(ContainSubstring("InvalidReason")))
+
+	applyConfigMap(ctx, g, map[string]string{"allowedReasons": "Testing,Upgrade"})
+	g.Eventually(cordon, cacheSyncTimeout, 100*time.Millisecond).Should(Succeed())
+}
+
+// applyConfigMap creates the global ConfigMap with the given data, or replaces the data of the existing one.
+func applyConfigMap(ctx context.Context, g *WithT, data map[string]string) {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: configMapNamespace}}
+	err := k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if apierrors.IsNotFound(err) {
+		configMap.Data = data
+		g.Expect(k8sClient.Create(ctx, configMap)).Should(Succeed())
+		return
+	}
+	g.Expect(err).ShouldNot(HaveOccurred())
+	configMap.Data = data
+	g.Expect(k8sClient.Update(ctx, configMap)).Should(Succeed())
+}
+
+// createNode creates a node, which is deleted with a valid reason when the test ends.
+func createNode(ctx context.Context, t *testing.T, g *WithT, name string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	g.Expect(k8sClient.Create(ctx, node)).Should(Succeed())
+	t.Cleanup(func() {
+		g := NewWithT(t)
+		g.Eventually(func() error {
+			current := &corev1.Node{}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(node), current); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			current.Annotations = map[string]string{reasonAnnotation: "Testing"}
+			if err := k8sClient.Update(ctx, current); err != nil {
+				return err
+			}
+			return client.IgnoreNotFound(k8sClient.Delete(ctx, current))
+		}, cacheSyncTimeout, 100*time.Millisecond).Should(Succeed())
+	})
+	return node
+}